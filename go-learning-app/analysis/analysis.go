@@ -0,0 +1,250 @@
+// Package analysis computes classical item statistics for quiz questions
+// from recorded attempts, to help authors find questions that are too easy,
+// too hard, ambiguous or miskeyed.
+package analysis
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"strings"
+
+	"go-learning-app/data"
+	"go-learning-app/models"
+)
+
+// minResponses is how many responses a question needs before it can be
+// flagged as miskeyed; with fewer, a couple of guesses decide the flag.
+const minResponses = 5
+
+// topWrongAnswers is how many of the most common wrong answers are listed
+// for questions without options.
+const topWrongAnswers = 5
+
+// Item is the analysis of one question.
+type Item struct {
+	QuestionID string `json:"questionId"`
+	LessonID   string `json:"lessonId"`
+	Type       string `json:"type"`
+	Text       string `json:"text"`
+	Responses  int    `json:"responses"`
+	// Difficulty is the percentage of responses that were correct, so a
+	// higher value means an easier question.
+	Difficulty float64 `json:"difficulty"`
+	// Discrimination is the point-biserial correlation between answering
+	// the question correctly and the score on the rest of the attempt. It
+	// is nil when either side has no variance.
+	Discrimination *float64 `json:"discrimination"`
+	// Options counts how often each option was chosen, for choice questions.
+	Options []Option `json:"options,omitempty"`
+	// WrongAnswers lists the most common wrong answers, for questions
+	// without options.
+	WrongAnswers []Answer `json:"wrongAnswers,omitempty"`
+	// Miskey is set when a wrong option is chosen more often than a keyed
+	// one, which usually means the key or the wording is off. It needs at
+	// least minResponses responses.
+	Miskey bool `json:"miskey"`
+}
+
+// Option is how often one option of a choice question was chosen.
+type Option struct {
+	Index   int     `json:"index"`
+	Text    string  `json:"text"`
+	Keyed   bool    `json:"keyed"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+}
+
+// Answer is a distinct submitted answer and how often it was given.
+type Answer struct {
+	Answer string `json:"answer"`
+	Count  int    `json:"count"`
+}
+
+// Report analyzes the questions of one lesson quiz, or of every quiz in
+// course order when lessonID is empty, given the course's store and
+// records. Questions nobody has answered are included with zero responses.
+func Report(store *data.Store, db *data.CourseDB, lessonID string) ([]Item, error) {
+	var items []Item
+	for _, ch := range store.GetChapters() {
+		for _, l := range ch.Lessons {
+			if lessonID != "" && l.ID != lessonID {
+				continue
+			}
+			quiz, ok := store.GetQuiz(l.ID)
+			if !ok {
+				continue
+			}
+			attempts, err := db.GetLessonAttempts(l.ID)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, Analyze(quiz, attempts)...)
+		}
+	}
+	return items, nil
+}
+
+// Analyze computes item statistics for every question of quiz. Attempts
+// must store answers with option indexes as authored, as RecordAttempt does.
+func Analyze(quiz models.Quiz, attempts []models.QuizAttempt) []Item {
+	items := make([]Item, 0, len(quiz.Questions))
+	for _, q := range quiz.Questions {
+		items = append(items, analyzeQuestion(quiz.LessonID, q, attempts))
+	}
+	return items
+}
+
+func analyzeQuestion(lessonID string, q models.Question, attempts []models.QuizAttempt) Item {
+	item := Item{QuestionID: q.ID, LessonID: lessonID, Type: q.Kind(), Text: q.Text}
+
+	var correct, rest []float64
+	optionCounts := make([]int, len(q.Options))
+	wrong := make(map[string]int)
+	for _, a := range attempts {
+		i := slices.IndexFunc(a.Answers, func(ans models.AttemptAnswer) bool { return ans.QuestionID == q.ID })
+		if i < 0 {
+			continue
+		}
+		ans := a.Answers[i]
+		item.Responses++
+
+		c := 0.0
+		if ans.Correct {
+			c = 1
+		}
+		correct = append(correct, c)
+		if others := len(a.Answers) - 1; others > 0 {
+			rest = append(rest, (float64(a.Correct)-c)/float64(others))
+		} else {
+			rest = append(rest, 0)
+		}
+
+		if len(q.Options) > 0 {
+			for _, j := range chosenOptions(ans.Answer) {
+				if j >= 0 && j < len(optionCounts) {
+					optionCounts[j]++
+				}
+			}
+		} else if !ans.Correct {
+			if text := answerText(ans.Answer); text != "" {
+				wrong[text]++
+			}
+		}
+	}
+
+	if item.Responses == 0 {
+		return item
+	}
+	item.Difficulty = round1(sum(correct) * 100 / float64(item.Responses))
+	if r, ok := correlation(correct, rest); ok {
+		r = math.Round(r*1000) / 1000
+		item.Discrimination = &r
+	}
+
+	keyed := keyedOptions(q)
+	minKeyed := -1
+	for j, text := range q.Options {
+		opt := Option{
+			Index:   j,
+			Text:    text,
+			Keyed:   slices.Contains(keyed, j),
+			Count:   optionCounts[j],
+			Percent: round1(float64(optionCounts[j]) * 100 / float64(item.Responses)),
+		}
+		if opt.Keyed && (minKeyed < 0 || opt.Count < minKeyed) {
+			minKeyed = opt.Count
+		}
+		item.Options = append(item.Options, opt)
+	}
+	for _, opt := range item.Options {
+		if !opt.Keyed && opt.Count > minKeyed && item.Responses >= minResponses {
+			item.Miskey = true
+		}
+	}
+
+	for text, n := range wrong {
+		item.WrongAnswers = append(item.WrongAnswers, Answer{Answer: text, Count: n})
+	}
+	slices.SortFunc(item.WrongAnswers, func(a, b Answer) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Answer, b.Answer)
+	})
+	if len(item.WrongAnswers) > topWrongAnswers {
+		item.WrongAnswers = item.WrongAnswers[:topWrongAnswers]
+	}
+	return item
+}
+
+// keyedOptions returns the indexes of the correct options.
+func keyedOptions(q models.Question) []int {
+	if q.Kind() == models.QuestionMulti {
+		return q.Answers
+	}
+	return []int{q.Answer}
+}
+
+// chosenOptions decodes a stored single or multi-select answer.
+func chosenOptions(answer json.RawMessage) []int {
+	if string(answer) == "null" {
+		return nil
+	}
+	var single int
+	if err := json.Unmarshal(answer, &single); err == nil {
+		return []int{single}
+	}
+	var multi []int
+	if err := json.Unmarshal(answer, &multi); err == nil {
+		slices.Sort(multi)
+		return slices.Compact(multi)
+	}
+	return nil
+}
+
+// answerText renders a stored text or ordering answer for display.
+func answerText(answer json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(answer, &text); err == nil {
+		return strings.TrimSpace(text)
+	}
+	var lines []string
+	if err := json.Unmarshal(answer, &lines); err == nil {
+		return strings.Join(lines, " / ")
+	}
+	return ""
+}
+
+// correlation returns the Pearson correlation of x and y, or false if either
+// has no variance.
+func correlation(x, y []float64) (float64, bool) {
+	n := float64(len(x))
+	if n < 2 {
+		return 0, false
+	}
+	mx, my := sum(x)/n, sum(y)/n
+	var cov, vx, vy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(vx*vy), true
+}
+
+func sum(xs []float64) float64 {
+	var s float64
+	for _, x := range xs {
+		s += x
+	}
+	return s
+}
+
+func round1(x float64) float64 {
+	return math.Round(x*10) / 10
+}
@@ -0,0 +1,189 @@
+package analysis
+
+import (
+	"encoding/json"
+	"maps"
+	"math"
+	"slices"
+	"testing"
+
+	"go-learning-app/models"
+)
+
+// response is one answer of an attempt: the submitted JSON and whether it
+// was graded correct.
+type response struct {
+	answer  string
+	correct bool
+}
+
+// attempt builds an attempt from its responses by question ID.
+func attempt(responses map[string]response) models.QuizAttempt {
+	var a models.QuizAttempt
+	for _, id := range slices.Sorted(maps.Keys(responses)) {
+		r := responses[id]
+		a.Answers = append(a.Answers, models.AttemptAnswer{QuestionID: id, Answer: json.RawMessage(r.answer), Correct: r.correct})
+		if r.correct {
+			a.Correct++
+		}
+		a.Total++
+	}
+	return a
+}
+
+func TestCorrelation(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+		ok   bool
+	}{
+		{"strong", []float64{1, 1, 0, 0}, []float64{1, 0.5, 0, 0}, 0.905, true},
+		{"identical", []float64{1, 0, 1, 0}, []float64{1, 0, 1, 0}, 1, true},
+		{"inverse", []float64{1, 0}, []float64{0, 1}, -1, true},
+		{"no variance in x", []float64{1, 1, 1}, []float64{0, 1, 0}, 0, false},
+		{"no variance in y", []float64{1, 0, 1}, []float64{0.5, 0.5, 0.5}, 0, false},
+		{"too few", []float64{1}, []float64{1}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := correlation(tt.x, tt.y)
+			if ok != tt.ok || math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("correlation = %.3f, %v; want %.3f, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestChosenOptions(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   []int
+	}{
+		{`2`, []int{2}},
+		{`[3, 1, 3]`, []int{1, 3}},
+		{`null`, nil},
+		{`"x"`, nil},
+	}
+	for _, tt := range tests {
+		if got := chosenOptions(json.RawMessage(tt.answer)); !slices.Equal(got, tt.want) {
+			t.Errorf("chosenOptions(%s) = %v, want %v", tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestAnswerText(t *testing.T) {
+	tests := []struct {
+		answer, want string
+	}{
+		{`"  hello \n"`, "hello"},
+		{`["a := 1", "fmt.Println(a)"]`, "a := 1 / fmt.Println(a)"},
+		{`3`, ""},
+	}
+	for _, tt := range tests {
+		if got := answerText(json.RawMessage(tt.answer)); got != tt.want {
+			t.Errorf("answerText(%s) = %q, want %q", tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	quiz := models.Quiz{
+		LessonID: "1-1",
+		Questions: []models.Question{
+			{ID: "q1", Text: "single", Options: []string{"a", "b", "c"}, Answer: 0},
+			{ID: "q2", Type: models.QuestionFill, Text: "fill", Accepted: []string{"x"}},
+			{ID: "q3", Text: "unanswered", Options: []string{"a", "b"}},
+		},
+	}
+	attempts := []models.QuizAttempt{
+		attempt(map[string]response{"q1": {`0`, true}, "q2": {`"x"`, true}}),
+		attempt(map[string]response{"q1": {`0`, true}, "q2": {`"x"`, true}}),
+		attempt(map[string]response{"q1": {`1`, false}, "q2": {`"y"`, false}}),
+		attempt(map[string]response{"q1": {`1`, false}, "q2": {`" y "`, false}}),
+		attempt(map[string]response{"q1": {`2`, false}, "q2": {`"z"`, false}}),
+	}
+	items := Analyze(quiz, attempts)
+	if len(items) != 3 {
+		t.Fatalf("Analyze returned %d items, want 3", len(items))
+	}
+
+	q1 := items[0]
+	if q1.Responses != 5 || q1.Difficulty != 40 || q1.LessonID != "1-1" || q1.Type != models.QuestionSingle {
+		t.Errorf("q1 = %d responses, difficulty %v, lesson %q, type %q; want 5, 40, 1-1, single",
+			q1.Responses, q1.Difficulty, q1.LessonID, q1.Type)
+	}
+	if q1.Discrimination == nil || *q1.Discrimination != 1 {
+		t.Errorf("q1 discrimination = %v, want 1", q1.Discrimination)
+	}
+	wantOptions := []Option{
+		{Index: 0, Text: "a", Keyed: true, Count: 2, Percent: 40},
+		{Index: 1, Text: "b", Count: 2, Percent: 40},
+		{Index: 2, Text: "c", Count: 1, Percent: 20},
+	}
+	if !slices.Equal(q1.Options, wantOptions) {
+		t.Errorf("q1 options = %+v, want %+v", q1.Options, wantOptions)
+	}
+	if q1.Miskey {
+		t.Error("q1 flagged as miskeyed; no wrong option is chosen more than the key")
+	}
+
+	q2 := items[1]
+	wantWrong := []Answer{{Answer: "y", Count: 2}, {Answer: "z", Count: 1}}
+	if !slices.Equal(q2.WrongAnswers, wantWrong) || q2.Options != nil {
+		t.Errorf("q2 wrong answers = %+v, options %+v; want %+v and none", q2.WrongAnswers, q2.Options, wantWrong)
+	}
+
+	q3 := items[2]
+	if q3.Responses != 0 || q3.Discrimination != nil || q3.Options != nil {
+		t.Errorf("q3 = %+v, want no responses", q3)
+	}
+}
+
+func TestAnalyzeMiskey(t *testing.T) {
+	quiz := models.Quiz{Questions: []models.Question{
+		{ID: "q", Options: []string{"a", "b"}, Answer: 0},
+		{ID: "m", Type: models.QuestionMulti, Options: []string{"a", "b", "c"}, Answers: []int{0, 1}},
+	}}
+	wrongOften := func(n int) []models.QuizAttempt {
+		var attempts []models.QuizAttempt
+		for i := range n {
+			if i < 2 {
+				attempts = append(attempts, attempt(map[string]response{"q": {`0`, true}, "m": {`[0, 1]`, true}}))
+			} else {
+				attempts = append(attempts, attempt(map[string]response{"q": {`1`, false}, "m": {`[0, 2]`, false}}))
+			}
+		}
+		return attempts
+	}
+	tests := []struct {
+		name      string
+		responses int
+		want      bool
+	}{
+		{"too few responses", minResponses - 1, false},
+		{"enough responses", minResponses, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, item := range Analyze(quiz, wrongOften(tt.responses)) {
+				if item.Miskey != tt.want {
+					t.Errorf("%s miskey = %v, want %v", item.QuestionID, item.Miskey, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeTopWrongAnswers(t *testing.T) {
+	quiz := models.Quiz{Questions: []models.Question{{ID: "q", Type: models.QuestionShort, Pattern: "^x$"}}}
+	var attempts []models.QuizAttempt
+	for _, text := range []string{"a", "b", "c", "d", "e", "f", "f", "g", "g", "g"} {
+		attempts = append(attempts, attempt(map[string]response{"q": {`"` + text + `"`, false}}))
+	}
+	got := Analyze(quiz, attempts)[0].WrongAnswers
+	want := []Answer{{"g", 3}, {"f", 2}, {"a", 1}, {"b", 1}, {"c", 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("wrong answers = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"go-learning-app/analysis"
+	"go-learning-app/content"
+	"go-learning-app/data"
+	"go-learning-app/publish"
+	"go-learning-app/validate"
+)
+
+// runCommand runs a command-line subcommand with its arguments.
+func runCommand(name string, args []string) error {
+	switch name {
+	case "items":
+		return itemsCommand(args)
+	case "export-content":
+		return exportContentCommand(args)
+	case "validate":
+		return validateCommand(args)
+	case "export-course":
+		return exportCourseCommand(args)
+	case "import-course":
+		return importCourseCommand(args)
+	case "export-site":
+		return exportSiteCommand(args)
+	case "export-book":
+		return exportBookCommand(args)
+	default:
+		return fmt.Errorf("unknown command %q (available: items, export-content, validate, export-course, import-course, export-site, export-book)", name)
+	}
+}
+
+// itemsCommand prints the item analysis of recorded quiz attempts.
+func itemsCommand(args []string) error {
+	fs := flag.NewFlagSet("items", flag.ContinueOnError)
+	course := fs.String("course", "", "course whose quizzes to analyze (default: the first course)")
+	lesson := fs.String("lesson", "", "analyze only this lesson's quiz, e.g. 1-1")
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	flagged := fs.Bool("flagged", false, "print only questions flagged as possibly miskeyed")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := data.NewDB(data.DBPath())
+	if err != nil {
+		return fmt.Errorf("データベースの初期化に失敗しました: %w", err)
+	}
+	defer db.Close()
+
+	catalog, err := data.NewCatalog()
+	if err != nil {
+		return err
+	}
+	store := catalog.Courses()[0]
+	if *course != "" {
+		var ok bool
+		if store, ok = catalog.Course(*course); !ok {
+			return fmt.Errorf("unknown course %q", *course)
+		}
+	}
+	items, err := analysis.Report(store, db.Course(store.Course.ID), *lesson)
+	if err != nil {
+		return err
+	}
+	if *flagged {
+		var kept []analysis.Item
+		for _, it := range items {
+			if it.Miskey {
+				kept = append(kept, it)
+			}
+		}
+		items = kept
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "QUESTION\tTYPE\tN\tCORRECT%\tDISCRIM\tDISTRACTORS\tFLAG")
+	for _, it := range items {
+		discrim := "-"
+		if it.Discrimination != nil {
+			discrim = fmt.Sprintf("%.2f", *it.Discrimination)
+		}
+		note := ""
+		if it.Miskey {
+			note = "MISKEY?"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%.1f\t%s\t%s\t%s\n",
+			it.QuestionID, it.Type, it.Responses, it.Difficulty, discrim, distractors(it), note)
+	}
+	return w.Flush()
+}
+
+// exportContentCommand writes course content in the content file layout,
+// either the content built into the binary or a content directory, which
+// rewrites hand-edited files in canonical form.
+func exportContentCommand(args []string) error {
+	fs := flag.NewFlagSet("export-content", flag.ContinueOnError)
+	out := fs.String("out", "", "directory to write course directories into (required)")
+	from := fs.String("from", "", "read content from this directory instead of the built-in content")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return fmt.Errorf("-out is required")
+	}
+
+	var catalog *data.Catalog
+	var err error
+	if *from != "" {
+		catalog, err = data.LoadCatalog(os.DirFS(*from))
+	} else {
+		catalog, err = data.NewCatalog()
+	}
+	if err != nil {
+		return err
+	}
+	return data.ExportContent(catalog, *out)
+}
+
+// exportCourseCommand writes one course as a course package, with every
+// file of its directory, so another deployment can import it.
+func exportCourseCommand(args []string) error {
+	fs := flag.NewFlagSet("export-course", flag.ContinueOnError)
+	course := fs.String("course", "", "ID of the course to export (required)")
+	from := fs.String("from", "", "read the course from this content directory instead of the built-in content")
+	out := fs.String("out", "", "package file to write (default: <course>.zip)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *course == "" {
+		return fmt.Errorf("-course is required")
+	}
+	if *out == "" {
+		*out = *course + ".zip"
+	}
+
+	var root iofs.FS = content.FS
+	if *from != "" {
+		root = os.DirFS(*from)
+	}
+	if _, err := iofs.Stat(root, *course); err != nil {
+		return fmt.Errorf("unknown course %q", *course)
+	}
+	dir, err := iofs.Sub(root, *course)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := data.WritePackage(&buf, dir); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", *out)
+	return nil
+}
+
+// importCourseCommand checks a course package and writes its course into
+// a content directory. A course with the same ID is only replaced with
+// -replace; -dry-run shows what would change without writing anything.
+func importCourseCommand(args []string) error {
+	fs := flag.NewFlagSet("import-course", flag.ContinueOnError)
+	dir := fs.String("content", "content", "content directory to import into")
+	replace := fs.Bool("replace", false, "replace a course with the same ID")
+	dryRun := fs.Bool("dry-run", false, "print what would change without writing anything")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: import-course [-content dir] [-replace] [-dry-run] package.zip")
+	}
+
+	raw, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	pkg, err := data.ReadPackage(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(*dir, pkg.Manifest.ID)
+	var current *data.Store
+	var currentFiles map[string][]byte
+	if _, err := os.Stat(target); err == nil {
+		currentFiles, err = data.ReadFiles(os.DirFS(target))
+		if err != nil {
+			return err
+		}
+		// A course that no longer loads can still be replaced; its
+		// lessons are then all listed as added.
+		current, _ = data.LoadStore(os.DirFS(target))
+	}
+	plan := data.PlanImport(pkg, current, currentFiles)
+	printImportPlan(plan)
+	if *dryRun {
+		return nil
+	}
+	if plan.Exists && !*replace {
+		return fmt.Errorf("course %s already exists in %s; use -replace to replace it", plan.Course.ID, *dir)
+	}
+
+	// Write the course next to its final place and swap it in, so a
+	// failed import leaves the old course intact.
+	tmp := target + ".import"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	for name, b := range pkg.Files {
+		file := filepath.Join(tmp, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, b, 0o644); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return err
+	}
+	fmt.Printf("imported %s into %s\n", plan.Course.ID, target)
+	return nil
+}
+
+// printImportPlan prints the changes an import makes, one line per file.
+func printImportPlan(plan data.ImportPlan) {
+	action := "new course"
+	if plan.Exists {
+		action = "replaces the existing course"
+	}
+	fmt.Printf("course %s %q: %s\n", plan.Course.ID, plan.Course.Title, action)
+	for _, c := range []struct {
+		name string
+		set  data.ChangeSet
+	}{{"lessons", plan.Lessons}, {"files", plan.Files}} {
+		fmt.Printf("%s: %d added, %d changed, %d removed\n", c.name, len(c.set.Added), len(c.set.Changed), len(c.set.Removed))
+		for _, mark := range []struct {
+			sign  string
+			names []string
+		}{{"+", c.set.Added}, {"~", c.set.Changed}, {"-", c.set.Removed}} {
+			for _, name := range mark.names {
+				fmt.Printf("  %s %s\n", mark.sign, name)
+			}
+		}
+	}
+}
+
+// exportSiteCommand writes a course as a static website that needs no
+// server, with the recorded output of its examples in place of running
+// them.
+func exportSiteCommand(args []string) error {
+	fs := flag.NewFlagSet("export-site", flag.ContinueOnError)
+	out := fs.String("out", "", "directory to write the site into (required)")
+	course := fs.String("course", "", "course to export (default: the first course)")
+	from := fs.String("from", "", "read content from this directory instead of the built-in content")
+	lang := fs.String("lang", data.DefaultLocale, "language of the exported text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return fmt.Errorf("-out is required")
+	}
+
+	store, err := commandStore(*from, *course)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(store.Locales(), *lang) {
+		return fmt.Errorf("course %s has no %q translation", store.Course.ID, *lang)
+	}
+	if err := publish.Site(context.Background(), store.Localized(*lang), *out); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", *out)
+	return nil
+}
+
+// exportBookCommand writes a course as an EPUB book or as a single HTML
+// page for printing a handbook.
+func exportBookCommand(args []string) error {
+	fs := flag.NewFlagSet("export-book", flag.ContinueOnError)
+	format := fs.String("format", "epub", "epub for e-readers, or html for printing")
+	out := fs.String("out", "", "file to write (default: <course>.epub or <course>.html)")
+	course := fs.String("course", "", "course to export (default: the first course)")
+	from := fs.String("from", "", "read content from this directory instead of the built-in content")
+	lang := fs.String("lang", data.DefaultLocale, "language of the exported text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	write := publish.EPUB
+	switch *format {
+	case "epub":
+	case "html":
+		write = publish.Handbook
+	default:
+		return fmt.Errorf("unknown format %q (available: epub, html)", *format)
+	}
+
+	store, err := commandStore(*from, *course)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(store.Locales(), *lang) {
+		return fmt.Errorf("course %s has no %q translation", store.Course.ID, *lang)
+	}
+	if *out == "" {
+		*out = store.Course.ID + "." + *format
+	}
+	var buf bytes.Buffer
+	if err := write(context.Background(), store.Localized(*lang), &buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", *out)
+	return nil
+}
+
+// commandStore loads the built-in content, or a content directory, and
+// returns one of its courses, the first if id is empty.
+func commandStore(from, id string) (*data.Store, error) {
+	var catalog *data.Catalog
+	var err error
+	if from != "" {
+		catalog, err = data.LoadCatalog(os.DirFS(from))
+	} else {
+		catalog, err = data.NewCatalog()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return catalog.Courses()[0], nil
+	}
+	store, ok := catalog.Course(id)
+	if !ok {
+		return nil, fmt.Errorf("unknown course %q", id)
+	}
+	return store, nil
+}
+
+// validateCommand checks the content directory and prints every problem.
+// It fails if there are any, so it can gate a build.
+func validateCommand(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	dir := fs.String("content", "content", "content directory to check")
+	update := fs.Bool("update", false, "record the output of every example as its golden output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rep, err := validate.Run(context.Background(), validate.Options{Dir: *dir, Update: *update})
+	if err != nil {
+		return err
+	}
+	for _, p := range rep.Problems {
+		fmt.Println(p)
+	}
+	for _, file := range rep.Updated {
+		fmt.Println("updated", file)
+	}
+	for _, c := range rep.Coverage {
+		fmt.Printf("translation %s, %d of %d lessons complete\n", c, len(c.Lessons), rep.Lessons)
+	}
+	fmt.Printf("checked %d lessons, %d examples, %d starters, %d output questions: %d problems\n",
+		rep.Lessons, rep.Examples, rep.Starters, rep.OutputQuestions, len(rep.Problems))
+	if len(rep.Problems) > 0 {
+		return fmt.Errorf("validation failed")
+	}
+	return nil
+}
+
+// distractors summarizes how often each option, or each common wrong
+// answer, was given. Keyed options are marked with *.
+func distractors(it analysis.Item) string {
+	var parts []string
+	for _, o := range it.Options {
+		mark := ""
+		if o.Keyed {
+			mark = "*"
+		}
+		parts = append(parts, fmt.Sprintf("%c%s:%d", 'A'+o.Index, mark, o.Count))
+	}
+	for _, a := range it.WrongAnswers {
+		parts = append(parts, fmt.Sprintf("%q:%d", a.Answer, a.Count))
+	}
+	return strings.Join(parts, " ")
+}
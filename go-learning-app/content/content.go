@@ -0,0 +1,19 @@
+// Package content embeds the courses: chapters, lessons, code examples and
+// quizzes as Markdown, YAML and Go files. See data.LoadCatalog for the
+// layout.
+//
+// The output of every example is recorded next to it when the content is
+// generated, and served with the example:
+//
+//	go generate ./content
+package content
+
+//go:generate go run .. validate -update -content .
+
+import "embed"
+
+// FS holds one directory per course. The "all:" prefix keeps the
+// "_examples" and "_exercises" directories, which embed skips by default.
+//
+//go:embed all:*
+var FS embed.FS
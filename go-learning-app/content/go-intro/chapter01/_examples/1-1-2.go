@@ -0,0 +1,10 @@
+package main
+
+import (
+    "fmt"
+    "time"
+)
+
+func main() {
+    fmt.Println("現在時刻:", time.Now())
+}
@@ -0,0 +1,20 @@
+package main
+
+import "fmt"
+
+func main() {
+    // var を使った宣言
+    var name string = "Go"
+    var age int = 15
+
+    // 型推論（型を省略）
+    var language = "Go言語"
+
+    // 短縮変数宣言（関数内のみ）
+    message := "Hello!"
+
+    // 複数変数の同時宣言
+    var x, y int = 10, 20
+
+    fmt.Println(name, age, language, message, x, y)
+}
@@ -0,0 +1,20 @@
+package main
+
+import "fmt"
+
+const Pi = 3.14159
+const (
+    StatusOK    = 200
+    StatusError = 500
+)
+
+func main() {
+    // ゼロ値の確認
+    var i int       // 0
+    var f float64   // 0.0
+    var b bool      // false
+    var s string    // ""
+
+    fmt.Printf("int: %d, float: %f, bool: %t, string: %q\n", i, f, b, s)
+    fmt.Println("Pi =", Pi)
+}
@@ -0,0 +1,28 @@
+package main
+
+import "fmt"
+
+func main() {
+    // 整数型
+    var age int = 30
+    var small int8 = 127  // -128 ~ 127
+
+    // 浮動小数点型
+    var pi float64 = 3.14159
+    var e float32 = 2.718
+
+    // 論理型
+    var isGo bool = true
+
+    // 文字列型
+    var greeting string = "こんにちは"
+
+    // rune（Unicode文字）
+    var r rune = '漢'
+
+    fmt.Printf("age: %d, small: %d\n", age, small)
+    fmt.Printf("pi: %f, e: %f\n", pi, e)
+    fmt.Printf("isGo: %t\n", isGo)
+    fmt.Printf("greeting: %s (len=%d bytes)\n", greeting, len(greeting))
+    fmt.Printf("rune: %c (Unicode: U+%04X)\n", r, r)
+}
@@ -0,0 +1,18 @@
+package main
+
+import "fmt"
+
+func main() {
+    // 明示的な型変換が必要
+    var i int = 42
+    var f float64 = float64(i)
+    var u uint = uint(f)
+
+    fmt.Println(i, f, u)
+
+    // 文字列とバイト列の変換
+    s := "Hello, Go!"
+    b := []byte(s)
+    s2 := string(b)
+    fmt.Println(s, b, s2)
+}
@@ -0,0 +1,19 @@
+package main
+
+import "fmt"
+
+func main() {
+    name := "Go"
+    version := 1.22
+
+    // Print: 改行なし
+    fmt.Print("Hello, ")
+    fmt.Print(name)
+    fmt.Println() // 改行だけ
+
+    // Println: スペース区切り＋改行
+    fmt.Println("言語:", name, "バージョン:", version)
+
+    // Printf: 書式指定
+    fmt.Printf("言語: %s, バージョン: %.2f\n", name, version)
+}
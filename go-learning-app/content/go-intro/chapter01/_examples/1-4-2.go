@@ -0,0 +1,24 @@
+package main
+
+import "fmt"
+
+type Point struct {
+    X, Y int
+}
+
+func main() {
+    p := Point{10, 20}
+
+    // Sprintf: 文字列として返す
+    s := fmt.Sprintf("座標: (%d, %d)", p.X, p.Y)
+    fmt.Println(s)
+
+    // %v: デフォルト形式
+    fmt.Printf("%%v:  %v\n", p)
+    // %+v: フィールド名付き
+    fmt.Printf("%%+v: %+v\n", p)
+    // %#v: Go構文形式
+    fmt.Printf("%%#v: %#v\n", p)
+    // %T: 型名
+    fmt.Printf("%%T:  %T\n", p)
+}
@@ -0,0 +1,8 @@
+package main
+
+import "fmt"
+
+func main() {
+    // ここに自分の名前を表示するコードを書いてください
+    
+}
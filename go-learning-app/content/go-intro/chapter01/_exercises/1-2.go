@@ -0,0 +1,11 @@
+package main
+
+import "fmt"
+
+func main() {
+    // 変数 a と b を宣言して値を代入
+    
+    
+    // a + b の結果を表示
+    
+}
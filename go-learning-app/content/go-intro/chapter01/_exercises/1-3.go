@@ -0,0 +1,16 @@
+package main
+
+import "fmt"
+
+func main() {
+    num := 100
+    
+    // num を float64 に変換
+    
+    
+    // さらに uint に変換
+    
+    
+    // 結果を表示
+    
+}
@@ -0,0 +1,13 @@
+package main
+
+import "fmt"
+
+func main() {
+    // 変数を宣言
+    name := ""
+    age := 0
+    height := 0.0
+    
+    // fmt.Printf で書式付き表示
+    
+}
@@ -0,0 +1,15 @@
+package main
+
+import "fmt"
+
+func main() {
+    x := 10
+
+    if x > 0 {
+        fmt.Println("正の数")
+    } else if x < 0 {
+        fmt.Println("負の数")
+    } else {
+        fmt.Println("ゼロ")
+    }
+}
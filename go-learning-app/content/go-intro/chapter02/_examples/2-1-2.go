@@ -0,0 +1,19 @@
+package main
+
+import (
+    "fmt"
+    "os"
+)
+
+func main() {
+    // err のスコープは if ブロック内に限定される
+    if err := doSomething(); err != nil {
+        fmt.Println("エラー:", err)
+        os.Exit(1)
+    }
+    fmt.Println("成功")
+}
+
+func doSomething() error {
+    return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import "fmt"
+
+func main() {
+    // C言語スタイル
+    for i := 0; i < 5; i++ {
+        fmt.Print(i, " ")
+    }
+    fmt.Println()
+
+    // whileスタイル
+    n := 1
+    for n < 100 {
+        n *= 2
+    }
+    fmt.Println("n =", n)
+
+    // 無限ループ（breakで抜ける）
+    count := 0
+    for {
+        count++
+        if count >= 3 {
+            break
+        }
+    }
+    fmt.Println("count =", count)
+}
@@ -0,0 +1,21 @@
+package main
+
+import "fmt"
+
+func main() {
+    // スライスのrange
+    fruits := []string{"りんご", "バナナ", "みかん"}
+    for i, fruit := range fruits {
+        fmt.Printf("%d: %s\n", i, fruit)
+    }
+
+    // インデックス不要な場合
+    for _, fruit := range fruits {
+        fmt.Println(fruit)
+    }
+
+    // 文字列のrange（runeで反復）
+    for i, r := range "Go言語" {
+        fmt.Printf("byte=%d, rune=%c\n", i, r)
+    }
+}
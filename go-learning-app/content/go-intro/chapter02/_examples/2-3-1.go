@@ -0,0 +1,19 @@
+package main
+
+import (
+    "fmt"
+    "time"
+)
+
+func main() {
+    day := time.Now().Weekday()
+
+    switch day {
+    case time.Saturday, time.Sunday:
+        fmt.Println("週末です！")
+    case time.Friday:
+        fmt.Println("もうすぐ週末！")
+    default:
+        fmt.Println("平日です")
+    }
+}
@@ -0,0 +1,19 @@
+package main
+
+import "fmt"
+
+func main() {
+    score := 85
+
+    // 式なしswitch（if-else if の代替）
+    switch {
+    case score >= 90:
+        fmt.Println("A")
+    case score >= 80:
+        fmt.Println("B")
+    case score >= 70:
+        fmt.Println("C")
+    default:
+        fmt.Println("D")
+    }
+}
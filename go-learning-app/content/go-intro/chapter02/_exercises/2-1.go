@@ -0,0 +1,11 @@
+package main
+
+import "fmt"
+
+func main() {
+    // 変数 n を定義
+    n := 5
+    
+    // if文で偶数・奇数を判定して表示
+    
+}
@@ -0,0 +1,12 @@
+package main
+
+import "fmt"
+
+func main() {
+    sum := 0
+    
+    // 1から10までループして sum に加算
+    
+    
+    fmt.Println("合計:", sum)
+}
@@ -0,0 +1,10 @@
+package main
+
+import "fmt"
+
+func main() {
+    signal := "red"
+    
+    // switch文で信号機の色に応じたメッセージを表示
+    
+}
@@ -0,0 +1,19 @@
+package main
+
+import "fmt"
+
+func add(a, b int) int {
+    return a + b
+}
+
+func greet(name string) string {
+    return "Hello, " + name + "!"
+}
+
+func main() {
+    result := add(3, 5)
+    fmt.Println(result) // 8
+
+    msg := greet("Go")
+    fmt.Println(msg) // Hello, Go!
+}
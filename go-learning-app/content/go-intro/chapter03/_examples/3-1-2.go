@@ -0,0 +1,20 @@
+package main
+
+import "fmt"
+
+func sum(nums ...int) int {
+    total := 0
+    for _, n := range nums {
+        total += n
+    }
+    return total
+}
+
+func main() {
+    fmt.Println(sum(1, 2, 3))       // 6
+    fmt.Println(sum(1, 2, 3, 4, 5)) // 15
+
+    // スライスを展開して渡す
+    nums := []int{10, 20, 30}
+    fmt.Println(sum(nums...)) // 60
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+)
+
+func divide(a, b float64) (float64, error) {
+    if b == 0 {
+        return 0, errors.New("ゼロ除算エラー")
+    }
+    return a / b, nil
+}
+
+func main() {
+    result, err := divide(10, 3)
+    if err != nil {
+        fmt.Println("エラー:", err)
+        return
+    }
+    fmt.Printf("10 / 3 = %.2f\n", result)
+
+    _, err = divide(10, 0)
+    if err != nil {
+        fmt.Println("エラー:", err)
+    }
+}
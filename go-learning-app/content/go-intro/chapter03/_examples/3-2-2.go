@@ -0,0 +1,22 @@
+package main
+
+import "fmt"
+
+func minMax(nums []int) (min, max int) {
+    min = nums[0]
+    max = nums[0]
+    for _, n := range nums[1:] {
+        if n < min {
+            min = n
+        }
+        if n > max {
+            max = n
+        }
+    }
+    return // 裸のreturn（min, max が返される）
+}
+
+func main() {
+    lo, hi := minMax([]int{3, 1, 4, 1, 5, 9, 2, 6})
+    fmt.Printf("min=%d, max=%d\n", lo, hi)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+    "fmt"
+    "math"
+)
+
+type Circle struct {
+    Radius float64
+}
+
+// 値レシーバ（読み取りのみ）
+func (c Circle) Area() float64 {
+    return math.Pi * c.Radius * c.Radius
+}
+
+// ポインタレシーバ（値を変更可能）
+func (c *Circle) Scale(factor float64) {
+    c.Radius *= factor
+}
+
+func main() {
+    c := Circle{Radius: 5}
+    fmt.Printf("面積: %.2f\n", c.Area())
+
+    c.Scale(2)
+    fmt.Printf("スケール後の面積: %.2f\n", c.Area())
+}
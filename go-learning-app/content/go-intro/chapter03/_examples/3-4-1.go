@@ -0,0 +1,22 @@
+package main
+
+import "fmt"
+
+func counter() func() int {
+    count := 0
+    return func() int {
+        count++
+        return count
+    }
+}
+
+func main() {
+    c1 := counter()
+    fmt.Println(c1()) // 1
+    fmt.Println(c1()) // 2
+    fmt.Println(c1()) // 3
+
+    // 別のカウンターは独立
+    c2 := counter()
+    fmt.Println(c2()) // 1
+}
@@ -0,0 +1,25 @@
+package main
+
+import "fmt"
+
+func apply(nums []int, fn func(int) int) []int {
+    result := make([]int, len(nums))
+    for i, n := range nums {
+        result[i] = fn(n)
+    }
+    return result
+}
+
+func main() {
+    nums := []int{1, 2, 3, 4, 5}
+
+    doubled := apply(nums, func(n int) int {
+        return n * 2
+    })
+    fmt.Println(doubled) // [2 4 6 8 10]
+
+    squared := apply(nums, func(n int) int {
+        return n * n
+    })
+    fmt.Println(squared) // [1 4 9 16 25]
+}
@@ -0,0 +1,14 @@
+package main
+
+import "fmt"
+
+// multiply 関数を定義
+func multiply(a, b int) int {
+    // ここに実装
+    return 0
+}
+
+func main() {
+    result := multiply(10, 20)
+    fmt.Println("10 * 20 =", result)
+}
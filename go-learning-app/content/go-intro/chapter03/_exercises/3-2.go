@@ -0,0 +1,13 @@
+package main
+
+import "fmt"
+
+func swap(a, b string) (string, string) {
+    // ここに実装
+    return "", ""
+}
+
+func main() {
+    a, b := swap("Hello", "World")
+    fmt.Println(a, b) // World Hello と表示されるはず
+}
@@ -0,0 +1,12 @@
+package main
+
+import "fmt"
+
+// Rectangle 構造体の定義
+
+// Area メソッドの定義
+
+func main() {
+    r := Rectangle{Width: 10, Height: 5}
+    fmt.Println("面積:", r.Area())
+}
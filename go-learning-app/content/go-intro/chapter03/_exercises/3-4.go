@@ -0,0 +1,18 @@
+package main
+
+import "fmt"
+
+func createAdder(step int) func() int {
+    sum := 0
+    return func() int {
+        // ここに実装
+        return sum
+    }
+}
+
+func main() {
+    addTwo := createAdder(2)
+    fmt.Println(addTwo()) // 2
+    fmt.Println(addTwo()) // 4
+    fmt.Println(addTwo()) // 6
+}
@@ -0,0 +1,26 @@
+package main
+
+import "fmt"
+
+func main() {
+    // 配列の宣言
+    var a [3]int
+    a[0] = 10
+    a[1] = 20
+    a[2] = 30
+    fmt.Println(a) // [10 20 30]
+
+    // リテラルで初期化
+    b := [3]string{"Go", "Python", "Rust"}
+    fmt.Println(b)
+
+    // サイズを自動推論
+    c := [...]int{1, 2, 3, 4, 5}
+    fmt.Println(len(c)) // 5
+
+    // 配列は値型（コピーされる）
+    d := a
+    d[0] = 999
+    fmt.Println(a) // [10 20 30] （変更されない）
+    fmt.Println(d) // [999 20 30]
+}
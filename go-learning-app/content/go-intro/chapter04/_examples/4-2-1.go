@@ -0,0 +1,21 @@
+package main
+
+import "fmt"
+
+func main() {
+    // スライスリテラル
+    s := []int{1, 2, 3, 4, 5}
+    fmt.Println(s) // [1 2 3 4 5]
+
+    // make で作成
+    s2 := make([]int, 3, 10) // len=3, cap=10
+    fmt.Printf("len=%d, cap=%d\n", len(s2), cap(s2))
+
+    // append で要素追加
+    s2 = append(s2, 4, 5, 6)
+    fmt.Println(s2) // [0 0 0 4 5 6]
+
+    // スライス式
+    sub := s[1:4] // インデックス1から3まで
+    fmt.Println(sub) // [2 3 4]
+}
@@ -0,0 +1,18 @@
+package main
+
+import "fmt"
+
+func main() {
+    // スライスは参照型
+    original := []int{1, 2, 3}
+    copied := original
+    copied[0] = 999
+    fmt.Println(original) // [999 2 3] 変更が反映される！
+
+    // 独立したコピーを作る
+    independent := make([]int, len(original))
+    copy(independent, original)
+    independent[0] = 1
+    fmt.Println(original)    // [999 2 3]
+    fmt.Println(independent) // [1 2 3]
+}
@@ -0,0 +1,35 @@
+package main
+
+import "fmt"
+
+func main() {
+    // マップの作成
+    ages := map[string]int{
+        "Alice": 30,
+        "Bob":   25,
+    }
+
+    // 要素の追加・更新
+    ages["Charlie"] = 35
+
+    // 要素の取得
+    fmt.Println("Alice:", ages["Alice"])
+
+    // 存在確認（comma ok イディオム）
+    age, ok := ages["Dave"]
+    if ok {
+        fmt.Println("Dave:", age)
+    } else {
+        fmt.Println("Dave は存在しません")
+    }
+
+    // 要素の削除
+    delete(ages, "Bob")
+
+    // 反復処理
+    for name, age := range ages {
+        fmt.Printf("%s: %d歳\n", name, age)
+    }
+
+    fmt.Println("人数:", len(ages))
+}
@@ -0,0 +1,13 @@
+package main
+
+import "fmt"
+
+func main() {
+    // サイズ5の配列を宣言
+    var arr [5]int
+    
+    // ループで値を代入
+    
+    
+    fmt.Println(arr)
+}
@@ -0,0 +1,16 @@
+package main
+
+import "fmt"
+
+func main() {
+    // 空のスライスを作成
+    var s []int
+    
+    // 1から5までループして append
+    for i := 1; i <= 5; i++ {
+        // 追加
+        
+        // lenとcapを表示
+        
+    }
+}
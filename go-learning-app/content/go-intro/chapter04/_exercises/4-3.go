@@ -0,0 +1,14 @@
+package main
+
+import "fmt"
+
+func main() {
+    // マップの作成と初期化
+    
+    // orangeを追加
+    
+    // appleを削除
+    
+    // 結果を表示
+    fmt.Println(fruits)
+}
@@ -0,0 +1,26 @@
+package main
+
+import "fmt"
+
+type User struct {
+    Name  string
+    Email string
+    Age   int
+}
+
+func main() {
+    // 構造体の初期化
+    u1 := User{Name: "Alice", Email: "alice@example.com", Age: 30}
+
+    // フィールドアクセス
+    fmt.Println(u1.Name)
+
+    // ポインタ
+    u2 := &User{Name: "Bob", Age: 25}
+    u2.Email = "bob@example.com" // ポインタでも . でアクセス可能
+    fmt.Printf("%+v\n", u2)
+
+    // ゼロ値で初期化
+    var u3 User
+    fmt.Printf("%+v\n", u3) // {Name: Email: Age:0}
+}
@@ -0,0 +1,25 @@
+package main
+
+import "fmt"
+
+type Server struct {
+    Host string
+    Port int
+}
+
+// コンストラクタ関数（Goの慣例: New + 型名）
+func NewServer(host string, port int) *Server {
+    return &Server{
+        Host: host,
+        Port: port,
+    }
+}
+
+func (s *Server) Address() string {
+    return fmt.Sprintf("%s:%d", s.Host, s.Port)
+}
+
+func main() {
+    srv := NewServer("localhost", 8080)
+    fmt.Println(srv.Address())
+}
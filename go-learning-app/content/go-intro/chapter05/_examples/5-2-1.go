@@ -0,0 +1,47 @@
+package main
+
+import (
+    "fmt"
+    "math"
+)
+
+type Shape interface {
+    Area() float64
+    Perimeter() float64
+}
+
+type Rectangle struct {
+    Width, Height float64
+}
+
+func (r Rectangle) Area() float64 {
+    return r.Width * r.Height
+}
+
+func (r Rectangle) Perimeter() float64 {
+    return 2 * (r.Width + r.Height)
+}
+
+type Circle struct {
+    Radius float64
+}
+
+func (c Circle) Area() float64 {
+    return math.Pi * c.Radius * c.Radius
+}
+
+func (c Circle) Perimeter() float64 {
+    return 2 * math.Pi * c.Radius
+}
+
+func printShape(s Shape) {
+    fmt.Printf("面積: %.2f, 周囲: %.2f\n", s.Area(), s.Perimeter())
+}
+
+func main() {
+    r := Rectangle{Width: 10, Height: 5}
+    c := Circle{Radius: 7}
+
+    printShape(r) // Rectangle は Shape を暗黙的に実装
+    printShape(c) // Circle も Shape を暗黙的に実装
+}
@@ -0,0 +1,33 @@
+package main
+
+import "fmt"
+
+func describe(i any) string {
+    // 型スイッチ
+    switch v := i.(type) {
+    case int:
+        return fmt.Sprintf("整数: %d", v)
+    case string:
+        return fmt.Sprintf("文字列: %q (長さ%d)", v, len(v))
+    case bool:
+        return fmt.Sprintf("真偽値: %t", v)
+    case []int:
+        return fmt.Sprintf("intスライス: %v", v)
+    default:
+        return fmt.Sprintf("不明な型: %T", v)
+    }
+}
+
+func main() {
+    fmt.Println(describe(42))
+    fmt.Println(describe("hello"))
+    fmt.Println(describe(true))
+    fmt.Println(describe([]int{1, 2, 3}))
+
+    // comma ok パターン
+    var i any = "Go言語"
+    s, ok := i.(string)
+    if ok {
+        fmt.Println("文字列:", s)
+    }
+}
@@ -0,0 +1,28 @@
+package main
+
+import "fmt"
+
+type Animal struct {
+    Name string
+}
+
+func (a Animal) Speak() string {
+    return a.Name + "が鳴いています"
+}
+
+type Dog struct {
+    Animal // 埋め込み（フィールド名なし）
+    Breed  string
+}
+
+func main() {
+    d := Dog{
+        Animal: Animal{Name: "ポチ"},
+        Breed:  "柴犬",
+    }
+
+    // Animal のフィールドに直接アクセス
+    fmt.Println(d.Name)    // ポチ
+    fmt.Println(d.Speak()) // ポチが鳴いています
+    fmt.Println(d.Breed)   // 柴犬
+}
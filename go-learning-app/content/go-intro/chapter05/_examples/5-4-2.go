@@ -0,0 +1,35 @@
+package main
+
+import "fmt"
+
+type Reader interface {
+    Read(p []byte) (n int, err error)
+}
+
+type Writer interface {
+    Write(p []byte) (n int, err error)
+}
+
+// Reader と Writer を埋め込んで組み合わせ
+type ReadWriter interface {
+    Reader
+    Writer
+}
+
+type MyReadWriter struct{}
+
+func (rw MyReadWriter) Read(p []byte) (int, error) {
+    fmt.Println("Reading...")
+    return 0, nil
+}
+
+func (rw MyReadWriter) Write(p []byte) (int, error) {
+    fmt.Println("Writing...")
+    return len(p), nil
+}
+
+func main() {
+    var rw ReadWriter = MyReadWriter{}
+    rw.Read(nil)
+    rw.Write([]byte("hello"))
+}
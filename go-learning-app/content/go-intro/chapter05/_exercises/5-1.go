@@ -0,0 +1,12 @@
+package main
+
+import "fmt"
+
+// Book 構造体を定義
+
+func main() {
+    // Bookのインスタンスを作成
+    
+    // 内容を表示
+    fmt.Printf("タイトル: %s, 著者: %s, 価格: %d円\n", )
+}
@@ -0,0 +1,19 @@
+package main
+
+import "fmt"
+
+// Animal インターフェースの定義
+
+// Dog 構造体と Speak メソッド
+
+// Cat 構造体と Speak メソッド
+
+func main() {
+    var animals []Animal
+    // DogとCatを追加
+    
+    // ループでSpeakを呼び出す
+    for _, a := range animals {
+        fmt.Println(a.Speak())
+    }
+}
@@ -0,0 +1,14 @@
+package main
+
+import "fmt"
+
+func process(v any) {
+    // 型スイッチで処理を分岐
+    
+}
+
+func main() {
+    process(10)
+    process("World")
+    process(true)
+}
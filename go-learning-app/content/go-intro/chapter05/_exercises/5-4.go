@@ -0,0 +1,14 @@
+package main
+
+import "fmt"
+
+// Person 構造体
+
+// Programmer 構造体（Personを埋め込み）
+
+func main() {
+    // Programmerのインスタンス作成
+    
+    // フィールドを表示（Nameは昇格しているため直接アクセス可）
+    
+}
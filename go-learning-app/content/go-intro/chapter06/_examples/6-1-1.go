@@ -0,0 +1,28 @@
+package main
+
+import (
+    "fmt"
+    "time"
+)
+
+func sayHello(name string) {
+    for i := 0; i < 3; i++ {
+        fmt.Printf("Hello, %s! (%d)\n", name, i)
+        time.Sleep(100 * time.Millisecond)
+    }
+}
+
+func main() {
+    // ゴルーチンとして起動
+    go sayHello("Go")
+    go sayHello("World")
+
+    // 無名関数のゴルーチン
+    go func() {
+        fmt.Println("無名関数のゴルーチン")
+    }()
+
+    // メインが終了するとゴルーチンも終了する
+    time.Sleep(500 * time.Millisecond)
+    fmt.Println("メイン終了")
+}
@@ -0,0 +1,26 @@
+package main
+
+import "fmt"
+
+func main() {
+    // バッファなしチャネル
+    ch := make(chan string)
+
+    go func() {
+        ch <- "Hello from goroutine!"
+    }()
+
+    msg := <-ch // 受信（ブロックする）
+    fmt.Println(msg)
+
+    // バッファ付きチャネル
+    buffered := make(chan int, 3)
+    buffered <- 1
+    buffered <- 2
+    buffered <- 3
+    // buffered <- 4 // ブロックする（バッファが一杯）
+
+    fmt.Println(<-buffered) // 1
+    fmt.Println(<-buffered) // 2
+    fmt.Println(<-buffered) // 3
+}
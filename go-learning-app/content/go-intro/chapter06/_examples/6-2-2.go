@@ -0,0 +1,23 @@
+package main
+
+import "fmt"
+
+func sum(nums []int, ch chan int) {
+    total := 0
+    for _, n := range nums {
+        total += n
+    }
+    ch <- total
+}
+
+func main() {
+    nums := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+    ch := make(chan int)
+
+    // 前半と後半を並行で計算
+    go sum(nums[:5], ch)
+    go sum(nums[5:], ch)
+
+    a, b := <-ch, <-ch
+    fmt.Printf("合計: %d + %d = %d\n", a, b, a+b)
+}
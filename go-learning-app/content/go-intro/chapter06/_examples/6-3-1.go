@@ -0,0 +1,33 @@
+package main
+
+import (
+    "fmt"
+    "time"
+)
+
+func main() {
+    ch1 := make(chan string)
+    ch2 := make(chan string)
+
+    go func() {
+        time.Sleep(100 * time.Millisecond)
+        ch1 <- "ch1のデータ"
+    }()
+
+    go func() {
+        time.Sleep(200 * time.Millisecond)
+        ch2 <- "ch2のデータ"
+    }()
+
+    // 2つのチャネルから受信
+    for i := 0; i < 2; i++ {
+        select {
+        case msg := <-ch1:
+            fmt.Println("受信:", msg)
+        case msg := <-ch2:
+            fmt.Println("受信:", msg)
+        case <-time.After(1 * time.Second):
+            fmt.Println("タイムアウト")
+        }
+    }
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+    "fmt"
+    "sync"
+)
+
+var once sync.Once
+
+func initialize() {
+    fmt.Println("初期化は一度だけ実行されます")
+}
+
+func main() {
+    var wg sync.WaitGroup
+    for i := 0; i < 5; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            once.Do(initialize) // 最初の1回だけ実行
+        }()
+    }
+    wg.Wait()
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+    "fmt"
+    "time"
+)
+
+func countdown() {
+    // 3から1までループ
+    for i := 3; i > 0; i-- {
+        fmt.Println(i)
+        // 1秒待機
+        time.Sleep(time.Second)
+    }
+}
+
+func main() {
+    // ゴルーチン起動
+    
+    // メインゴルーチンで待機
+    fmt.Println("Start")
+    time.Sleep(3500 * time.Millisecond)
+    fmt.Println("Finish")
+}
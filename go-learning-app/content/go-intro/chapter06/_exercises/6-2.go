@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+func main() {
+    // チャネル作成
+    message := make(chan string)
+    
+    // ゴルーチン起動
+    go func() {
+        // メッセージ送信
+        
+    }()
+    
+    // 受信して表示
+    fmt.Println(<-message)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+    "fmt"
+    "time"
+)
+
+func main() {
+    ch1 := make(chan string)
+    ch2 := make(chan string)
+    
+    go func() {
+        time.Sleep(2 * time.Second)
+        ch1 <- "亀"
+    }()
+    
+    go func() {
+        time.Sleep(1 * time.Second)
+        ch2 <- "ウサギ"
+    }()
+    
+    // selectで待機（先に到着した方だけ表示）
+    select {
+    
+    
+    }
+}
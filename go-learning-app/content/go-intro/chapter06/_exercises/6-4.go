@@ -0,0 +1,24 @@
+package main
+
+import (
+    "fmt"
+    "sync"
+)
+
+func main() {
+    var wg sync.WaitGroup
+    
+    for i := 0; i < 3; i++ {
+        // Add呼び出し
+        
+        go func(id int) {
+            // Done呼び出し
+            
+            fmt.Printf("Goroutine %d finished\n", id)
+        }(i)
+    }
+    
+    // 待機
+    
+    fmt.Println("All done")
+}
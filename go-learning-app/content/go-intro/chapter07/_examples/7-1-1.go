@@ -0,0 +1,32 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "strconv"
+)
+
+func parseAge(s string) (int, error) {
+    age, err := strconv.Atoi(s)
+    if err != nil {
+        return 0, fmt.Errorf("年齢の解析に失敗: %w", err)
+    }
+    if age < 0 || age > 150 {
+        return 0, errors.New("年齢は0から150の範囲で指定してください")
+    }
+    return age, nil
+}
+
+func main() {
+    age, err := parseAge("25")
+    if err != nil {
+        fmt.Println("エラー:", err)
+        return
+    }
+    fmt.Println("年齢:", age)
+
+    _, err = parseAge("abc")
+    if err != nil {
+        fmt.Println("エラー:", err)
+    }
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+)
+
+// カスタムエラー型
+type ValidationError struct {
+    Field   string
+    Message string
+}
+
+func (e *ValidationError) Error() string {
+    return fmt.Sprintf("検証エラー [%s]: %s", e.Field, e.Message)
+}
+
+func validateAge(age int) error {
+    if age < 0 {
+        return &ValidationError{
+            Field:   "age",
+            Message: "年齢は0以上である必要があります",
+        }
+    }
+    return nil
+}
+
+func main() {
+    err := validateAge(-5)
+    if err != nil {
+        // errors.As で型を判定
+        var ve *ValidationError
+        if errors.As(err, &ve) {
+            fmt.Printf("フィールド: %s\n", ve.Field)
+            fmt.Printf("メッセージ: %s\n", ve.Message)
+        }
+    }
+}
@@ -0,0 +1,22 @@
+package main
+
+import "fmt"
+
+func safeDivide(a, b int) (result int, err error) {
+    defer func() {
+        if r := recover(); r != nil {
+            err = fmt.Errorf("回復: %v", r)
+        }
+    }()
+
+    // b が 0 の場合 panic する（例示目的）
+    return a / b, nil
+}
+
+func main() {
+    result, err := safeDivide(10, 2)
+    fmt.Printf("10/2 = %d, err = %v\n", result, err)
+
+    result, err = safeDivide(10, 0)
+    fmt.Printf("10/0 = %d, err = %v\n", result, err)
+}
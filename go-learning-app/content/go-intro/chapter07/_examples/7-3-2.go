@@ -0,0 +1,15 @@
+package main
+
+import "fmt"
+
+func main() {
+    fmt.Println("開始")
+
+    defer fmt.Println("defer 1")
+    defer fmt.Println("defer 2")
+    defer fmt.Println("defer 3")
+
+    fmt.Println("終了")
+    // 出力: 開始 → 終了 → defer 3 → defer 2 → defer 1
+    // (LIFO: 後入れ先出し)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+)
+
+func divide(a, b int) (int, error) {
+    if b == 0 {
+        // エラーを返す
+        
+    }
+    return a / b, nil
+}
+
+func main() {
+    res, err := divide(10, 0)
+    if err != nil {
+        fmt.Println("エラー:", err)
+    } else {
+        fmt.Println("結果:", res)
+    }
+}
@@ -0,0 +1,21 @@
+package main
+
+import "fmt"
+
+// カスタムエラー定義
+
+// 支払い関数
+func Pay(balance, amount int) error {
+    if balance < amount {
+        // エラーを返す
+        
+    }
+    return nil
+}
+
+func main() {
+    err := Pay(100, 200)
+    if err != nil {
+        fmt.Println(err)
+    }
+}
@@ -0,0 +1,19 @@
+package main
+
+import "fmt"
+
+func dangerous() {
+    panic("大変だ！")
+}
+
+func safeCall() {
+    // deferとrecoverで回復
+    
+    
+    dangerous()
+}
+
+func main() {
+    safeCall()
+    fmt.Println("メインは正常に終了")
+}
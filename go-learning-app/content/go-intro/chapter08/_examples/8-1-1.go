@@ -0,0 +1,27 @@
+// ディレクトリ構成:
+// myproject/
+// ├── main.go          (package main)
+// ├── math/
+// │   └── math.go      (package math)
+// └── utils/
+//     └── helper.go    (package utils)
+
+// --- math/math.go ---
+package math
+
+func Add(a, b int) int {
+    return a + b
+}
+
+// --- main.go ---
+package main
+
+import (
+    "fmt"
+    "myproject/math"
+)
+
+func main() {
+    result := math.Add(3, 5)
+    fmt.Println(result) // 8
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+    "fmt"                     // 標準ライブラリ
+    "myproject/utils"         // 自作パッケージ
+
+    // エイリアス
+    m "myproject/math"
+
+    // ブランクインポート（副作用のみ）
+    _ "image/png"
+)
+
+func main() {
+    fmt.Println(m.Add(1, 2))
+    fmt.Println(utils.Helper())
+}
@@ -0,0 +1,25 @@
+// 1. プロジェクトディレクトリを作成
+// mkdir myproject && cd myproject
+
+// 2. モジュールを初期化
+// go mod init github.com/yourname/myproject
+
+// 3. コードを書く（main.go）
+package main
+
+import (
+    "fmt"
+    "github.com/gorilla/mux"
+    "net/http"
+)
+
+func main() {
+    r := mux.NewRouter()
+    r.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+        fmt.Fprintln(w, "Hello!")
+    })
+    http.ListenAndServe(":8080", r)
+}
+
+// 4. 依存関係を解決
+// go mod tidy
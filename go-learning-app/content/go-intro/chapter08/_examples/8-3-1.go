@@ -0,0 +1,34 @@
+package user
+
+import "fmt"
+
+// User は公開型（大文字で始まる）
+type User struct {
+    Name  string // 公開フィールド
+    Email string // 公開フィールド
+    age   int    // 非公開フィールド
+}
+
+// NewUser は公開関数
+func NewUser(name, email string, age int) *User {
+    return &User{
+        Name:  name,
+        Email: email,
+        age:   age,
+    }
+}
+
+// GetAge は公開メソッド（非公開フィールドへのアクセサ）
+func (u *User) GetAge() int {
+    return u.age
+}
+
+// validate は非公開関数
+func validate(email string) bool {
+    return len(email) > 0
+}
+
+// String は公開メソッド
+func (u *User) String() string {
+    return fmt.Sprintf("%s <%s>", u.Name, u.Email)
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+    "fmt"
+    // strings と math をインポート
+    
+    
+)
+
+func main() {
+    text := "hello world"
+    num := 16.0
+    
+    // 大文字変換して表示
+    
+    
+    // 平方根を表示
+    
+}
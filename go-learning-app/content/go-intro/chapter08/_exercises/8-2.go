@@ -0,0 +1,11 @@
+package main
+
+import "fmt"
+
+func main() {
+    command := "go mod tidy"
+    description := "依存関係を整理するコマンド"
+    
+    // コマンドと説明を表示
+    
+}
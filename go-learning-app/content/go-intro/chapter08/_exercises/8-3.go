@@ -0,0 +1,19 @@
+package main
+
+import "fmt"
+
+// Product 構造体定義
+
+// SetPrice メソッド
+
+// Price メソッド
+
+func main() {
+    p := Product{Name: "Laptop"}
+    
+    // 価格を設定
+    p.SetPrice(150000)
+    
+    // 価格を表示
+    fmt.Printf("製品: %s, 価格: %d\n", p.Name, p.Price())
+}
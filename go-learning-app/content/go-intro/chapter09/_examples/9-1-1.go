@@ -0,0 +1,37 @@
+// math.go
+package math
+
+func Add(a, b int) int {
+    return a + b
+}
+
+func Abs(n int) int {
+    if n < 0 {
+        return -n
+    }
+    return n
+}
+
+// math_test.go
+package math
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+    result := Add(2, 3)
+    if result != 5 {
+        t.Errorf("Add(2, 3) = %d; want 5", result)
+    }
+}
+
+func TestAbs(t *testing.T) {
+    if Abs(-5) != 5 {
+        t.Error("Abs(-5) should be 5")
+    }
+    if Abs(5) != 5 {
+        t.Error("Abs(5) should be 5")
+    }
+    if Abs(0) != 0 {
+        t.Error("Abs(0) should be 0")
+    }
+}
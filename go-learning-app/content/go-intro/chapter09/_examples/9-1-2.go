@@ -0,0 +1,23 @@
+package math
+
+import "testing"
+
+func TestAddSubtests(t *testing.T) {
+    t.Run("正の数", func(t *testing.T) {
+        if Add(2, 3) != 5 {
+            t.Error("2+3 should be 5")
+        }
+    })
+
+    t.Run("負の数", func(t *testing.T) {
+        if Add(-2, -3) != -5 {
+            t.Error("-2+(-3) should be -5")
+        }
+    })
+
+    t.Run("ゼロ", func(t *testing.T) {
+        if Add(0, 0) != 0 {
+            t.Error("0+0 should be 0")
+        }
+    })
+}
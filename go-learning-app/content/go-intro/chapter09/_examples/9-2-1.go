@@ -0,0 +1,28 @@
+package math
+
+import "testing"
+
+func TestAdd_TableDriven(t *testing.T) {
+    tests := []struct {
+        name     string
+        a, b     int
+        expected int
+    }{
+        {"正の数同士", 2, 3, 5},
+        {"負の数同士", -2, -3, -5},
+        {"正と負", 5, -3, 2},
+        {"ゼロ加算", 0, 5, 5},
+        {"両方ゼロ", 0, 0, 0},
+        {"大きな数", 1000000, 2000000, 3000000},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := Add(tt.a, tt.b)
+            if result != tt.expected {
+                t.Errorf("Add(%d, %d) = %d; want %d",
+                    tt.a, tt.b, result, tt.expected)
+            }
+        })
+    }
+}
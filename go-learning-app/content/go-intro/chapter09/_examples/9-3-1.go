@@ -0,0 +1,25 @@
+package math
+
+import "testing"
+
+func BenchmarkAdd(b *testing.B) {
+    for i := 0; i < b.N; i++ {
+        Add(100, 200)
+    }
+}
+
+// メモリアロケーションも計測
+func BenchmarkConcat(b *testing.B) {
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        s := ""
+        for j := 0; j < 100; j++ {
+            s += "a"
+        }
+    }
+}
+
+// 実行: go test -bench=. -benchmem
+// 出力例:
+// BenchmarkAdd-8      1000000000    0.29 ns/op    0 B/op    0 allocs/op
+// BenchmarkConcat-8       50000    30000 ns/op   5000 B/op   99 allocs/op
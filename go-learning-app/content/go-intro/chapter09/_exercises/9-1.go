@@ -0,0 +1,20 @@
+package main
+
+import "fmt"
+
+func Square(n int) int {
+    // 実装
+    return 0
+}
+
+func main() {
+    result := Square(5)
+    expected := 25
+    
+    // 比較して PASS/FAIL を表示
+    if result == expected {
+        fmt.Println("PASS")
+    } else {
+        fmt.Printf("FAIL: want %d, got %d\n", expected, result)
+    }
+}
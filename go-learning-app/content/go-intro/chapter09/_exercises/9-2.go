@@ -0,0 +1,24 @@
+package main
+
+import "fmt"
+
+func IsEven(n int) bool {
+    return n % 2 == 0
+}
+
+func main() {
+    tests := []struct {
+        input    int
+        expected bool
+    }{
+        {2, true},
+        {3, false},
+        {0, true},
+        {-1, false},
+    }
+    
+    // テーブルをループしてテスト実行
+    for _, tt := range tests {
+        // 結果を表示
+    }
+}
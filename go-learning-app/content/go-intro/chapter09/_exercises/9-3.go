@@ -0,0 +1,21 @@
+package main
+
+import (
+    "fmt"
+    "testing"
+)
+
+func Concat(a, b string) string {
+    return a + b
+}
+
+// BenchmarkConcat をここに実装
+func BenchmarkConcat(b *testing.B) {
+    
+}
+
+func main() {
+    // ベンチマークは実行できませんが、コードが正しいか確認します
+    var _ func(*testing.B) = BenchmarkConcat
+    fmt.Println("Code compiled successfully")
+}
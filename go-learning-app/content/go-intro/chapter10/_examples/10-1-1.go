@@ -0,0 +1,32 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+)
+
+func main() {
+    // フラグの定義
+    name := flag.String("name", "World", "挨拶する相手")
+    count := flag.Int("count", 1, "繰り返し回数")
+    upper := flag.Bool("upper", false, "大文字に変換")
+
+    // フラグのパース
+    flag.Parse()
+
+    // フラグの使用
+    for i := 0; i < *count; i++ {
+        msg := fmt.Sprintf("Hello, %s!", *name)
+        if *upper {
+            msg = fmt.Sprintf("HELLO, %s!", *name)
+        }
+        fmt.Println(msg)
+    }
+
+    // 残りの引数
+    fmt.Println("残りの引数:", flag.Args())
+}
+
+// 実行例:
+// go run main.go -name=Go -count=3
+// go run main.go -upper -name=World
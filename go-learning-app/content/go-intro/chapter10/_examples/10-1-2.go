@@ -0,0 +1,26 @@
+package main
+
+import (
+    "bufio"
+    "fmt"
+    "os"
+    "strings"
+)
+
+func main() {
+    scanner := bufio.NewScanner(os.Stdin)
+
+    fmt.Print("名前を入力: ")
+    if scanner.Scan() {
+        name := strings.TrimSpace(scanner.Text())
+        fmt.Printf("こんにちは、%sさん！\n", name)
+    }
+
+    // 複数行の入力
+    fmt.Println("テキストを入力（Ctrl+Dで終了）:")
+    lines := 0
+    for scanner.Scan() {
+        lines++
+    }
+    fmt.Printf("%d行読み込みました\n", lines)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "net/http"
+)
+
+func main() {
+    mux := http.NewServeMux()
+
+    // 基本的なハンドラー
+    mux.HandleFunc("GET /hello", func(w http.ResponseWriter, r *http.Request) {
+        fmt.Fprintln(w, "Hello, World!")
+    })
+
+    // パスパラメータ（Go 1.22+）
+    mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+        id := r.PathValue("id")
+        fmt.Fprintf(w, "User ID: %s\n", id)
+    })
+
+    // ミドルウェア
+    handler := loggingMiddleware(mux)
+
+    fmt.Println("サーバー起動: http://localhost:8080")
+    log.Fatal(http.ListenAndServe(":8080", handler))
+}
+
+func loggingMiddleware(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        log.Printf("%s %s", r.Method, r.URL.Path)
+        next.ServeHTTP(w, r)
+    })
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+)
+
+type User struct {
+    ID       int    `json:"id"`
+    Name     string `json:"name"`
+    Email    string `json:"email"`
+    Password string `json:"-"`           // JSON出力から除外
+    Age      int    `json:"age,omitempty"` // ゼロ値なら省略
+}
+
+func main() {
+    // エンコード（Go → JSON）
+    user := User{
+        ID:       1,
+        Name:     "Alice",
+        Email:    "alice@example.com",
+        Password: "secret",
+    }
+
+    data, err := json.MarshalIndent(user, "", "  ")
+    if err != nil {
+        log.Fatal(err)
+    }
+    fmt.Println(string(data))
+    // {"id":1,"name":"Alice","email":"alice@example.com"}
+    // Password は除外、Age は omitempty で省略
+
+    // デコード（JSON → Go）
+    jsonStr := `{"id":2,"name":"Bob","email":"bob@example.com","age":25}`
+    var user2 User
+    err = json.Unmarshal([]byte(jsonStr), &user2)
+    if err != nil {
+        log.Fatal(err)
+    }
+    fmt.Printf("%+v\n", user2)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+    "encoding/json"
+    "net/http"
+)
+
+type Response struct {
+    Status  string `json:"status"`
+    Message string `json:"message"`
+}
+
+func jsonHandler(w http.ResponseWriter, r *http.Request) {
+    // リクエストボディのデコード
+    var req struct {
+        Name string `json:"name"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(Response{
+            Status: "error", Message: "不正なJSON",
+        })
+        return
+    }
+
+    // レスポンスの返却
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(Response{
+        Status:  "ok",
+        Message: "Hello, " + req.Name,
+    })
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+    "fmt"
+    "os"
+)
+
+func main() {
+    // os.Argsをチェック
+    // os.Args[0]はプログラム名なので、os.Args[1]以降を確認
+    
+    // 引数を渡す方法: エディタではコマンドライン引数を渡せませんが、
+    // os.Argsを擬似的に書き換えてテストしてみましょう
+    // os.Args = []string{"prog", "Go"}
+}
+
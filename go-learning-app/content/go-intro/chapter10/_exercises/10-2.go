@@ -0,0 +1,24 @@
+package main
+
+import (
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+)
+
+func helloHandler(w http.ResponseWriter, r *http.Request) {
+    fmt.Fprint(w, "OK")
+}
+
+func main() {
+    // リクエスト作成
+    req := httptest.NewRequest("GET", "/", nil)
+    // レスポンス記録用レコーダー
+    rec := httptest.NewRecorder()
+    
+    // ハンドラー呼び出し
+    helloHandler(rec, req)
+    
+    // 結果表示
+    fmt.Println("Response:", rec.Body.String())
+}
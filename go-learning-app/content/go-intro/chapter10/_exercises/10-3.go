@@ -0,0 +1,17 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+)
+
+// Item構造体を定義
+
+func main() {
+    item := Item{Name: "Apple", Price: 100}
+    
+    // JSONに変換
+    
+    // 表示
+    
+}
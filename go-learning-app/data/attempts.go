@@ -0,0 +1,294 @@
+package data
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-learning-app/models"
+)
+
+var (
+	// ErrNotFound is returned when a requested record does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadySubmitted is returned when a quiz session is submitted twice.
+	ErrAlreadySubmitted = errors.New("already submitted")
+)
+
+// StartQuizSession stores a new quiz session on the given version of a
+// lesson quiz and returns its ID.
+func (db *CourseDB) StartQuizSession(username, lessonID, quizVersion string, seed int64, questionIDs []string) (int64, error) {
+	ids, err := json.Marshal(questionIDs)
+	if err != nil {
+		return 0, fmt.Errorf("start quiz session: %w", err)
+	}
+	res, err := db.conn.Exec(
+		"INSERT INTO quiz_sessions (username, course_id, lesson_id, quiz_version, seed, question_ids) VALUES (?, ?, ?, ?, ?, ?)",
+		username, db.course, lessonID, quizVersion, seed, string(ids),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("start quiz session: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// GetQuizSession returns a quiz session of the course by ID, or
+// ErrNotFound.
+func (db *CourseDB) GetQuizSession(id int64) (models.QuizSession, error) {
+	var s models.QuizSession
+	var ids string
+	err := db.conn.QueryRow(`
+SELECT id, username, lesson_id, quiz_version, seed, question_ids, started_at, submitted_at IS NOT NULL
+FROM quiz_sessions WHERE id = ? AND course_id = ?`, id, db.course,
+	).Scan(&s.ID, &s.Username, &s.LessonID, &s.QuizVersion, &s.Seed, &ids, &s.StartedAt, &s.Submitted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, ErrNotFound
+	}
+	if err != nil {
+		return s, fmt.Errorf("get quiz session: %w", err)
+	}
+	if err := json.Unmarshal([]byte(ids), &s.QuestionIDs); err != nil {
+		return s, fmt.Errorf("decode quiz session %d: %w", id, err)
+	}
+	return s, nil
+}
+
+// CountAttempts returns how many graded attempts the user has made on a
+// lesson quiz.
+func (db *CourseDB) CountAttempts(username, lessonID string) (int, error) {
+	var n int
+	err := db.conn.QueryRow(
+		"SELECT COUNT(*) FROM quiz_attempts WHERE username = ? AND course_id = ? AND lesson_id = ?",
+		username, db.course, lessonID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count attempts: %w", err)
+	}
+	return n, nil
+}
+
+// RecentlyCorrect returns the IDs of questions the user answered correctly
+// in their last n attempts on a lesson quiz.
+func (db *CourseDB) RecentlyCorrect(username, lessonID string, n int) (map[string]bool, error) {
+	rows, err := db.conn.Query(`
+SELECT DISTINCT a.question_id FROM quiz_answers a
+WHERE a.correct AND a.attempt_id IN (
+    SELECT id FROM quiz_attempts WHERE username = ? AND course_id = ? AND lesson_id = ? ORDER BY id DESC LIMIT ?
+)`,
+		username, db.course, lessonID, n,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get recently correct: %w", err)
+	}
+	defer rows.Close()
+
+	correct := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan recently correct: %w", err)
+		}
+		correct[id] = true
+	}
+	return correct, rows.Err()
+}
+
+// RecordAttempt stores a graded quiz attempt with its answers, closes its
+// session and updates the lesson's quiz component: the best score is kept,
+// the quiz is marked passed the first time an attempt passes, and a passing
+// attempt records the quiz version it was made on. It returns
+// the new attempt ID, or ErrAlreadySubmitted if the session was closed.
+func (db *CourseDB) RecordAttempt(a models.QuizAttempt) (int64, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("record attempt: %w", err)
+	}
+	defer tx.Rollback()
+
+	closed, err := tx.Exec(
+		"UPDATE quiz_sessions SET submitted_at = CURRENT_TIMESTAMP WHERE id = ? AND course_id = ? AND submitted_at IS NULL",
+		a.SessionID, db.course,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("close quiz session: %w", err)
+	}
+	if n, _ := closed.RowsAffected(); n == 0 {
+		return 0, ErrAlreadySubmitted
+	}
+
+	res, err := tx.Exec(`
+INSERT INTO quiz_attempts (username, course_id, lesson_id, quiz_version, correct, total, score, passed, duration_ms, session_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.Username, db.course, a.LessonID, a.QuizVersion, a.Correct, a.Total, a.Score, a.Passed, a.DurationMs, a.SessionID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("record attempt: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("record attempt: %w", err)
+	}
+
+	for _, ans := range a.Answers {
+		if _, err := tx.Exec(
+			"INSERT INTO quiz_answers (attempt_id, question_id, answer, correct) VALUES (?, ?, ?, ?)",
+			id, ans.QuestionID, string(ans.Answer), ans.Correct,
+		); err != nil {
+			return 0, fmt.Errorf("record answer: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`
+INSERT INTO lesson_progress (username, course_id, lesson_id, quiz_score, quiz_passed_at, quiz_version, quiz_version_at)
+VALUES (?1, ?6, ?2, ?3, CASE WHEN ?4 THEN CURRENT_TIMESTAMP END,
+        CASE WHEN ?4 THEN NULLIF(?5, '') END, CASE WHEN ?4 THEN CURRENT_TIMESTAMP END)
+ON CONFLICT (username, course_id, lesson_id) DO UPDATE SET
+    quiz_score      = MAX(COALESCE(quiz_score, 0), excluded.quiz_score),
+    quiz_passed_at  = COALESCE(quiz_passed_at, excluded.quiz_passed_at),
+    quiz_version_at = CASE WHEN excluded.quiz_version IS NULL OR quiz_version IS excluded.quiz_version
+        THEN quiz_version_at ELSE excluded.quiz_version_at END,
+    quiz_version    = COALESCE(excluded.quiz_version, quiz_version)`,
+		a.Username, a.LessonID, a.Score, a.Passed, a.QuizVersion, db.course,
+	); err != nil {
+		return 0, fmt.Errorf("record quiz score: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("record attempt: %w", err)
+	}
+	return id, nil
+}
+
+// GetAttempts returns a user's attempts on a lesson quiz, oldest first,
+// including the per-question answers.
+func (db *CourseDB) GetAttempts(username, lessonID string) ([]models.QuizAttempt, error) {
+	return db.queryAttempts("t.username = ? AND t.lesson_id = ?", username, lessonID)
+}
+
+// GetLessonAttempts returns every learner's attempts on a lesson quiz,
+// oldest first, including the per-question answers.
+func (db *CourseDB) GetLessonAttempts(lessonID string) ([]models.QuizAttempt, error) {
+	return db.queryAttempts("t.lesson_id = ?", lessonID)
+}
+
+// queryAttempts loads the course's attempts matching where, a condition
+// on quiz_attempts aliased as t, together with their answers.
+func (db *CourseDB) queryAttempts(where string, args ...any) ([]models.QuizAttempt, error) {
+	where = "t.course_id = ? AND " + where
+	args = append([]any{db.course}, args...)
+	rows, err := db.conn.Query(`
+SELECT id, username, lesson_id, quiz_version, correct, total, score, passed, duration_ms, submitted_at
+FROM quiz_attempts t WHERE `+where+` ORDER BY id`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []models.QuizAttempt
+	index := make(map[int64]int)
+	for rows.Next() {
+		var a models.QuizAttempt
+		if err := rows.Scan(&a.ID, &a.Username, &a.LessonID, &a.QuizVersion, &a.Correct, &a.Total,
+			&a.Score, &a.Passed, &a.DurationMs, &a.SubmittedAt); err != nil {
+			return nil, fmt.Errorf("scan attempt: %w", err)
+		}
+		index[a.ID] = len(attempts)
+		attempts = append(attempts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get attempts: %w", err)
+	}
+
+	answers, err := db.conn.Query(`
+SELECT a.attempt_id, a.question_id, a.answer, a.correct
+FROM quiz_answers a JOIN quiz_attempts t ON t.id = a.attempt_id
+WHERE `+where+` ORDER BY a.attempt_id, a.rowid`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get answers: %w", err)
+	}
+	defer answers.Close()
+
+	for answers.Next() {
+		var id int64
+		var ans models.AttemptAnswer
+		var raw string
+		if err := answers.Scan(&id, &ans.QuestionID, &raw, &ans.Correct); err != nil {
+			return nil, fmt.Errorf("scan answer: %w", err)
+		}
+		ans.Answer = []byte(raw)
+		if i, ok := index[id]; ok {
+			attempts[i].Answers = append(attempts[i].Answers, ans)
+		}
+	}
+	return attempts, answers.Err()
+}
+
+// GetScores returns the attempt count and the best and latest quiz score
+// for every lesson the user has attempted, in order of first attempt.
+func (db *CourseDB) GetScores(username string) ([]models.LessonScores, error) {
+	rows, err := db.conn.Query(
+		"SELECT lesson_id, score, submitted_at FROM quiz_attempts WHERE username = ? AND course_id = ? ORDER BY id",
+		username, db.course,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get scores: %w", err)
+	}
+	defer rows.Close()
+
+	var scores []models.LessonScores
+	index := make(map[string]int)
+	for rows.Next() {
+		var lessonID string
+		var score int
+		var at time.Time
+		if err := rows.Scan(&lessonID, &score, &at); err != nil {
+			return nil, fmt.Errorf("scan scores: %w", err)
+		}
+		i, ok := index[lessonID]
+		if !ok {
+			i = len(scores)
+			index[lessonID] = i
+			scores = append(scores, models.LessonScores{LessonID: lessonID})
+		}
+		s := &scores[i]
+		s.Attempts++
+		s.Best = max(s.Best, score)
+		s.Latest = score
+		s.LatestAt = at
+	}
+	return scores, rows.Err()
+}
+
+// GetQuestionStats returns how often each question was answered correctly
+// across all users, hardest first. An empty lessonID covers every quiz.
+func (db *CourseDB) GetQuestionStats(lessonID string) ([]models.QuestionStats, error) {
+	rows, err := db.conn.Query(`
+SELECT a.question_id, COUNT(*), SUM(a.correct)
+FROM quiz_answers a JOIN quiz_attempts t ON t.id = a.attempt_id
+WHERE t.course_id = ? AND (? = '' OR t.lesson_id = ?)
+GROUP BY a.question_id
+ORDER BY CAST(SUM(a.correct) AS REAL) / COUNT(*), a.question_id`,
+		db.course, lessonID, lessonID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get question stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.QuestionStats
+	for rows.Next() {
+		var s models.QuestionStats
+		if err := rows.Scan(&s.QuestionID, &s.Attempts, &s.Correct); err != nil {
+			return nil, fmt.Errorf("scan question stats: %w", err)
+		}
+		s.Percent = s.Correct * 100 / s.Attempts
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,263 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go-learning-app/models"
+)
+
+// Draft is an editable copy of a store's content. Edits only keep the copy
+// consistent enough to export; the content itself is checked by loading
+// the exported files, see Check.
+type Draft struct {
+	s *Store
+}
+
+// Draft returns an editable copy of the store's Japanese content. The
+// store must be a base store, not a translation.
+func (s *Store) Draft() *Draft {
+	c := s.clone(s.Locale)
+	c.translationSources = slices.Clone(s.translationSources)
+	c.assets = maps.Clone(s.assets)
+	return &Draft{s: c}
+}
+
+// NewDraft returns a draft of a course that has no chapters yet.
+func NewDraft(course models.Course) *Draft {
+	s := newStore()
+	s.Course = course
+	return &Draft{s: s}
+}
+
+// Files returns the draft in the content file layout.
+func (d *Draft) Files() (map[string][]byte, error) {
+	return d.s.Files()
+}
+
+// Check loads the draft's files and returns the resulting store with the
+// files, or the content errors that loading reports, each a *ContentError
+// with its path in the catalog.
+func (d *Draft) Check() (*Store, map[string][]byte, error) {
+	files, err := d.Files()
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := LoadStore(mapFS(files))
+	if err != nil {
+		return nil, nil, errors.Join(inDir(d.s.Course.ID, err)...)
+	}
+	return s, files, nil
+}
+
+// PutCourse updates the title, description and catalog order of the
+// course. Its ID cannot change.
+func (d *Draft) PutCourse(c models.Course) {
+	c.ID = d.s.Course.ID
+	d.s.Course = c
+}
+
+// PutChapter creates a chapter, or updates the title, description, gating
+// and policy of an existing one, and reports whether it was created.
+// Chapters are kept in ID order, which is the order they are taught in.
+func (d *Draft) PutChapter(ch models.Chapter) bool {
+	i := d.chapterIndex(ch.ID)
+	if i >= 0 {
+		ch.Lessons = d.s.Chapters[i].Lessons
+		d.s.Chapters[i] = ch
+		return false
+	}
+	ch.Lessons = nil
+	d.s.Chapters = append(d.s.Chapters, ch)
+	slices.SortFunc(d.s.Chapters, func(a, b models.Chapter) int { return a.ID - b.ID })
+	return true
+}
+
+// DeleteChapter deletes a chapter with its lessons, quizzes and
+// translations.
+func (d *Draft) DeleteChapter(id int) error {
+	i := d.chapterIndex(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	for _, summary := range d.s.Chapters[i].Lessons {
+		delete(d.s.lessons, summary.ID)
+		delete(d.s.quizzes, summary.ID)
+	}
+	d.s.Chapters = slices.Delete(d.s.Chapters, i, i+1)
+	d.s.translationSources = slices.DeleteFunc(d.s.translationSources, func(src translationSource) bool {
+		return src.chapterID == id
+	})
+	return nil
+}
+
+// PutLesson creates a lesson at the end of a chapter, or replaces an
+// existing one, moving it if its chapter changed, and reports whether it
+// was created. Code files are renamed after the lesson, so the examples
+// and exercise can be given in any order. It returns ErrNotFound if the
+// chapter does not exist.
+func (d *Draft) PutLesson(l models.Lesson) (bool, error) {
+	to := d.chapterIndex(l.ChapterID)
+	if to < 0 {
+		return false, ErrNotFound
+	}
+	old, exists := d.s.lessons[l.ID]
+	l.CodeExamples = slices.Clone(l.CodeExamples)
+	for i := range l.CodeExamples {
+		ex := &l.CodeExamples[i]
+		ex.File = ""
+		// A recorded output is kept as long as the code it was recorded
+		// for is unchanged; new code is run by the validate command.
+		ex.Output = ""
+		for _, prev := range old.CodeExamples {
+			if prev.Code == ex.Code && prev.Output != "" {
+				ex.Output = prev.Output
+				break
+			}
+		}
+	}
+	if l.Exercise != nil {
+		ex := *l.Exercise
+		ex.StarterFile = ""
+		l.Exercise = &ex
+	}
+
+	summary := models.LessonSummary{ID: l.ID, Title: l.Title, Requires: l.Requires}
+	d.s.lessons[l.ID] = l
+	if exists && old.ChapterID == l.ChapterID {
+		lessons := d.s.Chapters[to].Lessons
+		lessons[slices.IndexFunc(lessons, func(s models.LessonSummary) bool { return s.ID == l.ID })] = summary
+		return false, nil
+	}
+	if exists {
+		from := d.chapterIndex(old.ChapterID)
+		d.s.Chapters[from].Lessons = slices.DeleteFunc(d.s.Chapters[from].Lessons, func(s models.LessonSummary) bool {
+			return s.ID == l.ID
+		})
+		for i, src := range d.s.translationSources {
+			if isLessonFile(src.name, l.ID) {
+				d.s.translationSources[i].chapterID = l.ChapterID
+			}
+		}
+	}
+	d.s.Chapters[to].Lessons = append(d.s.Chapters[to].Lessons, summary)
+	return !exists, nil
+}
+
+// DeleteLesson deletes a lesson with its quiz and translations.
+func (d *Draft) DeleteLesson(id string) error {
+	l, ok := d.s.lessons[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(d.s.lessons, id)
+	delete(d.s.quizzes, id)
+	i := d.chapterIndex(l.ChapterID)
+	d.s.Chapters[i].Lessons = slices.DeleteFunc(d.s.Chapters[i].Lessons, func(s models.LessonSummary) bool {
+		return s.ID == id
+	})
+	d.s.translationSources = slices.DeleteFunc(d.s.translationSources, func(src translationSource) bool {
+		return isLessonFile(src.name, id)
+	})
+	return nil
+}
+
+// OrderLessons reorders the lessons of a chapter. ids must list every
+// lesson of the chapter once.
+func (d *Draft) OrderLessons(chapterID int, ids []string) error {
+	i := d.chapterIndex(chapterID)
+	if i < 0 {
+		return ErrNotFound
+	}
+	lessons := d.s.Chapters[i].Lessons
+	if len(ids) != len(lessons) {
+		return fmt.Errorf("%d lesson ids for %d lessons", len(ids), len(lessons))
+	}
+	ordered := make([]models.LessonSummary, 0, len(ids))
+	for _, id := range ids {
+		j := slices.IndexFunc(lessons, func(s models.LessonSummary) bool { return s.ID == id })
+		if j < 0 || slices.ContainsFunc(ordered, func(s models.LessonSummary) bool { return s.ID == id }) {
+			return fmt.Errorf("lesson %q is not in chapter %d or listed twice", id, chapterID)
+		}
+		ordered = append(ordered, lessons[j])
+	}
+	d.s.Chapters[i].Lessons = ordered
+	return nil
+}
+
+// PutQuiz creates or replaces the quiz of a lesson, and reports whether it
+// was created.
+func (d *Draft) PutQuiz(q models.Quiz) (bool, error) {
+	if _, ok := d.s.lessons[q.LessonID]; !ok {
+		return false, ErrNotFound
+	}
+	_, exists := d.s.quizzes[q.LessonID]
+	d.s.quizzes[q.LessonID] = q
+	return !exists, nil
+}
+
+// DeleteQuiz deletes the quiz of a lesson with its translation.
+func (d *Draft) DeleteQuiz(lessonID string) error {
+	if _, ok := d.s.quizzes[lessonID]; !ok {
+		return ErrNotFound
+	}
+	delete(d.s.quizzes, lessonID)
+	d.s.translationSources = slices.DeleteFunc(d.s.translationSources, func(src translationSource) bool {
+		return isLessonFile(src.name, lessonID) && strings.HasSuffix(src.name, quizExt)
+	})
+	return nil
+}
+
+// PutQuestion adds a question to the end of a lesson's quiz, creating the
+// quiz if needed, or replaces the question with the same ID, and reports
+// whether it was added.
+func (d *Draft) PutQuestion(lessonID string, q models.Question) (bool, error) {
+	if _, ok := d.s.lessons[lessonID]; !ok {
+		return false, ErrNotFound
+	}
+	quiz, ok := d.s.quizzes[lessonID]
+	if !ok {
+		quiz = models.Quiz{LessonID: lessonID}
+	}
+	quiz.Questions = slices.Clone(quiz.Questions)
+	i := slices.IndexFunc(quiz.Questions, func(x models.Question) bool { return x.ID == q.ID })
+	if i >= 0 {
+		quiz.Questions[i] = q
+	} else {
+		quiz.Questions = append(quiz.Questions, q)
+	}
+	d.s.quizzes[lessonID] = quiz
+	return i < 0, nil
+}
+
+// DeleteQuestion deletes a question from a lesson's quiz. Deleting the
+// last question deletes the quiz.
+func (d *Draft) DeleteQuestion(lessonID, questionID string) error {
+	quiz, ok := d.s.quizzes[lessonID]
+	if !ok {
+		return ErrNotFound
+	}
+	i := slices.IndexFunc(quiz.Questions, func(x models.Question) bool { return x.ID == questionID })
+	if i < 0 {
+		return ErrNotFound
+	}
+	if len(quiz.Questions) == 1 {
+		return d.DeleteQuiz(lessonID)
+	}
+	quiz.Questions = slices.Delete(slices.Clone(quiz.Questions), i, i+1)
+	d.s.quizzes[lessonID] = quiz
+	return nil
+}
+
+func (d *Draft) chapterIndex(id int) int {
+	return slices.IndexFunc(d.s.Chapters, func(ch models.Chapter) bool { return ch.ID == id })
+}
+
+// isLessonFile reports whether a translation file name, such as
+// "1-1.en.md" or "1-1.en.quiz.yaml", belongs to the lesson.
+func isLessonFile(name, lessonID string) bool {
+	return strings.HasPrefix(name, lessonID+".")
+}
@@ -0,0 +1,179 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"go-learning-app/content"
+	"go-learning-app/models"
+)
+
+// Catalog holds the courses the server hosts, each in its own Store, in
+// catalog order.
+type Catalog struct {
+	courses []*Store
+}
+
+// NewCatalog creates the catalog from the content embedded in the binary.
+func NewCatalog() (*Catalog, error) {
+	return Load(FSSource{content.FS})
+}
+
+// LoadCatalog reads every course in fsys, one directory per course named
+// after its ID:
+//
+//	go-intro/
+//	    course.yaml
+//	    chapter01/ ...
+//	go-web/
+//	    course.yaml
+//	    chapter01/ ...
+//
+// Each course is read as LoadStore describes. Problems in every course are
+// returned joined, each as a *ContentError with its path from the root.
+func LoadCatalog(fsys fs.FS) (*Catalog, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read content: %w", err)
+	}
+	c := &Catalog{}
+	var errs []error
+	for _, e := range entries {
+		dir := e.Name()
+		if !e.IsDir() || strings.HasPrefix(dir, "_") || strings.HasPrefix(dir, ".") {
+			continue
+		}
+		sub, err := fs.Sub(fsys, dir)
+		if err != nil {
+			return nil, fmt.Errorf("read content: %w", err)
+		}
+		s, err := LoadStore(sub)
+		if err != nil {
+			errs = append(errs, inDir(dir, err)...)
+			continue
+		}
+		if s.Course.ID != dir {
+			errs = append(errs, &ContentError{
+				Path: path.Join(dir, courseFile),
+				Msg:  fmt.Sprintf("id %q does not match directory name %q", s.Course.ID, dir),
+			})
+			continue
+		}
+		c.courses = append(c.courses, s)
+	}
+	if len(c.courses) == 0 && len(errs) == 0 {
+		errs = append(errs, &ContentError{Path: ".", Msg: "no course directories found"})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	c.sort()
+	return c, nil
+}
+
+// inDir makes the paths of content errors relative to the parent of dir.
+func inDir(dir string, err error) []error {
+	errs := []error{err}
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = j.Unwrap()
+	}
+	out := make([]error, 0, len(errs))
+	for _, e := range errs {
+		var ce *ContentError
+		if errors.As(e, &ce) {
+			e = &ContentError{Path: path.Join(dir, ce.Path), Line: ce.Line, Msg: ce.Msg}
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func (c *Catalog) sort() {
+	slices.SortFunc(c.courses, func(a, b *Store) int {
+		if a.Course.Order != b.Course.Order {
+			return a.Course.Order - b.Course.Order
+		}
+		return strings.Compare(a.Course.ID, b.Course.ID)
+	})
+}
+
+// Courses returns the Japanese store of every course in catalog order.
+func (c *Catalog) Courses() []*Store {
+	return c.courses
+}
+
+// Course returns the Japanese store of a course.
+func (c *Catalog) Course(id string) (*Store, bool) {
+	i := slices.IndexFunc(c.courses, func(s *Store) bool { return s.Course.ID == id })
+	if i < 0 {
+		return nil, false
+	}
+	return c.courses[i], true
+}
+
+// Locales returns the locales any course is available in, the default
+// locale first.
+func (c *Catalog) Locales() []string {
+	locales := []string{DefaultLocale}
+	for _, s := range c.courses {
+		for _, loc := range s.Locales()[1:] {
+			if !slices.Contains(locales, loc) {
+				locales = append(locales, loc)
+			}
+		}
+	}
+	slices.Sort(locales[1:])
+	return locales
+}
+
+// Summaries lists the courses in the given locale. With progress, the
+// resolved progress of a user keyed by course ID, each course also counts
+// the lessons the user completed.
+func (c *Catalog) Summaries(locale string, progress map[string][]models.LessonProgress) []models.CourseSummary {
+	summaries := []models.CourseSummary{}
+	for _, s := range c.courses {
+		s = s.Localized(locale)
+		sum := models.CourseSummary{Course: s.Course, Chapters: len(s.Chapters)}
+		for _, ch := range s.Chapters {
+			sum.Lessons += len(ch.Lessons)
+		}
+		if progress != nil {
+			done := 0
+			for _, p := range progress[s.Course.ID] {
+				if p.Completed {
+					done++
+				}
+			}
+			sum.Completed = &done
+		}
+		summaries = append(summaries, sum)
+	}
+	return summaries
+}
+
+// With returns a copy of the catalog with s, a course's Japanese store,
+// added or replacing the course with the same ID.
+func (c *Catalog) With(s *Store) *Catalog {
+	out := &Catalog{courses: slices.Clone(c.courses)}
+	if i := slices.IndexFunc(out.courses, func(x *Store) bool { return x.Course.ID == s.Course.ID }); i >= 0 {
+		out.courses[i] = s
+	} else {
+		out.courses = append(out.courses, s)
+	}
+	out.sort()
+	return out
+}
+
+// Without returns a copy of the catalog without a course. It returns
+// ErrNotFound if there is no such course.
+func (c *Catalog) Without(id string) (*Catalog, error) {
+	i := slices.IndexFunc(c.courses, func(s *Store) bool { return s.Course.ID == id })
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	return &Catalog{courses: slices.Delete(slices.Clone(c.courses), i, i+1)}, nil
+}
@@ -0,0 +1,706 @@
+package data
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"go-learning-app/models"
+)
+
+// A course's content files are laid out with one directory per chapter:
+//
+//	course.yaml              course metadata: ID, title, description and
+//	                         its place in the catalog
+//	glossary.yaml            glossary terms, optional
+//	chapter01/
+//	    chapter.yaml         chapter metadata and lesson order
+//	    1-1.md               lesson: YAML front matter, then the Markdown body
+//	    1-1.quiz.yaml        quiz of lesson 1-1
+//	    _examples/1-1-1.go   code examples, referenced from the front matter
+//	    _examples/1-1-1.out  golden output of the example, recorded by the
+//	                         validate command and shown with the example
+//	    _exercises/1-1.go    exercise starter code
+//	_assets/...              any other files, such as images, which are
+//	                         kept and exported unchanged
+//
+// Go files live under "_" directories so the go tool does not treat them as
+// packages of this module.
+const (
+	courseFile      = "course.yaml"
+	chapterFile     = "chapter.yaml"
+	lessonExt       = ".md"
+	quizExt         = ".quiz.yaml"
+	examplesDir     = "_examples"
+	exercisesDir    = "_exercises"
+	frontMatterLine = "---"
+	goldenExt       = ".out"
+)
+
+// coursePattern restricts course IDs to what reads well in a URL path.
+var coursePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type courseDoc struct {
+	ID          string `yaml:"id"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Order       int    `yaml:"order,omitempty"`
+}
+
+type chapterDoc struct {
+	ID          int        `yaml:"id"`
+	Title       string     `yaml:"title"`
+	Description string     `yaml:"description"`
+	Lessons     []string   `yaml:"lessons"`
+	Gating      string     `yaml:"gating,omitempty"`
+	Policy      *policyDoc `yaml:"policy,omitempty"`
+}
+
+type policyDoc struct {
+	PassPercent     int   `yaml:"passPercent,omitempty"`
+	MaxAttempts     int   `yaml:"maxAttempts,omitempty"`
+	NegativeMarking *bool `yaml:"negativeMarking,omitempty"`
+}
+
+type lessonDoc struct {
+	ID         string         `yaml:"id"`
+	Title      string         `yaml:"title"`
+	Requires   []string       `yaml:"requires,omitempty"`
+	Completion *completionDoc `yaml:"completion,omitempty"`
+	Examples   []exampleDoc   `yaml:"examples,omitempty"`
+	Exercise   *exerciseDoc   `yaml:"exercise,omitempty"`
+	Notes      []string       `yaml:"notes,omitempty"`
+	Changelog  []changeDoc    `yaml:"changelog,omitempty"`
+}
+
+type changeDoc struct {
+	Date string `yaml:"date"`
+	Note string `yaml:"note"`
+}
+
+type completionDoc struct {
+	Read     bool `yaml:"read,omitempty"`
+	Quiz     bool `yaml:"quiz,omitempty"`
+	Exercise bool `yaml:"exercise,omitempty"`
+	Examples bool `yaml:"examples,omitempty"`
+}
+
+type exampleDoc struct {
+	Title            string `yaml:"title"`
+	File             string `yaml:"file"`
+	Check            string `yaml:"check,omitempty"`
+	Nondeterministic bool   `yaml:"nondeterministic,omitempty"`
+}
+
+type exerciseDoc struct {
+	Title          string `yaml:"title"`
+	Description    string `yaml:"description"`
+	Starter        string `yaml:"starter"`
+	ExpectedOutput string `yaml:"expectedOutput,omitempty"`
+}
+
+type quizDoc struct {
+	Draw      int           `yaml:"draw,omitempty"`
+	Policy    *policyDoc    `yaml:"policy,omitempty"`
+	Questions []questionDoc `yaml:"questions"`
+}
+
+type questionDoc struct {
+	ID          string   `yaml:"id"`
+	Type        string   `yaml:"type,omitempty"`
+	Text        string   `yaml:"text"`
+	Options     []string `yaml:"options,omitempty"`
+	Answer      *int     `yaml:"answer,omitempty"`
+	Answers     []int    `yaml:"answers,omitempty"`
+	Code        string   `yaml:"code,omitempty"`
+	Accepted    []string `yaml:"accepted,omitempty"`
+	Lines       []string `yaml:"lines,omitempty"`
+	Pattern     string   `yaml:"pattern,omitempty"`
+	Explanation string   `yaml:"explanation"`
+	Weight      int      `yaml:"weight,omitempty"`
+}
+
+// ContentError is a problem found in a content file. Line is 1-based and
+// zero when the problem is not tied to a line.
+type ContentError struct {
+	Path string
+	Line int
+	Msg  string
+}
+
+func (e *ContentError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+}
+
+// LoadStore reads one course from fsys, the course directory, in the
+// layout described above. It checks every file rather than stopping at the
+// first problem, and returns all of them joined, each as a *ContentError.
+func LoadStore(fsys fs.FS) (*Store, error) {
+	read := recordingFS{FS: fsys, opened: make(map[string]bool)}
+	l := &loader{fsys: read, store: newStore()}
+
+	l.loadCourse()
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read content: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), "_") && !strings.HasPrefix(e.Name(), ".") {
+			l.loadChapter(e.Name())
+		}
+	}
+	l.findTranslations(".", 0)
+	if len(l.store.Chapters) == 0 && len(l.errs) == 0 {
+		l.errorf(".", 0, "no chapter directories found")
+	}
+	l.loadGlossary()
+	l.checkPrerequisites()
+	if len(l.errs) > 0 {
+		return nil, errors.Join(l.errs...)
+	}
+	// Versions are taken before translations are applied, so translating
+	// a lesson does not count as changing it.
+	l.store.stampVersions()
+	loadExams(l.store)
+	l.loadTranslations()
+	if len(l.errs) > 0 {
+		return nil, errors.Join(l.errs...)
+	}
+	for _, s := range l.store.translations {
+		s.renderGlossary()
+		s.renderLessons()
+		s.buildIndex()
+	}
+	files, err := ReadFiles(fsys)
+	if err != nil {
+		return nil, err
+	}
+	for name, data := range files {
+		if !read.opened[name] {
+			if l.store.assets == nil {
+				l.store.assets = make(map[string][]byte)
+			}
+			l.store.assets[name] = data
+		}
+	}
+	return l.store, nil
+}
+
+// recordingFS remembers the names opened through it, which tells the files
+// the loader read from the assets it did not.
+type recordingFS struct {
+	fs.FS
+	opened map[string]bool
+}
+
+func (r recordingFS) Open(name string) (fs.File, error) {
+	r.opened[name] = true
+	return r.FS.Open(name)
+}
+
+type loader struct {
+	fsys  fs.FS
+	store *Store
+	errs  []error
+	// questionFiles remembers where each question ID was defined.
+	questionFiles map[string]string
+	// translationFiles lists the translations found, applied once the
+	// Japanese content has loaded.
+	translationFiles []translationFile
+	// requires lists the prerequisite edges, checked once every lesson
+	// is loaded.
+	requires []requirement
+}
+
+func (l *loader) errorf(file string, line int, format string, args ...any) {
+	l.errs = append(l.errs, &ContentError{Path: file, Line: line, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (l *loader) loadCourse() {
+	raw, err := fs.ReadFile(l.fsys, courseFile)
+	if err != nil {
+		l.errorf(courseFile, 0, "missing course file")
+		return
+	}
+	var doc courseDoc
+	root, ok := l.decode(courseFile, raw, 0, &doc)
+	if !ok {
+		return
+	}
+	if !coursePattern.MatchString(doc.ID) {
+		l.errorf(courseFile, lineOf(root, "id"), "id %q must be lowercase letters, digits and dashes", doc.ID)
+	}
+	if doc.Title == "" {
+		l.errorf(courseFile, lineOf(root, "title"), "title is required")
+	}
+	l.store.Course = models.Course{ID: doc.ID, Title: doc.Title, Description: doc.Description, Order: doc.Order}
+}
+
+func (l *loader) loadChapter(dir string) {
+	file := path.Join(dir, chapterFile)
+	raw, err := fs.ReadFile(l.fsys, file)
+	if err != nil {
+		l.errorf(file, 0, "missing chapter file")
+		return
+	}
+	var doc chapterDoc
+	root, ok := l.decode(file, raw, 0, &doc)
+	if !ok {
+		return
+	}
+
+	switch {
+	case doc.ID <= 0:
+		l.errorf(file, lineOf(root, "id"), "id must be a positive number")
+		return
+	case slices.ContainsFunc(l.store.Chapters, func(ch models.Chapter) bool { return ch.ID == doc.ID }):
+		l.errorf(file, lineOf(root, "id"), "duplicate chapter id %d", doc.ID)
+		return
+	case doc.Title == "":
+		l.errorf(file, lineOf(root, "title"), "title is required")
+	case len(doc.Lessons) == 0:
+		l.errorf(file, lineOf(root, "lessons"), "at least one lesson is required")
+	}
+	gating := cmp.Or(doc.Gating, models.GatingAdvisory)
+	if gating != models.GatingStrict && gating != models.GatingAdvisory {
+		l.errorf(file, lineOf(root, "gating"), "unknown gating %q (want %s or %s)",
+			gating, models.GatingStrict, models.GatingAdvisory)
+	}
+
+	ch := models.Chapter{
+		ID:          doc.ID,
+		Title:       doc.Title,
+		Description: doc.Description,
+		Policy:      doc.Policy.model(),
+		Gating:      gating,
+	}
+	for i, id := range doc.Lessons {
+		if _, dup := l.store.lessons[id]; dup {
+			l.errorf(file, lineOf(root, "lessons", i), "duplicate lesson id %q", id)
+			continue
+		}
+		lesson, ok := l.loadLesson(dir, id, doc.ID)
+		if !ok {
+			continue
+		}
+		ch.Lessons = append(ch.Lessons, models.LessonSummary{ID: lesson.ID, Title: lesson.Title, Requires: lesson.Requires})
+		l.store.addLesson(lesson)
+	}
+	l.store.addChapter(ch)
+	// Quizzes are checked even when their lesson has problems, so one run
+	// reports everything.
+	for _, id := range slices.Compact(slices.Clone(doc.Lessons)) {
+		l.loadQuiz(dir, id)
+	}
+	l.findTranslations(dir, doc.ID)
+}
+
+func (l *loader) loadLesson(dir, id string, chapterID int) (models.Lesson, bool) {
+	file := path.Join(dir, id+lessonExt)
+	raw, err := fs.ReadFile(l.fsys, file)
+	if err != nil {
+		l.errorf(file, 0, "missing lesson file for %q listed in %s", id, chapterFile)
+		return models.Lesson{}, false
+	}
+
+	front, body, bodyLine, ok := splitFrontMatter(string(raw))
+	if !ok {
+		l.errorf(file, 1, "lesson must start with a %q front matter block", frontMatterLine)
+		return models.Lesson{}, false
+	}
+	var doc lessonDoc
+	// The front matter starts on the line after the opening "---".
+	root, ok := l.decode(file, []byte(front), 1, &doc)
+	if !ok {
+		return models.Lesson{}, false
+	}
+	line := func(p ...any) int { return lineOf(root, p...) + 1 }
+
+	valid := true
+	if doc.ID != id {
+		l.errorf(file, line("id"), "id %q does not match file name %q", doc.ID, id)
+		valid = false
+	}
+	if doc.Title == "" {
+		l.errorf(file, line("title"), "title is required")
+		valid = false
+	}
+	if strings.TrimSpace(body) == "" {
+		l.errorf(file, bodyLine, "lesson body is empty")
+		valid = false
+	}
+
+	lesson := models.Lesson{
+		ID:           id,
+		ChapterID:    chapterID,
+		Title:        doc.Title,
+		Content:      body,
+		CodeExamples: []models.CodeExample{},
+		Notes:        doc.Notes,
+		Requires:     doc.Requires,
+		Completion:   doc.Completion.model(),
+	}
+	for i, req := range doc.Requires {
+		l.requires = append(l.requires, requirement{lessonID: id, requires: req, file: file, line: line("requires", i)})
+	}
+	for i, c := range doc.Changelog {
+		if _, err := time.Parse(time.DateOnly, c.Date); err != nil {
+			l.errorf(file, line("changelog", i, "date"), "changelog %d: date %q is not YYYY-MM-DD", i+1, c.Date)
+		}
+		if strings.TrimSpace(c.Note) == "" {
+			l.errorf(file, line("changelog", i), "changelog %d: note is required", i+1)
+		}
+		lesson.Changelog = append(lesson.Changelog, models.ChangelogEntry{Date: c.Date, Note: c.Note})
+	}
+	// Dates are ISO, so they sort as strings.
+	slices.SortStableFunc(lesson.Changelog, func(a, b models.ChangelogEntry) int {
+		return strings.Compare(b.Date, a.Date)
+	})
+	for i, ex := range doc.Examples {
+		code, err := l.readCode(dir, ex.File)
+		if err != nil {
+			l.errorf(file, line("examples", i, "file"), "example %d: %v", i+1, err)
+			valid = false
+			continue
+		}
+		if ex.Title == "" {
+			l.errorf(file, line("examples", i), "example %d: title is required", i+1)
+			valid = false
+		}
+		check := ex.Check
+		if check == "" {
+			check = defaultCheck(ex.File)
+		}
+		if check != models.CheckRun && check != models.CheckCompile && check != models.CheckNone {
+			l.errorf(file, line("examples", i, "check"), "example %d: unknown check %q (want %s, %s or %s)",
+				i+1, check, models.CheckRun, models.CheckCompile, models.CheckNone)
+			valid = false
+		}
+		if ex.Nondeterministic && check != models.CheckRun {
+			l.errorf(file, line("examples", i, "nondeterministic"), "example %d: only examples that are run can be nondeterministic", i+1)
+			valid = false
+		}
+		example := models.CodeExample{
+			Title:            ex.Title,
+			Code:             code,
+			File:             path.Join(dir, ex.File),
+			Check:            check,
+			Nondeterministic: ex.Nondeterministic,
+		}
+		if check == models.CheckRun {
+			// The golden output is missing until it is first recorded,
+			// which the validate command reports.
+			if out, err := fs.ReadFile(l.fsys, GoldenFile(example.File)); err == nil {
+				example.Output = string(out)
+			}
+		}
+		lesson.CodeExamples = append(lesson.CodeExamples, example)
+	}
+	if doc.Exercise != nil {
+		code, err := l.readCode(dir, doc.Exercise.Starter)
+		if err != nil {
+			l.errorf(file, line("exercise", "starter"), "exercise: %v", err)
+			valid = false
+		}
+		lesson.Exercise = &models.Exercise{
+			Title:          doc.Exercise.Title,
+			Description:    doc.Exercise.Description,
+			StarterCode:    code,
+			StarterFile:    path.Join(dir, doc.Exercise.Starter),
+			ExpectedOutput: doc.Exercise.ExpectedOutput,
+		}
+	}
+	return lesson, valid
+}
+
+// GoldenFile names the file holding the golden output of the example in
+// file, e.g. _examples/4-2-1.out for _examples/4-2-1.go.
+func GoldenFile(file string) string {
+	return strings.TrimSuffix(file, path.Ext(file)) + goldenExt
+}
+
+// readCode reads a code file referenced from a lesson, relative to its
+// chapter directory. The file's final newline is not part of the code.
+// Code is usually a .go file; snippets that are not Go, such as go.mod
+// contents, are .txt files.
+func (l *loader) readCode(dir, name string) (string, error) {
+	if name == "" {
+		return "", errors.New("file is required")
+	}
+	if !fs.ValidPath(path.Join(dir, name)) || strings.HasPrefix(name, "../") {
+		return "", fmt.Errorf("invalid file %q: must be inside the chapter directory", name)
+	}
+	raw, err := fs.ReadFile(l.fsys, path.Join(dir, name))
+	if err != nil {
+		return "", fmt.Errorf("cannot read %s", path.Join(dir, name))
+	}
+	return strings.TrimSuffix(string(raw), "\n"), nil
+}
+
+// defaultCheck is the check of an example that sets none: Go files are run
+// and other snippets are skipped.
+func defaultCheck(file string) string {
+	if path.Ext(file) == ".go" {
+		return models.CheckRun
+	}
+	return models.CheckNone
+}
+
+func (l *loader) loadQuiz(dir, lessonID string) {
+	file := path.Join(dir, lessonID+quizExt)
+	raw, err := fs.ReadFile(l.fsys, file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return // lessons without a quiz are allowed
+	}
+	if err != nil {
+		l.errorf(file, 0, "%v", err)
+		return
+	}
+	var doc quizDoc
+	root, ok := l.decode(file, raw, 0, &doc)
+	if !ok {
+		return
+	}
+	if l.questionFiles == nil {
+		l.questionFiles = make(map[string]string)
+	}
+
+	quiz := models.Quiz{LessonID: lessonID, Draw: doc.Draw, Policy: doc.Policy.model()}
+	if len(doc.Questions) == 0 {
+		l.errorf(file, lineOf(root, "questions"), "at least one question is required")
+		return
+	}
+	if doc.Draw < 0 || doc.Draw > len(doc.Questions) {
+		l.errorf(file, lineOf(root, "draw"), "draw %d is outside 0..%d", doc.Draw, len(doc.Questions))
+	}
+	for i, qd := range doc.Questions {
+		line := func(p ...any) int { return lineOf(root, append([]any{"questions", i}, p...)...) }
+		if prev, dup := l.questionFiles[qd.ID]; dup {
+			l.errorf(file, line("id"), "duplicate question id %q (also in %s)", qd.ID, prev)
+			continue
+		}
+		q, field, msg := qd.model()
+		if msg != "" {
+			l.errorf(file, line(field), "question %s: %s", cmp.Or(qd.ID, strconv.Itoa(i+1)), msg)
+			continue
+		}
+		l.questionFiles[q.ID] = file
+		quiz.Questions = append(quiz.Questions, q)
+	}
+	l.store.addQuiz(quiz)
+}
+
+// decode parses YAML into v, rejecting unknown fields. offset is added to
+// line numbers so they refer to lines of the whole file. It also returns
+// the document node for looking up the lines of later problems.
+func (l *loader) decode(file string, raw []byte, offset int, v any) (*yaml.Node, bool) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(raw, &root); err != nil {
+		l.yamlError(file, offset, err)
+		return nil, false
+	}
+	dec := yaml.NewDecoder(strings.NewReader(string(raw)))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil {
+		l.yamlError(file, offset, err)
+		return nil, false
+	}
+	return &root, true
+}
+
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlType matches the Go type the yaml package names in unknown field
+// errors, which means nothing to content authors.
+var yamlType = regexp.MustCompile(` in type data\.\w+$`)
+
+// yamlError records a YAML error, splitting out the line numbers that the
+// yaml package embeds in its messages.
+func (l *loader) yamlError(file string, offset int, err error) {
+	msgs := []string{err.Error()}
+	var te *yaml.TypeError
+	if errors.As(err, &te) {
+		msgs = te.Errors
+	}
+	for _, msg := range msgs {
+		msg = yamlType.ReplaceAllString(msg, "")
+		if m := yamlLine.FindStringSubmatch(msg); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			l.errorf(file, n+offset, "%s", m[2])
+		} else {
+			l.errorf(file, 0, "%s", strings.TrimPrefix(msg, "yaml: "))
+		}
+	}
+}
+
+// splitFrontMatter splits a lesson file into its front matter and body.
+// bodyLine is the line the body starts on.
+func splitFrontMatter(src string) (front, body string, bodyLine int, ok bool) {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	rest, found := strings.CutPrefix(src, frontMatterLine+"\n")
+	if !found {
+		return "", "", 0, false
+	}
+	end := strings.Index("\n"+rest, "\n"+frontMatterLine+"\n")
+	if end < 0 {
+		return "", "", 0, false
+	}
+	front = rest[:end]
+	body = rest[end+len(frontMatterLine)+1:]
+	bodyLine = strings.Count(src[:len(src)-len(body)], "\n") + 1
+	// One blank line separates the front matter from the body.
+	if b, found := strings.CutPrefix(body, "\n"); found {
+		body = b
+		bodyLine++
+	}
+	return front, strings.TrimSuffix(body, "\n"), bodyLine, true
+}
+
+// lineOf returns the line of the node at p (mapping keys and sequence
+// indexes) under a document node, or of the deepest ancestor that exists.
+func lineOf(root *yaml.Node, p ...any) int {
+	if root == nil {
+		return 0
+	}
+	n := root
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	for _, step := range p {
+		var next *yaml.Node
+		switch step := step.(type) {
+		case string:
+			if n.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(n.Content); i += 2 {
+					if n.Content[i].Value == step {
+						next = n.Content[i+1]
+					}
+				}
+			}
+		case int:
+			if n.Kind == yaml.SequenceNode && step < len(n.Content) {
+				next = n.Content[step]
+			}
+		}
+		if next == nil {
+			break
+		}
+		n = next
+	}
+	return n.Line
+}
+
+func (p *policyDoc) model() *models.ScoringPolicy {
+	if p == nil {
+		return nil
+	}
+	return &models.ScoringPolicy{
+		PassPercent:     p.PassPercent,
+		MaxAttempts:     p.MaxAttempts,
+		NegativeMarking: p.NegativeMarking,
+	}
+}
+
+func (c *completionDoc) model() *models.CompletionRule {
+	if c == nil {
+		return nil
+	}
+	return &models.CompletionRule{Read: c.Read, Quiz: c.Quiz, Exercise: c.Exercise, Examples: c.Examples}
+}
+
+// model converts and checks a question. On failure it returns the field
+// at fault and a message.
+func (d questionDoc) model() (models.Question, string, string) {
+	q := models.Question{
+		ID:          d.ID,
+		Type:        d.Type,
+		Text:        d.Text,
+		Options:     d.Options,
+		Answers:     d.Answers,
+		Code:        d.Code,
+		Accepted:    d.Accepted,
+		Lines:       d.Lines,
+		Pattern:     d.Pattern,
+		Explanation: d.Explanation,
+		Weight:      d.Weight,
+	}
+	if d.Answer != nil {
+		q.Answer = *d.Answer
+	} else if q.Kind() == models.QuestionSingle && d.ID != "" && d.Text != "" {
+		return q, "", "answer is required"
+	}
+	field, msg := CheckQuestion(q)
+	return q, field, msg
+}
+
+// CheckQuestion reports the first problem with q: a missing field, an
+// answer that is not an option, an unknown type and so on. It returns the
+// name of the field at fault, which is empty for a missing field, and a
+// message, which is empty if q is fine.
+func CheckQuestion(q models.Question) (field, msg string) {
+	switch {
+	case q.ID == "":
+		return "", "id is required"
+	case q.Text == "":
+		return "text", "text is required"
+	case q.Weight < 0:
+		return "weight", "weight must not be negative"
+	}
+	inOptions := func(i int) bool { return i >= 0 && i < len(q.Options) }
+
+	switch q.Kind() {
+	case models.QuestionSingle:
+		if len(q.Options) < 2 {
+			return "options", "a single-choice question needs at least two options"
+		}
+		if !inOptions(q.Answer) {
+			return "answer", fmt.Sprintf("answer %d is not an option index (0..%d)", q.Answer, len(q.Options)-1)
+		}
+	case models.QuestionMulti:
+		if len(q.Options) < 2 {
+			return "options", "a multi-select question needs at least two options"
+		}
+		if len(q.Answers) == 0 {
+			return "", "answers is required"
+		}
+		for _, a := range q.Answers {
+			if !inOptions(a) {
+				return "answers", fmt.Sprintf("answer %d is not an option index (0..%d)", a, len(q.Options)-1)
+			}
+		}
+	case models.QuestionOutput:
+		if q.Code == "" {
+			return "", "an output question needs code"
+		}
+	case models.QuestionFill:
+		if len(q.Accepted) == 0 {
+			return "", "a fill question needs accepted answers"
+		}
+	case models.QuestionOrder:
+		if len(q.Lines) < 2 {
+			return "lines", "an ordering question needs at least two lines"
+		}
+	case models.QuestionShort:
+		if q.Pattern == "" {
+			return "", "a short-answer question needs a pattern"
+		}
+		if _, err := regexp.Compile(q.Pattern); err != nil {
+			return "pattern", fmt.Sprintf("invalid pattern: %v", err)
+		}
+	default:
+		return "type", fmt.Sprintf("unknown type %q", q.Type)
+	}
+	return "", ""
+}
@@ -0,0 +1,189 @@
+package data
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+// Content states kept in the database. Instructors edit the draft, and
+// publishing copies it to the published content learners see.
+const (
+	StateDraft     = "draft"
+	StatePublished = "published"
+)
+
+// ContentStatus tells when the draft and the published content were last
+// saved; a zero time means there is none.
+type ContentStatus struct {
+	DraftAt     time.Time `json:"draftAt,omitzero"`
+	PublishedAt time.Time `json:"publishedAt,omitzero"`
+	// PreviewToken opens the draft in the learner app; it is kept for the
+	// life of the draft so preview links stay valid across edits.
+	PreviewToken string `json:"previewToken,omitempty"`
+}
+
+// Unpublished reports whether the draft has changes that are not published.
+func (s ContentStatus) Unpublished() bool {
+	return !s.DraftAt.IsZero() && s.DraftAt.After(s.PublishedAt)
+}
+
+// Source returns the content of a state as a ContentSource.
+func (db *DB) Source(state string) ContentSource {
+	return dbSource{db: db, state: state}
+}
+
+type dbSource struct {
+	db    *DB
+	state string
+}
+
+// Files reads every file of the state into memory; content is small, and
+// loading reads all of it anyway.
+func (s dbSource) Files() (fs.FS, error) {
+	rows, err := s.db.conn.Query("SELECT path, data FROM content_files WHERE state = ?", s.state)
+	if err != nil {
+		return nil, fmt.Errorf("read %s content: %w", s.state, err)
+	}
+	defer rows.Close()
+
+	files := make(map[string][]byte)
+	for rows.Next() {
+		var name string
+		var data []byte
+		if err := rows.Scan(&name, &data); err != nil {
+			return nil, fmt.Errorf("read %s content: %w", s.state, err)
+		}
+		files[name] = data
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read %s content: %w", s.state, err)
+	}
+	if len(files) == 0 {
+		return nil, ErrNotFound
+	}
+	return mapFS(files), nil
+}
+
+// SaveDraft replaces the draft with files, keyed by slash-separated path.
+func (db *DB) SaveDraft(files map[string][]byte) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("save draft: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM content_files WHERE state = ?", StateDraft); err != nil {
+		return fmt.Errorf("save draft: %w", err)
+	}
+	for name, data := range files {
+		if _, err := tx.Exec(
+			"INSERT INTO content_files (state, path, data) VALUES (?, ?, ?)",
+			StateDraft, name, data,
+		); err != nil {
+			return fmt.Errorf("save draft: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`
+INSERT INTO content_states (state, updated_at, preview_token) VALUES (?, ?, ?)
+ON CONFLICT (state) DO UPDATE SET updated_at = excluded.updated_at`,
+		StateDraft, sqlTime(time.Now()), rand.Text(),
+	); err != nil {
+		return fmt.Errorf("save draft: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Publish copies the draft to the published content. It returns
+// ErrNotFound if there is no draft.
+func (db *DB) Publish() error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+	defer tx.Rollback()
+
+	var n int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM content_files WHERE state = ?", StateDraft).Scan(&n); err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.Exec("DELETE FROM content_files WHERE state = ?", StatePublished); err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+	if _, err := tx.Exec(`
+INSERT INTO content_files (state, path, data)
+SELECT ?, path, data FROM content_files WHERE state = ?`,
+		StatePublished, StateDraft,
+	); err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+	if _, err := tx.Exec(`
+INSERT INTO content_states (state, updated_at) VALUES (?, ?)
+ON CONFLICT (state) DO UPDATE SET updated_at = excluded.updated_at`,
+		StatePublished, sqlTime(time.Now()),
+	); err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+	return tx.Commit()
+}
+
+// DiscardDraft deletes the draft, and with it its preview token.
+func (db *DB) DiscardDraft() error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("discard draft: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"content_files", "content_states"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE state = ?", StateDraft); err != nil {
+			return fmt.Errorf("discard draft: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetContentStatus returns when the draft and published content were saved.
+func (db *DB) GetContentStatus() (ContentStatus, error) {
+	var status ContentStatus
+	rows, err := db.conn.Query("SELECT state, updated_at, preview_token FROM content_states")
+	if err != nil {
+		return status, fmt.Errorf("get content status: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var state, token string
+		var at time.Time
+		if err := rows.Scan(&state, &at, &token); err != nil {
+			return status, fmt.Errorf("get content status: %w", err)
+		}
+		switch state {
+		case StateDraft:
+			status.DraftAt, status.PreviewToken = at, token
+		case StatePublished:
+			status.PublishedAt = at
+		}
+	}
+	return status, rows.Err()
+}
+
+// PreviewToken returns the preview token of the draft, or ErrNotFound if
+// there is no draft.
+func (db *DB) PreviewToken() (string, error) {
+	var token string
+	err := db.conn.QueryRow("SELECT preview_token FROM content_states WHERE state = ?", StateDraft).Scan(&token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("get preview token: %w", err)
+	}
+	return token, nil
+}
@@ -1,22 +1,44 @@
 package data
 
-import "go-learning-app/models"
+import (
+	"go-learning-app/models"
+	"go-learning-app/search"
+)
 
-// Store holds all chapters, lessons, and quizzes with indexed lookup.
+// Store holds one course's chapters, lessons, and quizzes with indexed
+// lookup.
 type Store struct {
+	// Locale is the language of the store's text.
+	Locale   string
+	Course   models.Course
 	Chapters []models.Chapter
 	lessons  map[string]models.Lesson
 	quizzes  map[string]models.Quiz
+	exams    []models.Exam
+	glossary []models.GlossaryTerm
+	// questionLessons maps each question ID to its lesson ID.
+	questionLessons map[string]string
+	// translations maps every locale to its store, shared by all of them.
+	translations map[string]*Store
+	// index is the full-text index of the store's text.
+	index *search.Index
+	// coverage, translationSources and assets are set on the Japanese
+	// store only.
+	coverage           []Coverage
+	translationSources []translationSource
+	// assets are the files of the course directory that loading did not
+	// read, such as images or tests, keyed by path. They are exported
+	// unchanged.
+	assets map[string][]byte
 }
 
-// NewStore creates and initializes the data store with all content.
-func NewStore() *Store {
-	s := &Store{
-		lessons: make(map[string]models.Lesson),
-		quizzes: make(map[string]models.Quiz),
+func newStore() *Store {
+	return &Store{
+		Locale:          DefaultLocale,
+		lessons:         make(map[string]models.Lesson),
+		quizzes:         make(map[string]models.Quiz),
+		questionLessons: make(map[string]string),
 	}
-	s.loadAll()
-	return s
 }
 
 // GetChapters returns all chapters with lesson summaries.
@@ -30,33 +52,86 @@ func (s *Store) GetLesson(id string) (models.Lesson, bool) {
 	return l, ok
 }
 
-// GetQuiz returns a quiz by lesson ID.
+// GetQuiz returns a quiz by lesson ID. Its scoring policy is resolved from
+// its own, its chapter's and the default, so it is never nil.
 func (s *Store) GetQuiz(lessonID string) (models.Quiz, bool) {
+	q, ok := s.quizzes[lessonID]
+	if !ok {
+		return q, false
+	}
+	policy := models.DefaultScoringPolicy
+	if l, ok := s.lessons[q.LessonID]; ok {
+		for _, ch := range s.Chapters {
+			if ch.ID == l.ChapterID && ch.Policy != nil {
+				policy = ch.Policy.Inherit(policy)
+			}
+		}
+	}
+	if q.Policy != nil {
+		policy = q.Policy.Inherit(policy)
+	}
+	q.Policy = &policy
+	return q, true
+}
+
+// QuizSource returns a quiz as its content file has it: its policy is its
+// own, and nil when it inherits its chapter's.
+func (s *Store) QuizSource(lessonID string) (models.Quiz, bool) {
 	q, ok := s.quizzes[lessonID]
 	return q, ok
 }
 
+// CompletionRule returns the completion rule for a lesson.
+func (s *Store) CompletionRule(lessonID string) models.CompletionRule {
+	if l, ok := s.lessons[lessonID]; ok && l.Completion != nil {
+		return *l.Completion
+	}
+	return models.DefaultCompletionRule
+}
+
+// ResolveProgress turns raw progress rows into a breakdown for every lesson
+// in course order, applying each lesson's completion rule and flagging
+// completed lessons that changed since.
+func (s *Store) ResolveProgress(rows map[string]models.LessonProgress) []models.LessonProgress {
+	var out []models.LessonProgress
+	for _, ch := range s.Chapters {
+		for _, summary := range ch.Lessons {
+			p := rows[summary.ID]
+			p.LessonID = summary.ID
+			p.Rule = s.CompletionRule(summary.ID)
+			if l, ok := s.lessons[summary.ID]; ok {
+				p.ExamplesTotal = len(l.CodeExamples)
+			}
+			p.Completed = p.Rule.Satisfied(p)
+			lessonChanged, quizChanged := s.changed(p)
+			p.Updated = p.Completed && (lessonChanged || quizChanged)
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
 func (s *Store) addChapter(ch models.Chapter) {
 	s.Chapters = append(s.Chapters, ch)
 }
 
 func (s *Store) addLesson(l models.Lesson) {
+	if l.Completion == nil {
+		rule := models.DefaultCompletionRule
+		l.Completion = &rule
+	}
 	s.lessons[l.ID] = l
 }
 
-func (s *Store) addQuiz(q models.Quiz) {
-	s.quizzes[q.LessonID] = q
+// QuestionLesson returns the lesson ID a question belongs to.
+func (s *Store) QuestionLesson(questionID string) (string, bool) {
+	id, ok := s.questionLessons[questionID]
+	return id, ok
 }
 
-func (s *Store) loadAll() {
-	loadChapter01(s)
-	loadChapter02(s)
-	loadChapter03(s)
-	loadChapter04(s)
-	loadChapter05(s)
-	loadChapter06(s)
-	loadChapter07(s)
-	loadChapter08(s)
-	loadChapter09(s)
-	loadChapter10(s)
+func (s *Store) addQuiz(q models.Quiz) {
+	s.quizzes[q.LessonID] = q
+	for _, question := range q.Questions {
+		s.questionLessons[question.ID] = q.LessonID
+	}
 }
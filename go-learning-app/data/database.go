@@ -2,9 +2,12 @@ package data
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 
+	"go-learning-app/models"
+
 	_ "modernc.org/sqlite"
 )
 
@@ -13,6 +16,18 @@ type DB struct {
 	conn *sql.DB
 }
 
+// CourseDB reads and writes the records of one course: progress, quiz
+// attempts, review items and exam sessions.
+type CourseDB struct {
+	*DB
+	course string
+}
+
+// Course returns the records of a course.
+func (db *DB) Course(id string) *CourseDB {
+	return &CourseDB{DB: db, course: id}
+}
+
 // NewDB opens the SQLite database at path and creates tables if needed.
 func NewDB(path string) (*DB, error) {
 	conn, err := sql.Open("sqlite", path)
@@ -31,6 +46,11 @@ func NewDB(path string) (*DB, error) {
 		return nil, err
 	}
 
+	if err := migrate(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
 	return &DB{conn: conn}, nil
 }
 
@@ -39,18 +59,274 @@ func createTables(conn *sql.DB) error {
 CREATE TABLE IF NOT EXISTS users (
     username   TEXT PRIMARY KEY,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-);
+);`
+
+	if _, err := conn.Exec(schema); err != nil {
+		return fmt.Errorf("create tables: %w", err)
+	}
+	return nil
+}
 
+// migrations are applied in order on top of the base schema. The number of
+// applied migrations is tracked in PRAGMA user_version, so new steps must
+// only ever be appended.
+var migrations = []string{
+	// 1: split lesson completion into components. Legacy progress rows were
+	// written after a passed quiz, so they become read + quiz passed. New
+	// databases have no legacy table, so an empty one stands in for it.
+	`
 CREATE TABLE IF NOT EXISTS progress (
     username     TEXT NOT NULL,
     lesson_id    TEXT NOT NULL,
     completed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (username, lesson_id)
+);
+
+CREATE TABLE lesson_progress (
+    username           TEXT NOT NULL,
+    lesson_id          TEXT NOT NULL,
+    read_at            DATETIME,
+    quiz_score         INTEGER,
+    quiz_passed_at     DATETIME,
+    exercise_passed_at DATETIME,
     PRIMARY KEY (username, lesson_id),
     FOREIGN KEY (username) REFERENCES users(username)
-);`
+);
 
-	if _, err := conn.Exec(schema); err != nil {
-		return fmt.Errorf("create tables: %w", err)
+CREATE TABLE example_runs (
+    username      TEXT NOT NULL,
+    lesson_id     TEXT NOT NULL,
+    example_index INTEGER NOT NULL,
+    run_at        DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (username, lesson_id, example_index),
+    FOREIGN KEY (username) REFERENCES users(username)
+);
+
+INSERT INTO lesson_progress (username, lesson_id, read_at, quiz_passed_at)
+SELECT username, lesson_id, completed_at, completed_at FROM progress;
+
+DROP TABLE progress;`,
+
+	// 2: keep every graded quiz attempt with its per-question answers.
+	`
+CREATE TABLE quiz_attempts (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    username     TEXT NOT NULL,
+    lesson_id    TEXT NOT NULL,
+    correct      INTEGER NOT NULL,
+    total        INTEGER NOT NULL,
+    score        INTEGER NOT NULL,
+    passed       BOOLEAN NOT NULL,
+    duration_ms  INTEGER NOT NULL,
+    submitted_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (username) REFERENCES users(username)
+);
+
+CREATE INDEX quiz_attempts_user_lesson ON quiz_attempts (username, lesson_id);
+
+CREATE TABLE quiz_answers (
+    attempt_id  INTEGER NOT NULL,
+    question_id TEXT NOT NULL,
+    answer      TEXT NOT NULL,
+    correct     BOOLEAN NOT NULL,
+    PRIMARY KEY (attempt_id, question_id),
+    FOREIGN KEY (attempt_id) REFERENCES quiz_attempts(id)
+);
+
+CREATE INDEX quiz_answers_question ON quiz_answers (question_id);`,
+
+	// 3: an attempt starts as a session that fixes the drawn questions and
+	// the shuffle seed, and is graded against them on submit.
+	`
+CREATE TABLE quiz_sessions (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    username     TEXT NOT NULL,
+    lesson_id    TEXT NOT NULL,
+    seed         INTEGER NOT NULL,
+    question_ids TEXT NOT NULL,
+    started_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
+    submitted_at DATETIME,
+    FOREIGN KEY (username) REFERENCES users(username)
+);
+
+ALTER TABLE quiz_attempts ADD COLUMN session_id INTEGER REFERENCES quiz_sessions(id);`,
+
+	// 4: spaced-repetition queue of missed questions and note flashcards.
+	`
+CREATE TABLE review_items (
+    username      TEXT NOT NULL,
+    item_id       TEXT NOT NULL,
+    kind          TEXT NOT NULL,
+    lesson_id     TEXT NOT NULL,
+    ease          REAL NOT NULL,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    repetitions   INTEGER NOT NULL DEFAULT 0,
+    due_at        DATETIME NOT NULL,
+    reviewed_at   DATETIME,
+    PRIMARY KEY (username, item_id),
+    FOREIGN KEY (username) REFERENCES users(username)
+);
+
+CREATE INDEX review_items_due ON review_items (username, due_at);`,
+
+	// 5: timed exam sessions. Answers are saved as the learner goes and
+	// the deadline is fixed when the session starts.
+	`
+CREATE TABLE exam_sessions (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    username     TEXT NOT NULL,
+    exam_id      TEXT NOT NULL,
+    seed         INTEGER NOT NULL,
+    question_ids TEXT NOT NULL,
+    answers      TEXT NOT NULL DEFAULT '{}',
+    started_at   DATETIME NOT NULL,
+    deadline     DATETIME NOT NULL,
+    submitted_at DATETIME,
+    score        INTEGER NOT NULL DEFAULT 0,
+    passed       BOOLEAN NOT NULL DEFAULT 0,
+    FOREIGN KEY (username) REFERENCES users(username)
+);
+
+CREATE INDEX exam_sessions_user ON exam_sessions (username, exam_id);`,
+
+	// 6: preferred content language; empty means negotiate per request.
+	`ALTER TABLE users ADD COLUMN locale TEXT NOT NULL DEFAULT '';`,
+
+	// 7: the content versions progress was recorded against. Progress rows
+	// from before keep NULL until StampLegacyProgress fills them in.
+	`
+ALTER TABLE lesson_progress ADD COLUMN lesson_version TEXT;
+ALTER TABLE lesson_progress ADD COLUMN lesson_version_at DATETIME;
+ALTER TABLE lesson_progress ADD COLUMN quiz_version TEXT;
+ALTER TABLE lesson_progress ADD COLUMN quiz_version_at DATETIME;
+ALTER TABLE quiz_sessions ADD COLUMN quiz_version TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz_attempts ADD COLUMN quiz_version TEXT NOT NULL DEFAULT '';`,
+
+	// 8: content edited through the admin API, in the content file layout,
+	// as a draft and as the published copy learners see.
+	`
+CREATE TABLE content_files (
+    state TEXT NOT NULL,
+    path  TEXT NOT NULL,
+    data  BLOB NOT NULL,
+    PRIMARY KEY (state, path)
+);
+
+CREATE TABLE content_states (
+    state         TEXT PRIMARY KEY,
+    updated_at    DATETIME NOT NULL,
+    preview_token TEXT NOT NULL DEFAULT ''
+);`,
+
+	// 9: records are kept per course, since lesson, question and exam IDs
+	// are only unique within one. Everything recorded so far belongs to
+	// the one course there was, go-intro, and so does edited content.
+	`
+CREATE TABLE lesson_progress_9 (
+    username           TEXT NOT NULL,
+    course_id          TEXT NOT NULL,
+    lesson_id          TEXT NOT NULL,
+    read_at            DATETIME,
+    quiz_score         INTEGER,
+    quiz_passed_at     DATETIME,
+    exercise_passed_at DATETIME,
+    lesson_version     TEXT,
+    lesson_version_at  DATETIME,
+    quiz_version       TEXT,
+    quiz_version_at    DATETIME,
+    PRIMARY KEY (username, course_id, lesson_id),
+    FOREIGN KEY (username) REFERENCES users(username)
+);
+INSERT INTO lesson_progress_9
+SELECT username, 'go-intro', lesson_id, read_at, quiz_score, quiz_passed_at, exercise_passed_at,
+       lesson_version, lesson_version_at, quiz_version, quiz_version_at
+FROM lesson_progress;
+DROP TABLE lesson_progress;
+ALTER TABLE lesson_progress_9 RENAME TO lesson_progress;
+
+CREATE TABLE example_runs_9 (
+    username      TEXT NOT NULL,
+    course_id     TEXT NOT NULL,
+    lesson_id     TEXT NOT NULL,
+    example_index INTEGER NOT NULL,
+    run_at        DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (username, course_id, lesson_id, example_index),
+    FOREIGN KEY (username) REFERENCES users(username)
+);
+INSERT INTO example_runs_9
+SELECT username, 'go-intro', lesson_id, example_index, run_at FROM example_runs;
+DROP TABLE example_runs;
+ALTER TABLE example_runs_9 RENAME TO example_runs;
+
+CREATE TABLE review_items_9 (
+    username      TEXT NOT NULL,
+    course_id     TEXT NOT NULL,
+    item_id       TEXT NOT NULL,
+    kind          TEXT NOT NULL,
+    lesson_id     TEXT NOT NULL,
+    ease          REAL NOT NULL,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    repetitions   INTEGER NOT NULL DEFAULT 0,
+    due_at        DATETIME NOT NULL,
+    reviewed_at   DATETIME,
+    PRIMARY KEY (username, course_id, item_id),
+    FOREIGN KEY (username) REFERENCES users(username)
+);
+INSERT INTO review_items_9
+SELECT username, 'go-intro', item_id, kind, lesson_id, ease, interval_days, repetitions, due_at, reviewed_at
+FROM review_items;
+DROP TABLE review_items;
+ALTER TABLE review_items_9 RENAME TO review_items;
+CREATE INDEX review_items_due ON review_items (username, course_id, due_at);
+
+ALTER TABLE quiz_sessions ADD COLUMN course_id TEXT NOT NULL DEFAULT '';
+UPDATE quiz_sessions SET course_id = 'go-intro';
+ALTER TABLE quiz_attempts ADD COLUMN course_id TEXT NOT NULL DEFAULT '';
+UPDATE quiz_attempts SET course_id = 'go-intro';
+DROP INDEX quiz_attempts_user_lesson;
+CREATE INDEX quiz_attempts_user_lesson ON quiz_attempts (username, course_id, lesson_id);
+ALTER TABLE exam_sessions ADD COLUMN course_id TEXT NOT NULL DEFAULT '';
+UPDATE exam_sessions SET course_id = 'go-intro';
+DROP INDEX exam_sessions_user;
+CREATE INDEX exam_sessions_user ON exam_sessions (username, course_id, exam_id);
+
+UPDATE content_files SET path = 'go-intro/' || path;
+INSERT INTO content_files (state, path, data)
+SELECT DISTINCT state, 'go-intro/course.yaml', CAST('id: go-intro
+title: Go入門
+description: Go言語の基本から、構造体とインターフェース、並行処理、エラー処理、テストまでを10章で体系的に学びます。
+order: 1
+' AS BLOB)
+FROM content_files;`,
+
+	// 10: the legacy progress table the base schema used to recreate on
+	// every start after migration 1 had dropped it.
+	`DROP TABLE IF EXISTS progress;`,
+}
+
+func migrate(conn *sql.DB) error {
+	var version int
+	if err := conn.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := conn.Begin()
+		if err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		// PRAGMA does not accept bound parameters.
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
 	}
 	return nil
 }
@@ -72,47 +348,198 @@ func (db *DB) EnsureUser(username string) (bool, error) {
 	return n > 0, nil
 }
 
-// GetProgress returns the list of completed lesson IDs for a user.
-func (db *DB) GetProgress(username string) ([]string, error) {
-	rows, err := db.conn.Query(
-		"SELECT lesson_id FROM progress WHERE username = ? ORDER BY completed_at",
-		username,
+// SetUserLocale stores the user's preferred content locale. An empty
+// locale clears the preference.
+func (db *DB) SetUserLocale(username, locale string) error {
+	res, err := db.conn.Exec("UPDATE users SET locale = ? WHERE username = ?", locale, username)
+	if err != nil {
+		return fmt.Errorf("set user locale: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetUserLocale returns the user's preferred content locale, or "" when
+// the user has none or does not exist.
+func (db *DB) GetUserLocale(username string) (string, error) {
+	var locale string
+	err := db.conn.QueryRow("SELECT locale FROM users WHERE username = ?", username).Scan(&locale)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get user locale: %w", err)
+	}
+	return locale, nil
+}
+
+// GetProgress returns the per-component progress rows for a user, keyed by
+// lesson ID. Lessons the user has not touched are absent from the map.
+// Rule, ExamplesTotal, Completed and Updated are left for the caller to
+// resolve.
+func (db *CourseDB) GetProgress(username string) (map[string]models.LessonProgress, error) {
+	rows, err := db.conn.Query(`
+SELECT lesson_id, read_at IS NOT NULL, quiz_score, quiz_passed_at IS NOT NULL,
+       exercise_passed_at IS NOT NULL, COALESCE(lesson_version, ''), COALESCE(quiz_version, ''),
+       lesson_version_at, quiz_version_at
+FROM lesson_progress WHERE username = ? AND course_id = ?`,
+		username, db.course,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("get progress: %w", err)
 	}
 	defer rows.Close()
 
-	var lessons []string
+	progress := make(map[string]models.LessonProgress)
 	for rows.Next() {
-		var id string
-		if err := rows.Scan(&id); err != nil {
+		var p models.LessonProgress
+		var score sql.NullInt64
+		var lessonAt, quizAt sql.NullTime
+		if err := rows.Scan(&p.LessonID, &p.Read, &score, &p.QuizPassed, &p.ExercisePassed,
+			&p.LessonVersion, &p.QuizVersion, &lessonAt, &quizAt); err != nil {
 			return nil, fmt.Errorf("scan progress: %w", err)
 		}
-		lessons = append(lessons, id)
+		if score.Valid {
+			s := int(score.Int64)
+			p.QuizScore = &s
+		}
+		for _, t := range []sql.NullTime{lessonAt, quizAt} {
+			if t.Valid && (p.VersionAt.IsZero() || t.Time.Before(p.VersionAt)) {
+				p.VersionAt = t.Time
+			}
+		}
+		progress[p.LessonID] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get progress: %w", err)
 	}
-	return lessons, rows.Err()
+
+	runs, err := db.conn.Query(
+		"SELECT lesson_id, COUNT(*) FROM example_runs WHERE username = ? AND course_id = ? GROUP BY lesson_id",
+		username, db.course,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get example runs: %w", err)
+	}
+	defer runs.Close()
+
+	for runs.Next() {
+		var id string
+		var n int
+		if err := runs.Scan(&id, &n); err != nil {
+			return nil, fmt.Errorf("scan example runs: %w", err)
+		}
+		p := progress[id]
+		p.LessonID = id
+		p.ExamplesRun = n
+		progress[id] = p
+	}
+	return progress, runs.Err()
+}
+
+// MarkRead records that the user has read the given version of a lesson.
+// The first read time is kept; the version is that of the latest read.
+func (db *CourseDB) MarkRead(username, lessonID, version string) error {
+	_, err := db.conn.Exec(`
+INSERT INTO lesson_progress (username, course_id, lesson_id, read_at, lesson_version, lesson_version_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (username, course_id, lesson_id) DO UPDATE SET
+    read_at = COALESCE(read_at, excluded.read_at),`+stampLessonVersion,
+		username, db.course, lessonID, version,
+	)
+	if err != nil {
+		return fmt.Errorf("mark read: %w", err)
+	}
+	return nil
 }
 
-// MarkCompleted records that the user completed a lesson.
-func (db *DB) MarkCompleted(username, lessonID string) error {
+// MarkExercisePassed records that the user's exercise solution passed on
+// the given version of the lesson.
+func (db *CourseDB) MarkExercisePassed(username, lessonID, version string) error {
+	_, err := db.conn.Exec(`
+INSERT INTO lesson_progress (username, course_id, lesson_id, exercise_passed_at, lesson_version, lesson_version_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (username, course_id, lesson_id) DO UPDATE SET
+    exercise_passed_at = COALESCE(exercise_passed_at, excluded.exercise_passed_at),`+stampLessonVersion,
+		username, db.course, lessonID, version,
+	)
+	if err != nil {
+		return fmt.Errorf("mark exercise passed: %w", err)
+	}
+	return nil
+}
+
+// stampLessonVersion ends an upsert into lesson_progress by recording the
+// inserted lesson version, keeping the time it was first recorded.
+const stampLessonVersion = `
+    lesson_version_at = CASE WHEN lesson_version IS excluded.lesson_version
+        THEN lesson_version_at ELSE excluded.lesson_version_at END,
+    lesson_version = excluded.lesson_version`
+
+// StampLegacyProgress records the current lesson and quiz versions, keyed
+// by lesson ID, on progress saved before content was versioned, as of when
+// it was saved. Learners are thus only told about changes made from now on.
+func (db *CourseDB) StampLegacyProgress(lessons, quizzes map[string]string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("stamp legacy progress: %w", err)
+	}
+	defer tx.Rollback()
+
+	for id, version := range lessons {
+		if _, err := tx.Exec(`
+UPDATE lesson_progress SET lesson_version = ?, lesson_version_at = COALESCE(read_at, exercise_passed_at)
+WHERE course_id = ? AND lesson_id = ? AND lesson_version IS NULL
+  AND COALESCE(read_at, exercise_passed_at) IS NOT NULL`,
+			version, db.course, id,
+		); err != nil {
+			return fmt.Errorf("stamp legacy progress: %w", err)
+		}
+	}
+	for id, version := range quizzes {
+		if _, err := tx.Exec(`
+UPDATE lesson_progress SET quiz_version = ?, quiz_version_at = quiz_passed_at
+WHERE course_id = ? AND lesson_id = ? AND quiz_version IS NULL AND quiz_passed_at IS NOT NULL`,
+			version, db.course, id,
+		); err != nil {
+			return fmt.Errorf("stamp legacy progress: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// MarkExampleRun records that the user ran one of a lesson's code examples.
+func (db *CourseDB) MarkExampleRun(username, lessonID string, index int) error {
 	_, err := db.conn.Exec(
-		"INSERT OR IGNORE INTO progress (username, lesson_id) VALUES (?, ?)",
-		username, lessonID,
+		"INSERT OR IGNORE INTO example_runs (username, course_id, lesson_id, example_index) VALUES (?, ?, ?, ?)",
+		username, db.course, lessonID, index,
 	)
 	if err != nil {
-		return fmt.Errorf("mark completed: %w", err)
+		return fmt.Errorf("mark example run: %w", err)
 	}
 	return nil
 }
 
-// ResetProgress deletes all progress records for a user.
-func (db *DB) ResetProgress(username string) error {
-	_, err := db.conn.Exec("DELETE FROM progress WHERE username = ?", username)
+// ResetProgress deletes a user's completion records in the course: lessons
+// read, quizzes and exercises passed, examples run and the review queue.
+// Quiz attempts and sessions and exam sessions are kept, since attempt
+// limits and exam cooldowns count them and the score history and question
+// statistics are built from them.
+func (db *CourseDB) ResetProgress(username string) error {
+	tx, err := db.conn.Begin()
 	if err != nil {
 		return fmt.Errorf("reset progress: %w", err)
 	}
-	return nil
+	defer tx.Rollback()
+
+	for _, table := range []string{"lesson_progress", "example_runs", "review_items"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE username = ? AND course_id = ?", username, db.course); err != nil {
+			return fmt.Errorf("reset progress: %w", err)
+		}
+	}
+	return tx.Commit()
 }
 
 // DBPath returns the database file path from env or default.
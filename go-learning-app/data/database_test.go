@@ -0,0 +1,53 @@
+package data
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-learning-app/models"
+)
+
+// testDB opens an empty database for the course c.
+func testDB(t *testing.T) *CourseDB {
+	t.Helper()
+	db, err := NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db.Course("c")
+}
+
+func TestResetProgressKeepsAttempts(t *testing.T) {
+	db := testDB(t)
+	if err := db.MarkRead("u", "1-1", ""); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	session, err := db.StartQuizSession("u", "1-1", "", 1, []string{"q"})
+	if err != nil {
+		t.Fatalf("StartQuizSession: %v", err)
+	}
+	if _, err := db.StartExamSession(models.ExamSession{ExamID: "final", Username: "u", Deadline: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("StartExamSession: %v", err)
+	}
+	if _, err := db.RecordAttempt(models.QuizAttempt{SessionID: session, Username: "u", LessonID: "1-1", Correct: 1, Total: 1, Score: 100, Passed: true}); err != nil {
+		t.Fatalf("RecordAttempt: %v", err)
+	}
+
+	if err := db.ResetProgress("u"); err != nil {
+		t.Fatalf("ResetProgress: %v", err)
+	}
+	if progress, err := db.GetProgress("u"); err != nil || len(progress) != 0 {
+		t.Errorf("GetProgress after reset = %v, %v; want no lessons", progress, err)
+	}
+	if n, err := db.CountAttempts("u", "1-1"); err != nil || n != 1 {
+		t.Errorf("CountAttempts after reset = %d, %v; want 1", n, err)
+	}
+	if sessions, err := db.GetExamSessions("u", "final"); err != nil || len(sessions) != 1 {
+		t.Errorf("GetExamSessions after reset = %v, %v; want the session", sessions, err)
+	}
+	if scores, err := db.GetScores("u"); err != nil || len(scores) != 1 {
+		t.Errorf("GetScores after reset = %v, %v; want the attempt's lesson", scores, err)
+	}
+}
@@ -0,0 +1,129 @@
+package data
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-learning-app/models"
+)
+
+const examSessionColumns = `id, username, exam_id, seed, question_ids, answers, started_at, deadline,
+       submitted_at, score, passed`
+
+func scanExamSession(row interface{ Scan(...any) error }) (models.ExamSession, error) {
+	var s models.ExamSession
+	var ids, answers string
+	var submitted sql.NullTime
+	if err := row.Scan(&s.ID, &s.Username, &s.ExamID, &s.Seed, &ids, &answers,
+		&s.StartedAt, &s.Deadline, &submitted, &s.Score, &s.Passed); err != nil {
+		return s, err
+	}
+	s.SubmittedAt = submitted.Time
+	if err := json.Unmarshal([]byte(ids), &s.QuestionIDs); err != nil {
+		return s, fmt.Errorf("decode exam session %d: %w", s.ID, err)
+	}
+	if err := json.Unmarshal([]byte(answers), &s.Answers); err != nil {
+		return s, fmt.Errorf("decode exam session %d: %w", s.ID, err)
+	}
+	return s, nil
+}
+
+// StartExamSession stores a new exam session and returns its ID.
+func (db *CourseDB) StartExamSession(s models.ExamSession) (int64, error) {
+	ids, err := json.Marshal(s.QuestionIDs)
+	if err != nil {
+		return 0, fmt.Errorf("start exam session: %w", err)
+	}
+	res, err := db.conn.Exec(`
+INSERT INTO exam_sessions (username, course_id, exam_id, seed, question_ids, started_at, deadline)
+VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		s.Username, db.course, s.ExamID, s.Seed, string(ids), sqlTime(s.StartedAt), sqlTime(s.Deadline),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("start exam session: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// GetExamSession returns an exam session of the course by ID, or
+// ErrNotFound.
+func (db *CourseDB) GetExamSession(id int64) (models.ExamSession, error) {
+	s, err := scanExamSession(db.conn.QueryRow(
+		"SELECT "+examSessionColumns+" FROM exam_sessions WHERE id = ? AND course_id = ?", id, db.course,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, ErrNotFound
+	}
+	if err != nil {
+		return s, fmt.Errorf("get exam session: %w", err)
+	}
+	return s, nil
+}
+
+// GetExamSessions returns a user's sessions of one exam, oldest first. An
+// empty examID returns the sessions of every exam.
+func (db *CourseDB) GetExamSessions(username, examID string) ([]models.ExamSession, error) {
+	rows, err := db.conn.Query(
+		"SELECT "+examSessionColumns+` FROM exam_sessions
+WHERE username = ? AND course_id = ? AND (? = '' OR exam_id = ?) ORDER BY id`,
+		username, db.course, examID, examID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get exam sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []models.ExamSession
+	for rows.Next() {
+		s, err := scanExamSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan exam session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// SaveExamAnswers replaces the saved answers of an open exam session. It
+// returns ErrAlreadySubmitted if the session has been graded.
+func (db *CourseDB) SaveExamAnswers(id int64, answers map[string]json.RawMessage) error {
+	raw, err := json.Marshal(answers)
+	if err != nil {
+		return fmt.Errorf("save exam answers: %w", err)
+	}
+	res, err := db.conn.Exec(
+		"UPDATE exam_sessions SET answers = ? WHERE id = ? AND course_id = ? AND submitted_at IS NULL",
+		string(raw), id, db.course,
+	)
+	if err != nil {
+		return fmt.Errorf("save exam answers: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrAlreadySubmitted
+	}
+	return nil
+}
+
+// FinishExamSession stores the final answers and grade of an exam session.
+// It returns ErrAlreadySubmitted if the session has already been graded.
+func (db *CourseDB) FinishExamSession(s models.ExamSession, at time.Time) error {
+	raw, err := json.Marshal(s.Answers)
+	if err != nil {
+		return fmt.Errorf("finish exam session: %w", err)
+	}
+	res, err := db.conn.Exec(`
+UPDATE exam_sessions SET answers = ?, submitted_at = ?, score = ?, passed = ?
+WHERE id = ? AND course_id = ? AND submitted_at IS NULL`,
+		string(raw), sqlTime(at), s.Score, s.Passed, s.ID, db.course,
+	)
+	if err != nil {
+		return fmt.Errorf("finish exam session: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrAlreadySubmitted
+	}
+	return nil
+}
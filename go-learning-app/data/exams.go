@@ -0,0 +1,120 @@
+package data
+
+import (
+	"fmt"
+
+	"go-learning-app/models"
+)
+
+// FinalExamID is the ID of the cumulative final exam.
+const FinalExamID = "final"
+
+// ChapterExamID returns the exam ID of a chapter.
+func ChapterExamID(chapterID int) string {
+	return fmt.Sprintf("ch%d", chapterID)
+}
+
+// examTitles holds the exam title formats per locale: the chapter exam's,
+// given the chapter ID and title, and the final exam's.
+var examTitles = map[string][2]string{
+	DefaultLocale: {"第%d章 章末試験: %s", "修了試験"},
+	"en":          {"Chapter %d exam: %s", "Final exam"},
+}
+
+// finalExamPolicy grades the final exam, which no chapter's policy covers.
+var finalExamPolicy = models.ScoringPolicy{PassPercent: 70}
+
+// loadExams adds a chapter exam per chapter and the final exam. It must run
+// after every chapter is loaded. A chapter exam is graded under its
+// chapter's policy, as the chapter's quizzes are unless they set their own.
+func loadExams(s *Store) {
+	titles, ok := examTitles[s.Locale]
+	if !ok {
+		titles = examTitles[DefaultLocale]
+	}
+	for _, ch := range s.Chapters {
+		policy := models.DefaultScoringPolicy
+		if ch.Policy != nil {
+			policy = ch.Policy.Inherit(policy)
+		}
+		policy.MaxAttempts = 0
+		s.exams = append(s.exams, models.Exam{
+			ID:           ChapterExamID(ch.ID),
+			Title:        fmt.Sprintf(titles[0], ch.ID, ch.Title),
+			ChapterID:    ch.ID,
+			Questions:    8,
+			TimeLimitSec: 10 * 60,
+			CooldownSec:  30 * 60,
+			Policy:       policy,
+		})
+	}
+	s.exams = append(s.exams, models.Exam{
+		ID:           FinalExamID,
+		Title:        titles[1],
+		Questions:    30,
+		TimeLimitSec: 45 * 60,
+		MaxAttempts:  3,
+		CooldownSec:  24 * 60 * 60,
+		Policy:       finalExamPolicy,
+	})
+}
+
+// GetExams returns every exam, chapter exams first in chapter order.
+func (s *Store) GetExams() []models.Exam {
+	return s.exams
+}
+
+// GetExam returns an exam by ID.
+func (s *Store) GetExam(id string) (models.Exam, bool) {
+	for _, e := range s.exams {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return models.Exam{}, false
+}
+
+// ExamPool returns the question pool of an exam as a quiz: every question
+// of the chapter's lessons, or of the whole course for the final exam,
+// graded under the exam's policy.
+func (s *Store) ExamPool(exam models.Exam) models.Quiz {
+	policy := exam.Policy
+	pool := models.Quiz{LessonID: exam.ID, Draw: exam.Questions, Policy: &policy}
+	for _, ch := range s.Chapters {
+		if exam.ChapterID != 0 && ch.ID != exam.ChapterID {
+			continue
+		}
+		for _, l := range ch.Lessons {
+			if q, ok := s.quizzes[l.ID]; ok {
+				pool.Questions = append(pool.Questions, q.Questions...)
+			}
+		}
+	}
+	return pool
+}
+
+// ResolveChapters returns the progress of every chapter from the resolved
+// lesson progress and the set of passed exam IDs.
+func (s *Store) ResolveChapters(lessons []models.LessonProgress, passedExams map[string]bool) []models.ChapterProgress {
+	completed := make(map[string]bool)
+	for _, p := range lessons {
+		completed[p.LessonID] = p.Completed
+	}
+
+	chapters := make([]models.ChapterProgress, 0, len(s.Chapters))
+	for _, ch := range s.Chapters {
+		cp := models.ChapterProgress{
+			ChapterID:    ch.ID,
+			LessonsTotal: len(ch.Lessons),
+			ExamPassed:   passedExams[ChapterExamID(ch.ID)],
+		}
+		for _, l := range ch.Lessons {
+			if completed[l.ID] {
+				cp.LessonsCompleted++
+			}
+		}
+		cp.Completed = cp.LessonsCompleted == cp.LessonsTotal && cp.ExamPassed
+		chapters = append(chapters, cp)
+	}
+	return chapters
+}
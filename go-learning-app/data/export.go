@@ -0,0 +1,291 @@
+package data
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"go-learning-app/models"
+)
+
+// ExportContent writes every course's chapters, lessons and quizzes to dir
+// in the layout LoadCatalog reads, so that loading dir gives back the same
+// content. It was used to move the course out of Go literals and is kept
+// to normalize content files. Exams are not content files and are skipped.
+// Translations are written back as they were read.
+func ExportContent(c *Catalog, dir string) error {
+	return c.export(func(name string, data []byte) error {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(file, data, 0o644)
+	})
+}
+
+// Files returns the content files ExportContent would write, keyed by
+// slash-separated path.
+func (c *Catalog) Files() (map[string][]byte, error) {
+	return collect(c.export)
+}
+
+// Files returns the files of the store's course, keyed by slash-separated
+// path under the course directory.
+func (s *Store) Files() (map[string][]byte, error) {
+	return collect(s.export)
+}
+
+// collect gathers the files an export writes.
+func collect(export func(writeFunc) error) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := export(func(name string, data []byte) error {
+		if prev, ok := files[name]; ok && !bytes.Equal(prev, data) {
+			return fmt.Errorf("two different files would be written to %s", name)
+		}
+		files[name] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// writeFunc writes one exported file, named by its slash-separated path
+// under the content root.
+type writeFunc func(name string, data []byte) error
+
+func (c *Catalog) export(write writeFunc) error {
+	for _, s := range c.courses {
+		err := s.export(func(name string, data []byte) error {
+			return write(path.Join(s.Course.ID, name), data)
+		})
+		if err != nil {
+			return fmt.Errorf("export course %s: %w", s.Course.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) export(write writeFunc) error {
+	// Assets are written last, except where the content itself now writes
+	// the file.
+	written := make(map[string]bool)
+	next := write
+	write = func(name string, data []byte) error {
+		written[name] = true
+		return next(name, data)
+	}
+	course := courseDoc{
+		ID:          s.Course.ID,
+		Title:       s.Course.Title,
+		Description: s.Course.Description,
+		Order:       s.Course.Order,
+	}
+	if err := writeYAML(write, courseFile, course); err != nil {
+		return err
+	}
+	if err := s.exportGlossary(write); err != nil {
+		return err
+	}
+	for _, ch := range s.Chapters {
+		if err := exportChapter(s, ch, write); err != nil {
+			return fmt.Errorf("export chapter %d: %w", ch.ID, err)
+		}
+	}
+	for _, src := range s.translationSources {
+		dir := "."
+		if src.chapterID != 0 {
+			dir = chapterDir(src.chapterID)
+		}
+		if err := write(path.Join(dir, src.name), src.raw); err != nil {
+			return fmt.Errorf("export translation: %w", err)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.assets)) {
+		if written[name] {
+			continue
+		}
+		if err := write(name, s.assets[name]); err != nil {
+			return fmt.Errorf("export %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// chapterDir names the directory of an exported chapter.
+func chapterDir(id int) string {
+	return fmt.Sprintf("chapter%02d", id)
+}
+
+func exportChapter(s *Store, ch models.Chapter, write writeFunc) error {
+	dir := chapterDir(ch.ID)
+	doc := chapterDoc{
+		ID:          ch.ID,
+		Title:       ch.Title,
+		Description: ch.Description,
+		Policy:      policyDocOf(ch.Policy),
+	}
+	if ch.Gating != models.GatingAdvisory {
+		doc.Gating = ch.Gating
+	}
+	for _, l := range ch.Lessons {
+		doc.Lessons = append(doc.Lessons, l.ID)
+	}
+	if err := writeYAML(write, path.Join(dir, chapterFile), doc); err != nil {
+		return err
+	}
+
+	for _, summary := range ch.Lessons {
+		l, ok := s.lessons[summary.ID]
+		if !ok {
+			return fmt.Errorf("lesson %s: %w", summary.ID, ErrNotFound)
+		}
+		if err := exportLesson(l, dir, write); err != nil {
+			return fmt.Errorf("lesson %s: %w", l.ID, err)
+		}
+		if q, ok := s.quizzes[l.ID]; ok {
+			if err := exportQuiz(q, path.Join(dir, l.ID+quizExt), write); err != nil {
+				return fmt.Errorf("quiz %s: %w", l.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func exportLesson(l models.Lesson, dir string, write writeFunc) error {
+	doc := lessonDoc{ID: l.ID, Title: l.Title, Requires: l.Requires, Notes: l.Notes}
+	for _, c := range l.Changelog {
+		doc.Changelog = append(doc.Changelog, changeDoc{Date: c.Date, Note: c.Note})
+	}
+	if l.Completion != nil && *l.Completion != models.DefaultCompletionRule {
+		c := l.Completion
+		doc.Completion = &completionDoc{Read: c.Read, Quiz: c.Quiz, Exercise: c.Exercise, Examples: c.Examples}
+	}
+	for i, ex := range l.CodeExamples {
+		file := chapterRel(ex.File)
+		if file == "" {
+			file = fmt.Sprintf("%s/%s-%d%s", examplesDir, l.ID, i+1, codeExt(ex.Code))
+		}
+		if err := writeCode(write, path.Join(dir, file), ex.Code); err != nil {
+			return err
+		}
+		d := exampleDoc{Title: ex.Title, File: file, Nondeterministic: ex.Nondeterministic}
+		if ex.Check != "" && ex.Check != defaultCheck(file) {
+			d.Check = ex.Check
+		}
+		if ex.Output != "" && cmp.Or(ex.Check, defaultCheck(file)) == models.CheckRun {
+			if err := write(path.Join(dir, GoldenFile(file)), []byte(ex.Output)); err != nil {
+				return err
+			}
+		}
+		doc.Examples = append(doc.Examples, d)
+	}
+	if ex := l.Exercise; ex != nil {
+		file := chapterRel(ex.StarterFile)
+		if file == "" {
+			file = fmt.Sprintf("%s/%s%s", exercisesDir, l.ID, codeExt(ex.StarterCode))
+		}
+		if err := writeCode(write, path.Join(dir, file), ex.StarterCode); err != nil {
+			return err
+		}
+		doc.Exercise = &exerciseDoc{
+			Title:          ex.Title,
+			Description:    ex.Description,
+			Starter:        file,
+			ExpectedOutput: ex.ExpectedOutput,
+		}
+	}
+
+	front, err := marshalYAML(doc)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterLine + "\n")
+	buf.Write(front)
+	buf.WriteString(frontMatterLine + "\n\n")
+	buf.WriteString(l.Content)
+	buf.WriteString("\n")
+	return write(path.Join(dir, l.ID+lessonExt), buf.Bytes())
+}
+
+func exportQuiz(q models.Quiz, file string, write writeFunc) error {
+	doc := quizDoc{Draw: q.Draw, Policy: policyDocOf(q.Policy)}
+	for _, question := range q.Questions {
+		d := questionDoc{
+			ID:          question.ID,
+			Type:        question.Type,
+			Text:        question.Text,
+			Options:     question.Options,
+			Answers:     question.Answers,
+			Code:        question.Code,
+			Accepted:    question.Accepted,
+			Lines:       question.Lines,
+			Pattern:     question.Pattern,
+			Explanation: question.Explanation,
+			Weight:      question.Weight,
+		}
+		if question.Kind() == models.QuestionSingle {
+			d.Answer = &question.Answer
+		}
+		doc.Questions = append(doc.Questions, d)
+	}
+	return writeYAML(write, file, doc)
+}
+
+func policyDocOf(p *models.ScoringPolicy) *policyDoc {
+	if p == nil {
+		return nil
+	}
+	return &policyDoc{PassPercent: p.PassPercent, MaxAttempts: p.MaxAttempts, NegativeMarking: p.NegativeMarking}
+}
+
+func marshalYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeYAML(write writeFunc, file string, v any) error {
+	b, err := marshalYAML(v)
+	if err != nil {
+		return err
+	}
+	return write(file, b)
+}
+
+// chapterRel returns the path of a content file relative to its chapter
+// directory, or "" for code that was not loaded from a file.
+func chapterRel(file string) string {
+	_, rel, _ := strings.Cut(file, "/")
+	return rel
+}
+
+// codeExt returns the file extension for a snippet: .go for Go source and
+// .txt for anything else, such as the contents of a go.mod file.
+func codeExt(code string) string {
+	if strings.HasPrefix(code, "package ") || strings.Contains(code, "\npackage ") {
+		return ".go"
+	}
+	return ".txt"
+}
+
+func writeCode(write writeFunc, file, code string) error {
+	return write(file, []byte(code+"\n"))
+}
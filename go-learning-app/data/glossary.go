@@ -0,0 +1,192 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"go-learning-app/markdown"
+	"go-learning-app/models"
+)
+
+// glossaryFile holds a course's glossary. It is optional.
+const glossaryFile = "glossary.yaml"
+
+type glossaryDoc struct {
+	Terms []termDoc `yaml:"terms"`
+}
+
+type termDoc struct {
+	ID         string   `yaml:"id"`
+	Term       string   `yaml:"term"`
+	Reading    string   `yaml:"reading,omitempty"`
+	Aliases    []string `yaml:"aliases,omitempty"`
+	Lesson     string   `yaml:"lesson,omitempty"`
+	Related    []string `yaml:"related,omitempty"`
+	Definition string   `yaml:"definition"`
+}
+
+// loadGlossary reads the glossary, once every lesson is loaded so the
+// lessons terms point to can be checked.
+func (l *loader) loadGlossary() {
+	raw, err := fs.ReadFile(l.fsys, glossaryFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return // courses without a glossary are allowed
+	}
+	if err != nil {
+		l.errorf(glossaryFile, 0, "%v", err)
+		return
+	}
+	var doc glossaryDoc
+	root, ok := l.decode(glossaryFile, raw, 0, &doc)
+	if !ok {
+		return
+	}
+
+	ids := make(map[string]bool)
+	// words maps each term and alias to the ID of the entry using it, as
+	// a word can only link to one entry.
+	words := make(map[string]string)
+	for i, d := range doc.Terms {
+		line := func(p ...any) int { return lineOf(root, append([]any{"terms", i}, p...)...) }
+		switch {
+		case !coursePattern.MatchString(d.ID):
+			l.errorf(glossaryFile, line("id"), "term %d: id %q must be lowercase letters, digits and dashes", i+1, d.ID)
+			continue
+		case ids[d.ID]:
+			l.errorf(glossaryFile, line("id"), "duplicate term id %q", d.ID)
+			continue
+		}
+		ids[d.ID] = true
+		if strings.TrimSpace(d.Term) == "" {
+			l.errorf(glossaryFile, line("term"), "term %s: term is required", d.ID)
+		}
+		if strings.TrimSpace(d.Definition) == "" {
+			l.errorf(glossaryFile, line("definition"), "term %s: definition is required", d.ID)
+		}
+		for j, w := range append([]string{d.Term}, d.Aliases...) {
+			if w = strings.TrimSpace(w); w == "" {
+				continue
+			}
+			if other, dup := words[w]; dup {
+				p := []any{"term"}
+				if j > 0 {
+					p = []any{"aliases", j - 1}
+				}
+				l.errorf(glossaryFile, line(p...), "term %s: %q is already used by term %s", d.ID, w, other)
+				continue
+			}
+			words[w] = d.ID
+		}
+		if _, ok := l.store.lessons[d.Lesson]; d.Lesson != "" && !ok {
+			l.errorf(glossaryFile, line("lesson"), "term %s: unknown lesson %q", d.ID, d.Lesson)
+		}
+		l.store.glossary = append(l.store.glossary, models.GlossaryTerm{
+			ID:         d.ID,
+			Term:       d.Term,
+			Reading:    d.Reading,
+			Aliases:    d.Aliases,
+			Definition: d.Definition,
+			Lesson:     d.Lesson,
+			Related:    d.Related,
+		})
+	}
+	for i, d := range doc.Terms {
+		for j, id := range d.Related {
+			if !ids[id] || id == d.ID {
+				l.errorf(glossaryFile, lineOf(root, "terms", i, "related", j), "term %s: unknown related term %q", d.ID, id)
+			}
+		}
+	}
+}
+
+// Glossary returns the course's glossary terms in the order they are
+// written.
+func (s *Store) Glossary() []models.GlossaryTerm {
+	return s.glossary
+}
+
+// GlossaryTerm returns a glossary term by ID.
+func (s *Store) GlossaryTerm(id string) (models.GlossaryTerm, bool) {
+	for _, t := range s.glossary {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return models.GlossaryTerm{}, false
+}
+
+// glossaryLinks returns the terms lesson text links to the glossary,
+// given the href of a term.
+func (s *Store) glossaryLinks(href func(id string) string) []markdown.Term {
+	var terms []markdown.Term
+	for _, t := range s.glossary {
+		terms = append(terms, markdown.Term{
+			Words: words(t),
+			Href:  href(t.ID),
+			Title: strings.TrimSpace(plainText(markdown.RenderInline(t.Definition))),
+		})
+	}
+	return terms
+}
+
+// renderGlossary renders the definitions and finds the lessons mentioning
+// each term.
+func (s *Store) renderGlossary() {
+	for i := range s.glossary {
+		t := &s.glossary[i]
+		t.DefinitionHTML = markdown.RenderInline(t.Definition)
+		t.Mentions = []string{}
+		for _, ch := range s.Chapters {
+			for _, summary := range ch.Lessons {
+				if l, ok := s.lessons[summary.ID]; ok && mentions(l, words(*t)) {
+					t.Mentions = append(t.Mentions, l.ID)
+				}
+			}
+		}
+	}
+}
+
+// words returns the term and its aliases.
+func words(t models.GlossaryTerm) []string {
+	return append([]string{t.Term}, t.Aliases...)
+}
+
+// mentions reports whether the text of a lesson contains any of words.
+func mentions(l models.Lesson, words []string) bool {
+	text := []string{l.Title, plainText(l.Content)}
+	text = append(text, l.Notes...)
+	if l.Exercise != nil {
+		text = append(text, l.Exercise.Description)
+	}
+	all := strings.Join(text, "\n")
+	for _, w := range words {
+		if markdown.Index(all, w) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) exportGlossary(write writeFunc) error {
+	if len(s.glossary) == 0 {
+		return nil
+	}
+	var doc glossaryDoc
+	for _, t := range s.glossary {
+		doc.Terms = append(doc.Terms, termDoc{
+			ID:         t.ID,
+			Term:       t.Term,
+			Reading:    t.Reading,
+			Aliases:    t.Aliases,
+			Lesson:     t.Lesson,
+			Related:    t.Related,
+			Definition: t.Definition,
+		})
+	}
+	if err := writeYAML(write, glossaryFile, doc); err != nil {
+		return fmt.Errorf("export glossary: %w", err)
+	}
+	return nil
+}
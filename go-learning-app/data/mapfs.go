@@ -0,0 +1,135 @@
+package data
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// mapFS serves files keyed by slash-separated path as a read-only file
+// system. Directories are implied by the paths of the files in them.
+type mapFS map[string][]byte
+
+func (m mapFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if data, ok := m[name]; ok {
+		return &mapFile{info: mapInfo{name: path.Base(name), size: int64(len(data))}, r: bytes.NewReader(data)}, nil
+	}
+	entries, ok := m.entries(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &mapDir{info: mapInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+func (m mapFS) ReadFile(name string) ([]byte, error) {
+	data, ok := m[name]
+	if !ok || !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return slices.Clone(data), nil
+}
+
+func (m mapFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, ok := m.entries(name)
+	if !ok || !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	return entries, nil
+}
+
+// entries lists the directory name sorted by file name, and reports
+// whether it exists: the root always does, any other directory only if
+// it holds a file.
+func (m mapFS) entries(name string) ([]fs.DirEntry, bool) {
+	prefix := ""
+	if name != "." {
+		prefix = name + "/"
+	}
+	seen := make(map[string]bool)
+	var entries []fs.DirEntry
+	for file, data := range m {
+		rest, ok := strings.CutPrefix(file, prefix)
+		if !ok {
+			continue
+		}
+		child, _, isDir := strings.Cut(rest, "/")
+		if seen[child] {
+			continue
+		}
+		seen[child] = true
+		info := mapInfo{name: child, dir: isDir}
+		if !isDir {
+			info.size = int64(len(data))
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	if len(entries) == 0 && name != "." {
+		return nil, false
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, true
+}
+
+// mapInfo describes a file or directory of a mapFS.
+type mapInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i mapInfo) Name() string       { return i.name }
+func (i mapInfo) Size() int64        { return i.size }
+func (i mapInfo) ModTime() time.Time { return time.Time{} }
+func (i mapInfo) IsDir() bool        { return i.dir }
+func (i mapInfo) Sys() any           { return nil }
+
+func (i mapInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+type mapFile struct {
+	info mapInfo
+	r    *bytes.Reader
+}
+
+func (f *mapFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *mapFile) Read(b []byte) (int, error) { return f.r.Read(b) }
+func (f *mapFile) Close() error               { return nil }
+
+type mapDir struct {
+	info    mapInfo
+	entries []fs.DirEntry
+}
+
+func (d *mapDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *mapDir) Close() error               { return nil }
+
+func (d *mapDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir reads the entries not yet read, as fs.ReadDirFile describes.
+func (d *mapDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
@@ -0,0 +1,21 @@
+package data
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestMapFS(t *testing.T) {
+	fsys := mapFS{
+		"go-intro/course.yaml":                []byte("id: go-intro\n"),
+		"go-intro/chapter01/chapter.yaml":     []byte("id: 1\n"),
+		"go-intro/chapter01/_examples/1-1.go": []byte("package main\n"),
+		"go-intro/glossary.yaml":              nil,
+	}
+	if err := fstest.TestFS(fsys, "go-intro/course.yaml", "go-intro/chapter01/_examples/1-1.go", "go-intro/glossary.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Open("go-intro/chapter02"); err == nil {
+		t.Error("Open of a directory without files: no error")
+	}
+}
@@ -0,0 +1,371 @@
+package data
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"go-learning-app/models"
+)
+
+// A course package carries one course between deployments as a zip file:
+//
+//	manifest.yaml         format, course ID and title, and every file
+//	                      with its size and SHA-256
+//	course/course.yaml    the course directory, as LoadStore reads it:
+//	course/chapter01/...  lessons, quizzes, examples with their golden
+//	course/_assets/...    outputs, exercises and any other files
+//
+// Files in the course directory that the loader does not read, such as
+// images under _assets, travel with the package unchanged.
+const (
+	// PackageFormat is the package format WritePackage writes. ReadPackage
+	// accepts this format and older ones.
+	PackageFormat = 1
+
+	manifestFile = "manifest.yaml"
+	packageDir   = "course"
+
+	// maxPackageSize bounds the unpacked size of a package, so a small
+	// zip cannot expand without limit.
+	maxPackageSize = 64 << 20
+)
+
+// Manifest describes a course package.
+type Manifest struct {
+	Format      int           `yaml:"format" json:"format"`
+	ID          string        `yaml:"id" json:"id"`
+	Title       string        `yaml:"title" json:"title"`
+	Description string        `yaml:"description,omitempty" json:"description,omitempty"`
+	Chapters    int           `yaml:"chapters" json:"chapters"`
+	Lessons     int           `yaml:"lessons" json:"lessons"`
+	Files       []PackageFile `yaml:"files" json:"files"`
+}
+
+// PackageFile is a file of a package, with its path in the course
+// directory.
+type PackageFile struct {
+	Path   string `yaml:"path" json:"path"`
+	Size   int    `yaml:"size" json:"size"`
+	SHA256 string `yaml:"sha256" json:"sha256"`
+}
+
+// Package is a course package that was read and checked.
+type Package struct {
+	Manifest Manifest
+	// Files are the files of the course directory, keyed by path in it.
+	Files map[string][]byte
+	// Store is the course loaded from Files.
+	Store *Store
+}
+
+// WritePackage writes the course in fsys, a course directory, as a
+// package. Hidden files are left out. The course must load.
+func WritePackage(w io.Writer, fsys fs.FS) error {
+	s, err := LoadStore(fsys)
+	if err != nil {
+		return err
+	}
+	files, err := ReadFiles(fsys)
+	if err != nil {
+		return err
+	}
+	return writePackage(w, s, files)
+}
+
+// ReadFiles reads every file in fsys into memory, keyed by path, leaving
+// out hidden files and directories.
+func ReadFiles(fsys fs.FS) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name != "." && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		files[name], err = fs.ReadFile(fsys, name)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read files: %w", err)
+	}
+	return files, nil
+}
+
+// WritePackage writes the course as a package, in the file layout Files
+// writes. The store must be a base store, not a translation.
+func (s *Store) WritePackage(w io.Writer) error {
+	files, err := s.Files()
+	if err != nil {
+		return err
+	}
+	return writePackage(w, s, files)
+}
+
+func writePackage(w io.Writer, s *Store, files map[string][]byte) error {
+	m := Manifest{
+		Format:      PackageFormat,
+		ID:          s.Course.ID,
+		Title:       s.Course.Title,
+		Description: s.Course.Description,
+		Chapters:    len(s.Chapters),
+	}
+	for _, ch := range s.Chapters {
+		m.Lessons += len(ch.Lessons)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		m.Files = append(m.Files, PackageFile{Path: name, Size: len(files[name]), SHA256: checksum(files[name])})
+	}
+	manifest, err := marshalYAML(m)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	now := time.Now()
+	add := func(name string, data []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	if err := add(manifestFile, manifest); err != nil {
+		return fmt.Errorf("write package: %w", err)
+	}
+	for _, name := range names {
+		if err := add(packageDir+"/"+name, files[name]); err != nil {
+			return fmt.Errorf("write package: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("write package: %w", err)
+	}
+	return nil
+}
+
+// ReadPackage reads and checks a package: the manifest must be complete
+// and of a known format, it must list exactly the files in the package
+// with their checksums, and the course must load under the manifest's ID.
+// Problems are returned joined, each as a *ContentError with its path in
+// the package; other errors mean the zip itself could not be read.
+func ReadPackage(r io.ReaderAt, size int64) (*Package, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("read package: %w", err)
+	}
+
+	l := &loader{}
+	var raw []byte
+	files := make(map[string][]byte)
+	total := 0
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		name, inCourse := strings.CutPrefix(f.Name, packageDir+"/")
+		if f.Name != manifestFile && (!inCourse || !fs.ValidPath(name)) {
+			l.errorf(f.Name, 0, "unexpected file outside %s/", packageDir)
+			continue
+		}
+		data, err := readZipFile(f, maxPackageSize-total)
+		if err != nil {
+			return nil, fmt.Errorf("read package: %s: %w", f.Name, err)
+		}
+		total += len(data)
+		if f.Name == manifestFile {
+			raw = data
+			continue
+		}
+		if _, dup := files[name]; dup {
+			l.errorf(f.Name, 0, "file appears more than once")
+		}
+		files[name] = data
+	}
+	if raw == nil {
+		l.errorf(manifestFile, 0, "missing manifest")
+		return nil, errors.Join(l.errs...)
+	}
+
+	var m Manifest
+	if _, ok := l.decode(manifestFile, raw, 0, &m); !ok {
+		return nil, errors.Join(l.errs...)
+	}
+	switch {
+	case m.Format < 1 || m.Format > PackageFormat:
+		l.errorf(manifestFile, 0, "format %d is not supported (this version reads 1..%d)", m.Format, PackageFormat)
+	case !coursePattern.MatchString(m.ID):
+		l.errorf(manifestFile, 0, "id %q must be lowercase letters, digits and dashes", m.ID)
+	case strings.TrimSpace(m.Title) == "":
+		l.errorf(manifestFile, 0, "title is required")
+	}
+	listed := make(map[string]bool)
+	for _, f := range m.Files {
+		file := packageDir + "/" + f.Path
+		data, ok := files[f.Path]
+		switch {
+		case listed[f.Path]:
+			l.errorf(manifestFile, 0, "%s is listed more than once", file)
+		case !ok:
+			l.errorf(manifestFile, 0, "%s is listed but not in the package", file)
+		case len(data) != f.Size || checksum(data) != f.SHA256:
+			l.errorf(file, 0, "does not match its size and checksum in %s", manifestFile)
+		}
+		listed[f.Path] = true
+	}
+	for _, f := range zr.File {
+		name, _ := strings.CutPrefix(f.Name, packageDir+"/")
+		if _, ok := files[name]; ok && !listed[name] {
+			l.errorf(f.Name, 0, "not listed in %s", manifestFile)
+			listed[name] = true
+		}
+	}
+	if len(l.errs) > 0 {
+		return nil, errors.Join(l.errs...)
+	}
+
+	s, err := LoadStore(mapFS(files))
+	if err != nil {
+		return nil, errors.Join(inDir(packageDir, err)...)
+	}
+	if s.Course.ID != m.ID {
+		return nil, &ContentError{
+			Path: packageDir + "/" + courseFile,
+			Msg:  fmt.Sprintf("id %q does not match %q in %s", s.Course.ID, m.ID, manifestFile),
+		}
+	}
+	return &Package{Manifest: m, Files: files, Store: s}, nil
+}
+
+// readZipFile reads a file of a zip, failing if it is larger than limit.
+func readZipFile(f *zip.File, limit int) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var b bytes.Buffer
+	if _, err := io.CopyN(&b, rc, int64(limit)+1); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if b.Len() > limit {
+		return nil, fmt.Errorf("package is larger than %d MB unpacked", maxPackageSize>>20)
+	}
+	return b.Bytes(), nil
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ImportPlan describes what importing a package would change, for a dry
+// run or to confirm an import.
+type ImportPlan struct {
+	Course models.Course `json:"course"`
+	// Exists reports whether there already is a course with the package's
+	// ID, which the import would replace.
+	Exists bool `json:"exists"`
+	// Files are the course's files, by path in the course directory.
+	Files ChangeSet `json:"files"`
+	// Lessons are the course's lessons by ID. A lesson or quiz whose
+	// content changed is listed as changed; learners who completed it see
+	// it as updated.
+	Lessons ChangeSet `json:"lessons"`
+}
+
+// ChangeSet lists what was added, changed and removed, in sorted order.
+type ChangeSet struct {
+	Added   []string `json:"added"`
+	Changed []string `json:"changed"`
+	Removed []string `json:"removed"`
+}
+
+// Empty reports whether nothing changes.
+func (c ChangeSet) Empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Removed) == 0
+}
+
+// PlanImport compares a package with the course it would replace.
+// currentFiles are that course's files, in the same form as the package's,
+// or nil if there is no such course; current is the course loaded from
+// them, or nil if there is none or it does not load.
+func PlanImport(p *Package, current *Store, currentFiles map[string][]byte) ImportPlan {
+	plan := ImportPlan{Course: p.Store.Course, Exists: currentFiles != nil}
+	plan.Files = diff(currentFiles, p.Files, bytes.Equal)
+
+	newLessons, newQuizzes := p.Store.Versions()
+	var oldLessons, oldQuizzes map[string]string
+	if current != nil {
+		oldLessons, oldQuizzes = current.Versions()
+	}
+	plan.Lessons = diff(oldLessons, newLessons, func(a, b string) bool { return a == b })
+	// A lesson whose quiz alone changed is changed too.
+	for id, v := range newQuizzes {
+		if _, ok := oldLessons[id]; ok && oldQuizzes[id] != v && !slices.Contains(plan.Lessons.Changed, id) {
+			plan.Lessons.Changed = append(plan.Lessons.Changed, id)
+		}
+	}
+
+	// List lessons in teaching order rather than by ID, where 1-10 would
+	// come before 1-2.
+	rank := make(map[string]int)
+	for _, s := range []*Store{p.Store, current} {
+		if s == nil {
+			continue
+		}
+		for _, ch := range s.Chapters {
+			for _, l := range ch.Lessons {
+				if _, ok := rank[l.ID]; !ok {
+					rank[l.ID] = len(rank)
+				}
+			}
+		}
+	}
+	byRank := func(a, b string) int { return rank[a] - rank[b] }
+	slices.SortFunc(plan.Lessons.Added, byRank)
+	slices.SortFunc(plan.Lessons.Changed, byRank)
+	slices.SortFunc(plan.Lessons.Removed, byRank)
+	return plan
+}
+
+func diff[V any](old, new map[string]V, equal func(a, b V) bool) ChangeSet {
+	c := ChangeSet{Added: []string{}, Changed: []string{}, Removed: []string{}}
+	for k, v := range new {
+		if o, ok := old[k]; !ok {
+			c.Added = append(c.Added, k)
+		} else if !equal(o, v) {
+			c.Changed = append(c.Changed, k)
+		}
+	}
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			c.Removed = append(c.Removed, k)
+		}
+	}
+	slices.Sort(c.Added)
+	slices.Sort(c.Changed)
+	slices.Sort(c.Removed)
+	return c
+}
@@ -0,0 +1,163 @@
+package data
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testCourse is a small course with an asset and an exercise test, which
+// the loader does not read.
+var testCourse = map[string][]byte{
+	"course.yaml":                      []byte("id: pkg\ntitle: Package\n"),
+	"chapter01/chapter.yaml":           []byte("id: 1\ntitle: Chapter\nlessons:\n  - 1-1\n"),
+	"chapter01/1-1.md":                 []byte("---\nid: 1-1\ntitle: Lesson\n---\nText.\n"),
+	"chapter01/_exercises/1-1_test.go": []byte("package main\n"),
+	"_assets/logo.png":                 {0x89, 'P', 'N', 'G'},
+}
+
+// entry is a file of a test zip.
+type entry struct {
+	name string
+	data []byte
+}
+
+// zipOf writes entries, in order, as a zip.
+func zipOf(t *testing.T, entries ...entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		f, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// manifestOf returns a manifest of the pkg course listing files.
+func manifestOf(files map[string][]byte) entry {
+	var b strings.Builder
+	b.WriteString("format: 1\nid: pkg\ntitle: Package\nchapters: 1\nlessons: 1\nfiles:\n")
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		fmt.Fprintf(&b, "  - path: %s\n    size: %d\n    sha256: %s\n", name, len(files[name]), checksum(files[name]))
+	}
+	return entry{manifestFile, []byte(b.String())}
+}
+
+// courseEntries returns the files as entries under the course directory.
+func courseEntries(files map[string][]byte) []entry {
+	var entries []entry
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		entries = append(entries, entry{packageDir + "/" + name, files[name]})
+	}
+	return entries
+}
+
+func readPackage(raw []byte) (*Package, error) {
+	return ReadPackage(bytes.NewReader(raw), int64(len(raw)))
+}
+
+func TestPackageRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePackage(&buf, mapFS(testCourse)); err != nil {
+		t.Fatalf("WritePackage: %v", err)
+	}
+	p, err := readPackage(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadPackage: %v", err)
+	}
+	if !maps.EqualFunc(p.Files, testCourse, bytes.Equal) {
+		t.Errorf("files = %q, want %q", slices.Sorted(maps.Keys(p.Files)), slices.Sorted(maps.Keys(testCourse)))
+	}
+	if p.Manifest.ID != "pkg" || p.Manifest.Lessons != 1 || len(p.Manifest.Files) != len(testCourse) {
+		t.Errorf("manifest = %+v", p.Manifest)
+	}
+
+	// The course keeps the files it does not read when written back, as
+	// the draft stores it.
+	files, err := p.Store.Files()
+	if err != nil {
+		t.Fatalf("Files: %v", err)
+	}
+	for _, name := range []string{"_assets/logo.png", "chapter01/_exercises/1-1_test.go"} {
+		if !bytes.Equal(files[name], testCourse[name]) {
+			t.Errorf("Files()[%q] = %q, want %q", name, files[name], testCourse[name])
+		}
+	}
+}
+
+func TestReadPackageProblems(t *testing.T) {
+	altered := maps.Clone(testCourse)
+	altered["_assets/logo.png"] = []byte("not a logo")
+
+	tests := []struct {
+		name    string
+		entries []entry
+		want    string
+	}{
+		{
+			name:    "file outside the course directory",
+			entries: append(courseEntries(testCourse), manifestOf(testCourse), entry{"notes.txt", nil}),
+			want:    "notes.txt: unexpected file outside course/",
+		},
+		{
+			name:    "path escaping the course directory",
+			entries: append(courseEntries(testCourse), manifestOf(testCourse), entry{"course/../x", nil}),
+			want:    "course/../x: unexpected file outside course/",
+		},
+		{
+			name:    "duplicate entry",
+			entries: append(courseEntries(testCourse), manifestOf(testCourse), entry{"course/course.yaml", testCourse["course.yaml"]}),
+			want:    "course/course.yaml: file appears more than once",
+		},
+		{
+			name:    "checksum mismatch",
+			entries: append(courseEntries(altered), manifestOf(testCourse)),
+			want:    "course/_assets/logo.png: does not match its size and checksum in manifest.yaml",
+		},
+		{
+			name:    "file not listed",
+			entries: append(courseEntries(testCourse), manifestOf(nil)),
+			want:    "course/course.yaml: not listed in manifest.yaml",
+		},
+		{
+			name:    "listed file missing",
+			entries: append(courseEntries(nil), manifestOf(testCourse)),
+			want:    "manifest.yaml: course/course.yaml is listed but not in the package",
+		},
+		{
+			name:    "missing manifest",
+			entries: courseEntries(testCourse),
+			want:    "manifest.yaml: missing manifest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readPackage(zipOf(t, tt.entries...))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadPackage error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPackageSizeLimit(t *testing.T) {
+	big := maps.Clone(testCourse)
+	big["_assets/big.bin"] = make([]byte, maxPackageSize)
+	_, err := readPackage(zipOf(t, append(courseEntries(big), manifestOf(big))...))
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("ReadPackage error = %v, want the size limit", err)
+	}
+}
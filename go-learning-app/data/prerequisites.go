@@ -0,0 +1,108 @@
+package data
+
+import (
+	"strings"
+
+	"go-learning-app/models"
+)
+
+// requirement is a prerequisite edge as written in a lesson's front matter.
+type requirement struct {
+	lessonID, requires string
+	file               string
+	line               int
+}
+
+// checkPrerequisites checks that every prerequisite names another lesson
+// and that the prerequisites form no cycle.
+func (l *loader) checkPrerequisites() {
+	graph := make(map[string][]requirement)
+	for _, r := range l.requires {
+		switch _, ok := l.store.lessons[r.requires]; {
+		case r.requires == r.lessonID:
+			l.errorf(r.file, r.line, "lesson %s requires itself", r.lessonID)
+		case !ok:
+			l.errorf(r.file, r.line, "requires unknown lesson %q", r.requires)
+		default:
+			graph[r.lessonID] = append(graph[r.lessonID], r)
+		}
+	}
+
+	// Depth-first search; reaching a lesson still on the path closes a
+	// cycle, which is reported at the edge that closes it.
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		path = append(path, id)
+		for _, r := range graph[id] {
+			switch state[r.requires] {
+			case visiting:
+				start := len(path) - 1
+				for path[start] != r.requires {
+					start--
+				}
+				cycle := append(append([]string{}, path[start:]...), r.requires)
+				l.errorf(r.file, r.line, "prerequisite cycle: %s", strings.Join(cycle, " → "))
+			case 0:
+				visit(r.requires)
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+	}
+	for _, ch := range l.store.Chapters {
+		for _, summary := range ch.Lessons {
+			if state[summary.ID] == 0 {
+				visit(summary.ID)
+			}
+		}
+	}
+}
+
+// Gating returns the gating mode of a lesson's chapter.
+func (s *Store) Gating(lessonID string) string {
+	if l, ok := s.lessons[lessonID]; ok {
+		for _, ch := range s.Chapters {
+			if ch.ID == l.ChapterID {
+				return ch.Gating
+			}
+		}
+	}
+	return models.GatingAdvisory
+}
+
+// MissingPrerequisites returns the prerequisites of a lesson that are not
+// in completed, and whether strict gating locks the lesson because of them.
+func (s *Store) MissingPrerequisites(lessonID string, completed map[string]bool) ([]string, bool) {
+	var missing []string
+	for _, id := range s.lessons[lessonID].Requires {
+		if !completed[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing, len(missing) > 0 && s.Gating(lessonID) == models.GatingStrict
+}
+
+// ChaptersFor returns the chapters with each lesson's missing prerequisites
+// and lock state for a learner, given their resolved lesson progress.
+func (s *Store) ChaptersFor(lessons []models.LessonProgress) []models.Chapter {
+	completed := make(map[string]bool)
+	for _, p := range lessons {
+		completed[p.LessonID] = p.Completed
+	}
+	chapters := make([]models.Chapter, len(s.Chapters))
+	for i, ch := range s.Chapters {
+		ch.Lessons = append([]models.LessonSummary(nil), ch.Lessons...)
+		for j := range ch.Lessons {
+			ch.Lessons[j].Missing, ch.Lessons[j].Locked = s.MissingPrerequisites(ch.Lessons[j].ID, completed)
+		}
+		chapters[i] = ch
+	}
+	return chapters
+}
@@ -0,0 +1,90 @@
+package data
+
+import (
+	"slices"
+
+	"go-learning-app/markdown"
+	"go-learning-app/models"
+	"go-learning-app/stddoc"
+)
+
+// headingPrefix is put before heading IDs in lesson HTML, keeping them
+// apart from the IDs of the lesson's other sections.
+const headingPrefix = "heading-"
+
+// Links are the hrefs rendered lessons link to.
+type Links struct {
+	// Heading links a heading of a lesson to itself, given its slug; nil
+	// leaves headings without anchor links.
+	Heading func(lessonID, slug string) string
+	// HeadingIDs returns what a lesson's heading IDs start with, for pages
+	// showing several lessons; nil means section-heading-, as the lesson
+	// view has them.
+	HeadingIDs func(lessonID string) string
+	// Term links to a glossary term.
+	Term func(id string) string
+	// Doc links Go code to the standard library's documentation; nil
+	// leaves code unlinked.
+	Doc markdown.DocLink
+}
+
+// appLinks are the routes of the browser app: lesson/<id>/heading-<slug>
+// opens a lesson at a section, and the glossary and documentation have
+// their own views.
+var appLinks = Links{
+	Heading: func(lessonID, slug string) string { return "#lesson/" + lessonID + "/" + headingPrefix + slug },
+	Term:    func(id string) string { return "#glossary/" + id },
+	Doc:     stddoc.LinkStd,
+}
+
+// renderLessons renders the Markdown of every lesson for the browser.
+func (s *Store) renderLessons() {
+	terms := s.glossaryLinks(appLinks.Term)
+	for id, l := range s.lessons {
+		s.lessons[id] = renderLesson(l, terms, appLinks)
+	}
+}
+
+// RenderLesson renders a lesson's Markdown again with other links, for
+// pages that are read without the app.
+func (s *Store) RenderLesson(l models.Lesson, links Links) models.Lesson {
+	return renderLesson(l, s.glossaryLinks(links.Term), links)
+}
+
+// renderLesson renders a lesson's text, notes, exercise and code. The
+// first occurrence of each glossary term in the text links to the term.
+func renderLesson(l models.Lesson, terms []markdown.Term, links Links) models.Lesson {
+	opts := markdown.Options{
+		IDPrefix: "section-" + headingPrefix,
+		Terms:    terms,
+		DocLink:  links.Doc,
+	}
+	if links.HeadingIDs != nil {
+		opts.IDPrefix = links.HeadingIDs(l.ID)
+	}
+	if links.Heading != nil {
+		opts.Link = func(h string) string { return links.Heading(l.ID, h) }
+	}
+	html, headings := markdown.Render(l.Content, opts)
+	l.ContentHTML = html
+	l.TOC = nil
+	for _, h := range headings {
+		l.TOC = append(l.TOC, models.Heading{Level: h.Level, ID: h.ID, Title: h.Title})
+	}
+	l.NotesHTML = nil
+	for _, n := range l.Notes {
+		l.NotesHTML = append(l.NotesHTML, markdown.RenderInline(n))
+	}
+	l.CodeExamples = slices.Clone(l.CodeExamples)
+	for i, ex := range l.CodeExamples {
+		l.CodeExamples[i].CodeHTML = markdown.HighlightGo(ex.Code, links.Doc)
+	}
+	if l.Exercise != nil {
+		// Translations share the exercise with the base store until
+		// they translate it, so it is copied rather than changed.
+		ex := *l.Exercise
+		ex.DescriptionHTML = markdown.RenderInline(ex.Description)
+		l.Exercise = &ex
+	}
+	return l
+}
@@ -0,0 +1,121 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-learning-app/models"
+)
+
+// timeFormat matches SQLite's CURRENT_TIMESTAMP so times written from Go
+// compare correctly with it as text.
+const timeFormat = "2006-01-02 15:04:05"
+
+func sqlTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+const reviewColumns = `item_id, kind, lesson_id, ease, interval_days, repetitions, due_at, reviewed_at`
+
+func scanReviewItem(row interface{ Scan(...any) error }) (models.ReviewItem, error) {
+	var it models.ReviewItem
+	var reviewed sql.NullTime
+	err := row.Scan(&it.ID, &it.Kind, &it.LessonID, &it.Ease, &it.IntervalDays,
+		&it.Repetitions, &it.DueAt, &reviewed)
+	it.ReviewedAt = reviewed.Time
+	return it, err
+}
+
+// GetReviewItem returns one item of a user's review queue, or ErrNotFound.
+func (db *CourseDB) GetReviewItem(username, itemID string) (models.ReviewItem, error) {
+	it, err := scanReviewItem(db.conn.QueryRow(
+		"SELECT "+reviewColumns+" FROM review_items WHERE username = ? AND course_id = ? AND item_id = ?",
+		username, db.course, itemID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return it, ErrNotFound
+	}
+	if err != nil {
+		return it, fmt.Errorf("get review item: %w", err)
+	}
+	return it, nil
+}
+
+// SaveReviewItem inserts or updates an item in a user's review queue.
+func (db *CourseDB) SaveReviewItem(username string, it models.ReviewItem) error {
+	var reviewed any
+	if !it.ReviewedAt.IsZero() {
+		reviewed = sqlTime(it.ReviewedAt)
+	}
+	_, err := db.conn.Exec(`
+INSERT INTO review_items (username, course_id, `+reviewColumns+`)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (username, course_id, item_id) DO UPDATE SET
+    ease          = excluded.ease,
+    interval_days = excluded.interval_days,
+    repetitions   = excluded.repetitions,
+    due_at        = excluded.due_at,
+    reviewed_at   = excluded.reviewed_at`,
+		username, db.course, it.ID, it.Kind, it.LessonID, it.Ease, it.IntervalDays,
+		it.Repetitions, sqlTime(it.DueAt), reviewed,
+	)
+	if err != nil {
+		return fmt.Errorf("save review item: %w", err)
+	}
+	return nil
+}
+
+// AddReviewItems queues items for a user, leaving items that are already
+// queued untouched.
+func (db *CourseDB) AddReviewItems(username string, items []models.ReviewItem) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("add review items: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, it := range items {
+		if _, err := tx.Exec(`
+INSERT OR IGNORE INTO review_items (username, course_id, item_id, kind, lesson_id, ease, due_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			username, db.course, it.ID, it.Kind, it.LessonID, it.Ease, sqlTime(it.DueAt),
+		); err != nil {
+			return fmt.Errorf("add review item: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DueReviewItems returns up to limit items due at now, most overdue first,
+// and the total number of due items.
+func (db *CourseDB) DueReviewItems(username string, now time.Time, limit int) ([]models.ReviewItem, int, error) {
+	var total int
+	if err := db.conn.QueryRow(
+		"SELECT COUNT(*) FROM review_items WHERE username = ? AND course_id = ? AND due_at <= ?",
+		username, db.course, sqlTime(now),
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count due review items: %w", err)
+	}
+
+	rows, err := db.conn.Query(
+		"SELECT "+reviewColumns+` FROM review_items
+WHERE username = ? AND course_id = ? AND due_at <= ? ORDER BY due_at, item_id LIMIT ?`,
+		username, db.course, sqlTime(now), limit,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get due review items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.ReviewItem
+	for rows.Next() {
+		it, err := scanReviewItem(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan review item: %w", err)
+		}
+		items = append(items, it)
+	}
+	return items, total, rows.Err()
+}
@@ -0,0 +1,66 @@
+package data
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"go-learning-app/search"
+)
+
+// tagPattern matches the HTML tags lesson text may contain.
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// buildIndex indexes the store's lessons for Search, one document per
+// section: the body, each example, the exercise, the notes and the quiz.
+// Quiz explanations are left out so searching does not reveal answers.
+func (s *Store) buildIndex() {
+	var docs []search.Doc
+	for _, ch := range s.Chapters {
+		for _, summary := range ch.Lessons {
+			l, ok := s.lessons[summary.ID]
+			if !ok {
+				continue
+			}
+			docs = append(docs, search.Doc{LessonID: l.ID, Section: "content", Title: l.Title, Text: plainText(l.Content)})
+			for i, ex := range l.CodeExamples {
+				docs = append(docs, search.Doc{
+					LessonID: l.ID, Section: fmt.Sprintf("example-%d", i+1), Title: ex.Title, Code: ex.Code,
+				})
+			}
+			if ex := l.Exercise; ex != nil {
+				docs = append(docs, search.Doc{
+					LessonID: l.ID, Section: "exercise", Title: ex.Title,
+					Text: plainText(ex.Description), Code: ex.StarterCode,
+				})
+			}
+			if len(l.Notes) > 0 {
+				docs = append(docs, search.Doc{LessonID: l.ID, Section: "notes", Text: plainText(strings.Join(l.Notes, "\n"))})
+			}
+			if q, ok := s.quizzes[l.ID]; ok {
+				var text, code []string
+				for _, question := range q.Questions {
+					text = append(append(text, question.Text), question.Options...)
+					code = append(code, question.Code)
+				}
+				docs = append(docs, search.Doc{
+					LessonID: l.ID, Section: "quiz",
+					Text: plainText(strings.Join(text, "\n")), Code: strings.Join(code, "\n"),
+				})
+			}
+		}
+	}
+	s.index = search.New(docs)
+}
+
+// Search returns up to limit lesson sections matching every word of the
+// query, best first.
+func (s *Store) Search(query string, limit int) []search.Result {
+	return s.index.Search(query, limit)
+}
+
+// plainText strips the HTML markup lesson text may contain.
+func plainText(s string) string {
+	return html.UnescapeString(tagPattern.ReplaceAllString(s, ""))
+}
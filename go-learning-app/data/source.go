@@ -0,0 +1,33 @@
+package data
+
+import (
+	"io/fs"
+)
+
+// ContentSource provides the courses in the file layout LoadCatalog reads,
+// wherever they are kept.
+type ContentSource interface {
+	// Files returns the content files, or ErrNotFound if the source holds
+	// no content.
+	Files() (fs.FS, error)
+}
+
+// FSSource is a ContentSource for a file system, such as a content
+// directory or the content built into the binary.
+type FSSource struct {
+	FS fs.FS
+}
+
+// Files returns the file system.
+func (s FSSource) Files() (fs.FS, error) {
+	return s.FS, nil
+}
+
+// Load reads the courses from src; see LoadCatalog.
+func Load(src ContentSource) (*Catalog, error) {
+	fsys, err := src.Files()
+	if err != nil {
+		return nil, err
+	}
+	return LoadCatalog(fsys)
+}
@@ -0,0 +1,460 @@
+package data
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go-learning-app/models"
+)
+
+// DefaultLocale is the locale the content is written in. Other locales are
+// translations that fall back to it for anything they leave out.
+const DefaultLocale = "ja"
+
+// Translations sit next to the files they translate, with the locale
+// before the extension:
+//
+//	course.en.yaml           course title and description
+//	chapter01/
+//	    chapter.en.yaml      title and description
+//	    1-1.en.md            title, example titles, exercise text, notes
+//	                         and the Markdown body
+//	    1-1.en.quiz.yaml     question text, options and explanations
+//
+// Every field is optional and falls back to the Japanese text. Answers,
+// code and file references always come from the Japanese files.
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// chapterTranslation also translates a course, which has the same
+// translatable fields.
+type chapterTranslation struct {
+	Title       string `yaml:"title,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type lessonTranslation struct {
+	ID       string               `yaml:"id"`
+	Title    string               `yaml:"title,omitempty"`
+	Examples []exampleTranslation `yaml:"examples,omitempty"`
+	Exercise *exerciseTranslation `yaml:"exercise,omitempty"`
+	Notes    []string             `yaml:"notes,omitempty"`
+}
+
+type exampleTranslation struct {
+	Title string `yaml:"title,omitempty"`
+}
+
+type exerciseTranslation struct {
+	Title       string `yaml:"title,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type quizTranslation struct {
+	Questions []questionTranslation `yaml:"questions"`
+}
+
+type questionTranslation struct {
+	ID          string   `yaml:"id"`
+	Text        string   `yaml:"text,omitempty"`
+	Options     []string `yaml:"options,omitempty"`
+	Explanation string   `yaml:"explanation,omitempty"`
+	// Accepted and Pattern replace the Japanese ones when answers in the
+	// locale are written differently.
+	Accepted []string `yaml:"accepted,omitempty"`
+	Pattern  string   `yaml:"pattern,omitempty"`
+}
+
+// Coverage is how much of the content a locale translates, counted in
+// translatable strings: the course and chapter titles and descriptions,
+// lesson titles and
+// bodies, example titles, exercise texts, notes, and question texts,
+// options and explanations.
+type Coverage struct {
+	Locale     string `json:"locale"`
+	Translated int    `json:"translated"`
+	Total      int    `json:"total"`
+	// Lessons lists the lessons whose strings, including their quiz's,
+	// are all translated.
+	Lessons []string `json:"lessons"`
+}
+
+// Percent returns the share of translated strings, rounded down.
+func (c Coverage) Percent() int {
+	if c.Total == 0 {
+		return 100
+	}
+	return c.Translated * 100 / c.Total
+}
+
+// Localized returns the store with text in the given locale, falling back
+// to the Japanese text for anything not translated. An unknown locale gets
+// the Japanese store.
+func (s *Store) Localized(locale string) *Store {
+	if t, ok := s.translations[locale]; ok {
+		return t
+	}
+	return s
+}
+
+// Locales returns the locales the content is available in, the default
+// locale first.
+func (s *Store) Locales() []string {
+	locales := []string{DefaultLocale}
+	for loc := range s.translations {
+		if loc != DefaultLocale {
+			locales = append(locales, loc)
+		}
+	}
+	slices.Sort(locales[1:])
+	return locales
+}
+
+// Coverage returns the translation coverage of every translated locale.
+func (s *Store) Coverage() []Coverage {
+	return s.coverage
+}
+
+// translationSource is a translation file as read, kept so ExportContent
+// can write it back unchanged.
+type translationSource struct {
+	chapterID int
+	name      string
+	raw       []byte
+}
+
+// translationFile is a translation found while loading a chapter.
+type translationFile struct {
+	locale    string
+	chapterID int
+	kind      string // courseFile, chapterFile, lessonExt or quizExt
+	id        string // lesson ID for lessons and quizzes
+	path      string
+}
+
+// findTranslations lists the translation files in a chapter directory, or
+// in the course directory "." with chapter ID 0.
+func (l *loader) findTranslations(dir string, chapterID int) {
+	entries, err := fs.ReadDir(l.fsys, dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		name := e.Name()
+		var kind, stem string
+		switch {
+		case strings.HasSuffix(name, quizExt):
+			kind, stem = quizExt, strings.TrimSuffix(name, quizExt)
+		case strings.HasSuffix(name, lessonExt):
+			kind, stem = lessonExt, strings.TrimSuffix(name, lessonExt)
+		case strings.HasPrefix(name, "chapter.") && strings.HasSuffix(name, ".yaml") && name != chapterFile:
+			kind, stem = chapterFile, strings.TrimSuffix(name, ".yaml")
+		case strings.HasPrefix(name, "course.") && strings.HasSuffix(name, ".yaml") && name != courseFile:
+			kind, stem = courseFile, strings.TrimSuffix(name, ".yaml")
+		default:
+			continue
+		}
+		id, locale, ok := strings.Cut(stem, ".")
+		if !ok {
+			continue // a Japanese file
+		}
+		file := path.Join(dir, name)
+		if !localePattern.MatchString(locale) || locale == DefaultLocale {
+			l.errorf(file, 0, "invalid translation locale %q", locale)
+			continue
+		}
+		l.translationFiles = append(l.translationFiles, translationFile{
+			locale: locale, chapterID: chapterID, kind: kind, id: id, path: file,
+		})
+	}
+}
+
+// loadTranslations builds a localized store per locale found, once the
+// Japanese content has loaded without problems.
+func (l *loader) loadTranslations() {
+	base := l.store
+	base.Locale = DefaultLocale
+	base.translations = map[string]*Store{DefaultLocale: base}
+
+	byLocale := make(map[string][]translationFile)
+	for _, tf := range l.translationFiles {
+		byLocale[tf.locale] = append(byLocale[tf.locale], tf)
+	}
+	for _, locale := range slices.Sorted(maps.Keys(byLocale)) {
+		files := byLocale[locale]
+		t := base.clone(locale)
+		tl := &translator{loader: l, store: t, done: make(map[string]int)}
+		for _, tf := range files {
+			tl.apply(tf)
+		}
+		loadExams(t)
+		t.translations = base.translations
+		base.translations[locale] = t
+		base.coverage = append(base.coverage, tl.coverage())
+	}
+}
+
+// clone copies the store for a translation. Chapters, lessons and quizzes
+// are copied so they can be changed; slices inside them are replaced, never
+// modified, when a translation applies.
+func (s *Store) clone(locale string) *Store {
+	t := &Store{
+		Locale:          locale,
+		Course:          s.Course,
+		Chapters:        slices.Clone(s.Chapters),
+		lessons:         make(map[string]models.Lesson, len(s.lessons)),
+		quizzes:         make(map[string]models.Quiz, len(s.quizzes)),
+		questionLessons: s.questionLessons,
+		glossary:        slices.Clone(s.glossary),
+	}
+	for i := range t.Chapters {
+		t.Chapters[i].Lessons = slices.Clone(t.Chapters[i].Lessons)
+	}
+	for id, lesson := range s.lessons {
+		t.lessons[id] = lesson
+	}
+	for id, quiz := range s.quizzes {
+		quiz.Questions = slices.Clone(quiz.Questions)
+		t.quizzes[id] = quiz
+	}
+	return t
+}
+
+// translator applies the translation files of one locale to its store and
+// counts what they translate.
+type translator struct {
+	*loader
+	store *Store
+	// done counts translated strings per lesson ID; chapter strings are
+	// counted under "".
+	done map[string]int
+}
+
+func (tl *translator) apply(tf translationFile) {
+	raw, err := fs.ReadFile(tl.fsys, tf.path)
+	if err != nil {
+		tl.errorf(tf.path, 0, "%v", err)
+		return
+	}
+	base := tl.base()
+	base.translationSources = append(base.translationSources, translationSource{
+		chapterID: tf.chapterID, name: path.Base(tf.path), raw: raw,
+	})
+	switch tf.kind {
+	case courseFile:
+		tl.applyCourse(tf, raw)
+	case chapterFile:
+		tl.applyChapter(tf, raw)
+	case lessonExt:
+		tl.applyLesson(tf, raw)
+	case quizExt:
+		tl.applyQuiz(tf, raw)
+	}
+}
+
+func (tl *translator) applyCourse(tf translationFile, raw []byte) {
+	var doc chapterTranslation
+	if _, ok := tl.decode(tf.path, raw, 0, &doc); !ok {
+		return
+	}
+	tl.set(&tl.store.Course.Title, doc.Title, "")
+	tl.set(&tl.store.Course.Description, doc.Description, "")
+}
+
+func (tl *translator) applyChapter(tf translationFile, raw []byte) {
+	var doc chapterTranslation
+	if _, ok := tl.decode(tf.path, raw, 0, &doc); !ok {
+		return
+	}
+	i := slices.IndexFunc(tl.store.Chapters, func(ch models.Chapter) bool { return ch.ID == tf.chapterID })
+	ch := &tl.store.Chapters[i]
+	tl.set(&ch.Title, doc.Title, "")
+	tl.set(&ch.Description, doc.Description, "")
+}
+
+func (tl *translator) applyLesson(tf translationFile, raw []byte) {
+	lesson, ok := tl.store.lessons[tf.id]
+	if !ok || lesson.ChapterID != tf.chapterID {
+		tl.errorf(tf.path, 0, "translates lesson %q, which is not in this chapter", tf.id)
+		return
+	}
+	front, body, _, ok := splitFrontMatter(string(raw))
+	if !ok {
+		tl.errorf(tf.path, 1, "lesson must start with a %q front matter block", frontMatterLine)
+		return
+	}
+	var doc lessonTranslation
+	root, ok := tl.decode(tf.path, []byte(front), 1, &doc)
+	if !ok {
+		return
+	}
+	line := func(p ...any) int { return lineOf(root, p...) + 1 }
+	if doc.ID != tf.id {
+		tl.errorf(tf.path, line("id"), "id %q does not match file name %q", doc.ID, tf.id)
+		return
+	}
+
+	tl.set(&lesson.Title, doc.Title, lesson.ID)
+	tl.set(&lesson.Content, strings.TrimSpace(body), lesson.ID)
+	if len(doc.Examples) > len(lesson.CodeExamples) {
+		tl.errorf(tf.path, line("examples"), "%d example titles for %d examples", len(doc.Examples), len(lesson.CodeExamples))
+		return
+	}
+	if len(doc.Examples) > 0 {
+		lesson.CodeExamples = slices.Clone(lesson.CodeExamples)
+		for i, ex := range doc.Examples {
+			tl.set(&lesson.CodeExamples[i].Title, ex.Title, lesson.ID)
+		}
+	}
+	if doc.Exercise != nil {
+		if lesson.Exercise == nil {
+			tl.errorf(tf.path, line("exercise"), "lesson %s has no exercise", lesson.ID)
+			return
+		}
+		ex := *lesson.Exercise
+		tl.set(&ex.Title, doc.Exercise.Title, lesson.ID)
+		tl.set(&ex.Description, doc.Exercise.Description, lesson.ID)
+		lesson.Exercise = &ex
+	}
+	if doc.Notes != nil {
+		// Notes are review flashcards identified by position, so a
+		// translation must keep them one to one.
+		if len(doc.Notes) != len(lesson.Notes) {
+			tl.errorf(tf.path, line("notes"), "%d notes for %d notes in Japanese", len(doc.Notes), len(lesson.Notes))
+			return
+		}
+		lesson.Notes = slices.Clone(lesson.Notes)
+		for i, note := range doc.Notes {
+			tl.set(&lesson.Notes[i], note, lesson.ID)
+		}
+	}
+	tl.store.lessons[lesson.ID] = lesson
+
+	for i := range tl.store.Chapters {
+		for j, summary := range tl.store.Chapters[i].Lessons {
+			if summary.ID == lesson.ID {
+				tl.store.Chapters[i].Lessons[j].Title = lesson.Title
+			}
+		}
+	}
+}
+
+func (tl *translator) applyQuiz(tf translationFile, raw []byte) {
+	quiz, ok := tl.store.quizzes[tf.id]
+	if lesson := tl.store.lessons[tf.id]; !ok || lesson.ChapterID != tf.chapterID {
+		tl.errorf(tf.path, 0, "translates the quiz of lesson %q, which has none in this chapter", tf.id)
+		return
+	}
+	var doc quizTranslation
+	root, ok := tl.decode(tf.path, raw, 0, &doc)
+	if !ok {
+		return
+	}
+	for i, qt := range doc.Questions {
+		line := func(p ...any) int { return lineOf(root, append([]any{"questions", i}, p...)...) }
+		j := slices.IndexFunc(quiz.Questions, func(q models.Question) bool { return q.ID == qt.ID })
+		if j < 0 {
+			tl.errorf(tf.path, line("id"), "question %q is not in the quiz", qt.ID)
+			continue
+		}
+		q := quiz.Questions[j]
+		if qt.Options != nil && len(qt.Options) != len(q.Options) {
+			tl.errorf(tf.path, line("options"), "question %s: %d options for %d in Japanese", q.ID, len(qt.Options), len(q.Options))
+			continue
+		}
+		tl.set(&q.Text, qt.Text, tf.id)
+		tl.set(&q.Explanation, qt.Explanation, tf.id)
+		if qt.Options != nil {
+			q.Options = slices.Clone(q.Options)
+			for k, opt := range qt.Options {
+				tl.set(&q.Options[k], opt, tf.id)
+			}
+		}
+		if qt.Accepted != nil {
+			q.Accepted = qt.Accepted
+		}
+		if qt.Pattern != "" {
+			q.Pattern = qt.Pattern
+		}
+		if field, msg := CheckQuestion(q); msg != "" {
+			tl.errorf(tf.path, line(field), "question %s: %s", q.ID, msg)
+			continue
+		}
+		quiz.Questions[j] = q
+	}
+	tl.store.quizzes[tf.id] = quiz
+}
+
+// set replaces *dst with a non-empty translation and counts it.
+func (tl *translator) set(dst *string, translation, lessonID string) {
+	if translation == "" {
+		return
+	}
+	*dst = translation
+	tl.done[lessonID]++
+}
+
+// coverage compares the strings translated with those in the Japanese
+// content.
+func (tl *translator) coverage() Coverage {
+	c := Coverage{Locale: tl.store.Locale, Lessons: []string{}}
+	c.Total++
+	if tl.base().Course.Description != "" {
+		c.Total++
+	}
+	for _, ch := range tl.base().Chapters {
+		c.Total++
+		if ch.Description != "" {
+			c.Total++
+		}
+		for _, summary := range ch.Lessons {
+			total := tl.base().lessonStrings(summary.ID)
+			c.Total += total
+			done := min(tl.done[summary.ID], total)
+			c.Translated += done
+			if done == total {
+				c.Lessons = append(c.Lessons, summary.ID)
+			}
+		}
+	}
+	c.Translated += tl.done[""]
+	return c
+}
+
+func (tl *translator) base() *Store {
+	return tl.loader.store
+}
+
+// lessonStrings counts the non-empty translatable strings of a lesson and
+// its quiz.
+func (s *Store) lessonStrings(id string) int {
+	lesson := s.lessons[id]
+	strs := []string{lesson.Title, lesson.Content}
+	for _, ex := range lesson.CodeExamples {
+		strs = append(strs, ex.Title)
+	}
+	if ex := lesson.Exercise; ex != nil {
+		strs = append(strs, ex.Title, ex.Description)
+	}
+	strs = append(strs, lesson.Notes...)
+	for _, q := range s.quizzes[id].Questions {
+		strs = append(append(strs, q.Text, q.Explanation), q.Options...)
+	}
+	n := 0
+	for _, str := range strs {
+		if str != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// String formats the coverage for reports, e.g. "en: 12% (140/1150
+// strings)".
+func (c Coverage) String() string {
+	return fmt.Sprintf("%s: %d%% (%d/%d strings)", c.Locale, c.Percent(), c.Translated, c.Total)
+}
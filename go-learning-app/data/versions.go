@@ -0,0 +1,121 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"go-learning-app/models"
+)
+
+// versionLen is the number of hex digits of a content hash kept as a
+// version; enough to tell the revisions of one lesson apart.
+const versionLen = 12
+
+// stampVersions sets the version of every lesson and quiz from its content.
+func (s *Store) stampVersions() {
+	for id, l := range s.lessons {
+		l.Version = lessonVersion(l)
+		s.lessons[id] = l
+	}
+	for id, q := range s.quizzes {
+		q.Version = contentHash(q.Questions)
+		s.quizzes[id] = q
+	}
+}
+
+// lessonVersion hashes what a learner reads and runs in a lesson. The
+// completion rule, prerequisites and changelog are left out: changing them
+// does not change the material.
+func lessonVersion(l models.Lesson) string {
+	type example struct{ Title, Code string }
+	v := struct {
+		Title, Content string
+		Examples       []example
+		Notes          []string
+		Exercise       *models.Exercise
+	}{Title: l.Title, Content: l.Content, Notes: l.Notes, Exercise: l.Exercise}
+	for _, ex := range l.CodeExamples {
+		v.Examples = append(v.Examples, example{ex.Title, ex.Code})
+	}
+	if ex := l.Exercise; ex != nil {
+		// The file the starter was loaded from is not content.
+		v.Exercise = &models.Exercise{
+			Title:          ex.Title,
+			Description:    ex.Description,
+			StarterCode:    ex.StarterCode,
+			ExpectedOutput: ex.ExpectedOutput,
+		}
+	}
+	return contentHash(v)
+}
+
+func contentHash(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err) // only plain structs and slices are hashed
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:versionLen]
+}
+
+// Versions returns the current version of every lesson and quiz, keyed by
+// lesson ID.
+func (s *Store) Versions() (lessons, quizzes map[string]string) {
+	lessons = make(map[string]string, len(s.lessons))
+	for id, l := range s.lessons {
+		lessons[id] = l.Version
+	}
+	quizzes = make(map[string]string, len(s.quizzes))
+	for id, q := range s.quizzes {
+		quizzes[id] = q.Version
+	}
+	return lessons, quizzes
+}
+
+// changed reports whether the lesson or its quiz has changed since the
+// versions recorded in p. Progress without a recorded version predates
+// versioning and is not reported.
+func (s *Store) changed(p models.LessonProgress) (lesson, quiz bool) {
+	if l, ok := s.lessons[p.LessonID]; ok && p.LessonVersion != "" {
+		lesson = p.LessonVersion != l.Version
+	}
+	if q, ok := s.quizzes[p.LessonID]; ok && p.QuizVersion != "" {
+		quiz = p.QuizVersion != q.Version
+	}
+	return lesson, quiz
+}
+
+// UpdatedLessons returns the completed lessons that changed since the
+// learner completed them, in course order, given their resolved progress.
+// Each carries the changelog entries made since.
+func (s *Store) UpdatedLessons(lessons []models.LessonProgress) []models.LessonUpdate {
+	updates := []models.LessonUpdate{}
+	for _, p := range lessons {
+		if !p.Completed {
+			continue
+		}
+		lessonChanged, quizChanged := s.changed(p)
+		if !lessonChanged && !quizChanged {
+			continue
+		}
+		l := s.lessons[p.LessonID]
+		u := models.LessonUpdate{
+			LessonID:      l.ID,
+			ChapterID:     l.ChapterID,
+			Title:         l.Title,
+			LessonChanged: lessonChanged,
+			QuizChanged:   quizChanged,
+			Changes:       []models.ChangelogEntry{},
+		}
+		since := p.VersionAt.UTC().Format(time.DateOnly)
+		for _, c := range l.Changelog {
+			if p.VersionAt.IsZero() || c.Date >= since {
+				u.Changes = append(u.Changes, c)
+			}
+		}
+		updates = append(updates, u)
+	}
+	return updates
+}
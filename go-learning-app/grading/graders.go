@@ -0,0 +1,269 @@
+package grading
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"go-learning-app/models"
+	"go-learning-app/runner"
+)
+
+// A Grader checks answers to one type of question.
+type Grader interface {
+	// Grade reports whether answer, the submitted JSON value, is correct.
+	Grade(ctx context.Context, q models.Question, answer json.RawMessage) (bool, error)
+	// Key returns the correct answer that is revealed after grading.
+	Key(ctx context.Context, q models.Question) (any, error)
+}
+
+// partialGrader is implemented by graders that can award part of a
+// question's points when the scoring policy uses negative marking.
+type partialGrader interface {
+	// Credit returns the share of the question earned, from 0 to 1.
+	Credit(q models.Question, answer json.RawMessage) float64
+}
+
+var graders = map[string]Grader{
+	models.QuestionSingle: singleGrader{},
+	models.QuestionMulti:  multiGrader{},
+	models.QuestionOutput: outputGrader{},
+	models.QuestionFill:   fillGrader{},
+	models.QuestionOrder:  orderGrader{},
+	models.QuestionShort:  shortGrader{},
+}
+
+// singleGrader expects the index of the chosen option.
+type singleGrader struct{}
+
+func (singleGrader) Grade(_ context.Context, q models.Question, answer json.RawMessage) (bool, error) {
+	var selected int
+	if err := json.Unmarshal(answer, &selected); err != nil {
+		return false, nil
+	}
+	return selected == q.Answer, nil
+}
+
+func (singleGrader) Key(_ context.Context, q models.Question) (any, error) {
+	return q.Answer, nil
+}
+
+// multiGrader expects the indexes of every chosen option, in any order.
+type multiGrader struct{}
+
+func (multiGrader) Grade(_ context.Context, q models.Question, answer json.RawMessage) (bool, error) {
+	var selected []int
+	if err := json.Unmarshal(answer, &selected); err != nil {
+		return false, nil
+	}
+	slices.Sort(selected)
+	selected = slices.Compact(selected)
+	want := slices.Sorted(slices.Values(q.Answers))
+	return slices.Equal(selected, want), nil
+}
+
+func (multiGrader) Key(_ context.Context, q models.Question) (any, error) {
+	return q.Answers, nil
+}
+
+// Credit gives a share of the question for each correct option chosen and
+// takes a share away for each wrong one, never going below zero.
+func (multiGrader) Credit(q models.Question, answer json.RawMessage) float64 {
+	var selected []int
+	if err := json.Unmarshal(answer, &selected); err != nil || len(q.Answers) == 0 {
+		return 0
+	}
+	slices.Sort(selected)
+	selected = slices.Compact(selected)
+
+	hits, misses := 0, 0
+	for _, i := range selected {
+		if slices.Contains(q.Answers, i) {
+			hits++
+		} else {
+			misses++
+		}
+	}
+	credit := float64(hits) / float64(len(q.Answers))
+	if wrong := len(q.Options) - len(q.Answers); wrong > 0 {
+		credit -= float64(misses) / float64(wrong)
+	} else if misses > 0 {
+		credit = 0
+	}
+	return max(credit, 0)
+}
+
+// outputGrader expects the text the snippet prints. The expected output is
+// obtained by actually running the snippet.
+type outputGrader struct{}
+
+func (outputGrader) Grade(ctx context.Context, q models.Question, answer json.RawMessage) (bool, error) {
+	var text string
+	if err := json.Unmarshal(answer, &text); err != nil {
+		return false, nil
+	}
+	want, err := snippetOutput(ctx, q.Code)
+	if err != nil {
+		return false, err
+	}
+	return normalizeOutput(text) == want, nil
+}
+
+func (outputGrader) Key(ctx context.Context, q models.Question) (any, error) {
+	return snippetOutput(ctx, q.Code)
+}
+
+// fillGrader expects the text for the blank. Runs of whitespace are
+// insignificant.
+type fillGrader struct{}
+
+func (fillGrader) Grade(_ context.Context, q models.Question, answer json.RawMessage) (bool, error) {
+	var text string
+	if err := json.Unmarshal(answer, &text); err != nil {
+		return false, nil
+	}
+	text = collapseSpace(text)
+	for _, accepted := range q.Accepted {
+		if text == collapseSpace(accepted) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (fillGrader) Key(_ context.Context, q models.Question) (any, error) {
+	if len(q.Accepted) == 0 {
+		return "", nil
+	}
+	return q.Accepted[0], nil
+}
+
+// orderGrader expects the lines in the submitted order. Besides the keyed
+// order, any rearrangement of the same lines that builds and prints the same
+// output as the keyed program is accepted.
+type orderGrader struct{}
+
+func (orderGrader) Grade(ctx context.Context, q models.Question, answer json.RawMessage) (bool, error) {
+	var lines []string
+	if err := json.Unmarshal(answer, &lines); err != nil {
+		return false, nil
+	}
+
+	trim := func(ls []string) []string {
+		out := make([]string, len(ls))
+		for i, l := range ls {
+			out[i] = strings.TrimSpace(l)
+		}
+		return out
+	}
+	got, want := trim(lines), trim(q.Lines)
+	if slices.Equal(got, want) {
+		return true, nil
+	}
+	// Only a permutation of the given lines may be run.
+	if !slices.Equal(slices.Sorted(slices.Values(got)), slices.Sorted(slices.Values(want))) {
+		return false, nil
+	}
+
+	wantOut, err := snippetOutput(ctx, orderProgram(q.Code, q.Lines))
+	if err != nil {
+		return false, err
+	}
+	res, err := runner.Run(ctx, orderProgram(q.Code, lines))
+	if errors.Is(err, runner.ErrTimeout) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !res.Failed && normalizeOutput(res.Output) == wantOut, nil
+}
+
+func (orderGrader) Key(_ context.Context, q models.Question) (any, error) {
+	return q.Lines, nil
+}
+
+func orderProgram(prefix string, lines []string) string {
+	body := strings.Join(lines, "\n")
+	if prefix == "" {
+		return body
+	}
+	return prefix + "\n" + body
+}
+
+// shortGrader matches the trimmed answer against Pattern. Accepted, if set,
+// holds a model answer to reveal after grading.
+type shortGrader struct{}
+
+func (shortGrader) Grade(_ context.Context, q models.Question, answer json.RawMessage) (bool, error) {
+	var text string
+	if err := json.Unmarshal(answer, &text); err != nil {
+		return false, nil
+	}
+	re, err := compilePattern(q.Pattern)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(strings.TrimSpace(text)), nil
+}
+
+func (shortGrader) Key(_ context.Context, q models.Question) (any, error) {
+	if len(q.Accepted) > 0 {
+		return q.Accepted[0], nil
+	}
+	return q.Pattern, nil
+}
+
+var (
+	outputs  sync.Map // snippet code -> normalized output
+	patterns sync.Map // pattern -> *regexp.Regexp
+)
+
+// snippetOutput runs code once and caches its normalized output.
+func snippetOutput(ctx context.Context, code string) (string, error) {
+	if out, ok := outputs.Load(code); ok {
+		return out.(string), nil
+	}
+	res, err := runner.Run(ctx, code)
+	if err != nil {
+		return "", fmt.Errorf("run snippet: %w", err)
+	}
+	if res.Failed {
+		return "", fmt.Errorf("snippet does not run: %s", strings.TrimSpace(res.Output))
+	}
+	out := normalizeOutput(res.Output)
+	outputs.Store(code, out)
+	return out, nil
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compile pattern: %w", err)
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// normalizeOutput ignores line-ending style, trailing spaces on each line
+// and trailing blank lines.
+func normalizeOutput(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
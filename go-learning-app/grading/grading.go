@@ -0,0 +1,122 @@
+package grading
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"go-learning-app/models"
+)
+
+// Result is the outcome of grading one quiz submission. Percent is Points
+// as a share of MaxPoints, so question weights and partial credit count.
+type Result struct {
+	Correct     int                  `json:"correct"`
+	Total       int                  `json:"total"`
+	Points      float64              `json:"points"`
+	MaxPoints   int                  `json:"maxPoints"`
+	Percent     int                  `json:"percent"`
+	Passed      bool                 `json:"passed"`
+	PassPercent int                  `json:"passPercent"`
+	Policy      models.ScoringPolicy `json:"policy"`
+	Questions   []QuestionResult     `json:"questions"`
+}
+
+// QuestionResult reveals the answer and explanation for one question.
+// Selected echoes the submitted answer ("null" when unanswered) and Answer
+// is the correct answer in the shape the question type expects.
+type QuestionResult struct {
+	QuestionID  string          `json:"questionId"`
+	Selected    json.RawMessage `json:"selected"`
+	Answer      any             `json:"answer"`
+	Correct     bool            `json:"correct"`
+	Points      float64         `json:"points"`
+	MaxPoints   int             `json:"maxPoints"`
+	Explanation string          `json:"explanation"`
+}
+
+// PolicyOf returns the scoring policy of quiz, or the default if it has none.
+func PolicyOf(quiz models.Quiz) models.ScoringPolicy {
+	if quiz.Policy == nil {
+		return models.DefaultScoringPolicy
+	}
+	return quiz.Policy.Inherit(models.DefaultScoringPolicy)
+}
+
+// Key returns the correct answer to a question in the shape Grade reveals
+// it. The code of output questions is run to find theirs.
+func Key(ctx context.Context, q models.Question) (any, error) {
+	g, ok := graders[q.Kind()]
+	if !ok {
+		return nil, fmt.Errorf("question %s: unknown type %q", q.ID, q.Type)
+	}
+	return g.Key(ctx, q)
+}
+
+// Grade scores answers (question ID to submitted JSON value) against quiz
+// under the quiz's scoring policy. Unanswered or malformed answers count as
+// wrong. An error means a question could not be graded at all, for example
+// because its snippet failed to run.
+func Grade(ctx context.Context, quiz models.Quiz, answers map[string]json.RawMessage) (Result, error) {
+	policy := PolicyOf(quiz)
+	res := Result{
+		Total:       len(quiz.Questions),
+		PassPercent: policy.PassPercent,
+		Policy:      policy,
+	}
+
+	for _, q := range quiz.Questions {
+		g, ok := graders[q.Kind()]
+		if !ok {
+			return Result{}, fmt.Errorf("question %s: unknown type %q", q.ID, q.Type)
+		}
+
+		selected, ok := answers[q.ID]
+		if !ok || len(selected) == 0 {
+			selected = json.RawMessage("null")
+		}
+		// null would decode to the zero value, which can be a valid answer.
+		correct := false
+		if string(selected) != "null" {
+			var err error
+			if correct, err = g.Grade(ctx, q, selected); err != nil {
+				return Result{}, fmt.Errorf("grade %s: %w", q.ID, err)
+			}
+		}
+		key, err := g.Key(ctx, q)
+		if err != nil {
+			return Result{}, fmt.Errorf("answer key %s: %w", q.ID, err)
+		}
+
+		credit := 0.0
+		switch pg, ok := g.(partialGrader); {
+		case correct:
+			credit = 1
+		case ok && policy.NegativeMarks() && string(selected) != "null":
+			credit = pg.Credit(q, selected)
+		}
+
+		if correct {
+			res.Correct++
+		}
+		points := credit * float64(q.Points())
+		res.Points += points
+		res.MaxPoints += q.Points()
+		res.Questions = append(res.Questions, QuestionResult{
+			QuestionID:  q.ID,
+			Selected:    selected,
+			Answer:      key,
+			Correct:     correct,
+			Points:      points,
+			MaxPoints:   q.Points(),
+			Explanation: q.Explanation,
+		})
+	}
+
+	if res.MaxPoints > 0 {
+		res.Percent = int(math.Round(res.Points * 100 / float64(res.MaxPoints)))
+	}
+	res.Passed = res.Percent >= policy.PassPercent
+	return res, nil
+}
@@ -0,0 +1,260 @@
+package grading
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"slices"
+	"testing"
+
+	"go-learning-app/models"
+)
+
+const helloProgram = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+	fmt.Println("world")
+}`
+
+func needGo(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+}
+
+func TestGraders(t *testing.T) {
+	single := models.Question{ID: "q", Options: []string{"a", "b", "c"}, Answer: 1}
+	multi := models.Question{ID: "q", Type: models.QuestionMulti, Options: []string{"a", "b", "c", "d"}, Answers: []int{0, 2}}
+	fill := models.Question{ID: "q", Type: models.QuestionFill, Code: "x := ___", Accepted: []string{"len(s)", "len( s )"}}
+	short := models.Question{ID: "q", Type: models.QuestionShort, Pattern: `^(?i)goroutine$`}
+
+	tests := []struct {
+		name   string
+		q      models.Question
+		answer string
+		want   bool
+	}{
+		{"single correct", single, `1`, true},
+		{"single wrong", single, `0`, false},
+		{"single malformed", single, `"1"`, false},
+		{"multi correct", multi, `[0, 2]`, true},
+		{"multi any order and repeats", multi, `[2, 0, 2]`, true},
+		{"multi missing option", multi, `[0]`, false},
+		{"multi extra option", multi, `[0, 1, 2]`, false},
+		{"multi malformed", multi, `0`, false},
+		{"fill exact", fill, `"len(s)"`, true},
+		{"fill collapses spaces", fill, `"  len(s) "`, true},
+		{"fill second accepted", fill, `"len(  s  )"`, true},
+		{"fill wrong", fill, `"cap(s)"`, false},
+		{"short matches trimmed", short, `" Goroutine\n"`, true},
+		{"short no match", short, `"thread"`, false},
+		{"short malformed", short, `1`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := graders[tt.q.Kind()].Grade(context.Background(), tt.q, json.RawMessage(tt.answer))
+			if err != nil {
+				t.Fatalf("Grade: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Grade(%s) = %v, want %v", tt.answer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGradersRunningCode(t *testing.T) {
+	needGo(t)
+	output := models.Question{ID: "q", Type: models.QuestionOutput, Code: helloProgram}
+	order := models.Question{
+		ID:   "q",
+		Type: models.QuestionOrder,
+		Code: "package main\n\nimport \"fmt\"\n\nfunc main() {",
+		Lines: []string{
+			`	a := 1`,
+			`	b := 2`,
+			`	fmt.Println(a + b)`,
+			`}`,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		q      models.Question
+		answer string
+		want   bool
+	}{
+		{"output exact", output, `"hello\nworld"`, true},
+		{"output ignores trailing space and CRLF", output, `"hello  \r\nworld\n\n"`, true},
+		{"output wrong", output, `"hello"`, false},
+		{"order keyed", order, `["a := 1", "b := 2", "fmt.Println(a + b)", "}"]`, true},
+		{"order equivalent program", order, `["b := 2", "a := 1", "fmt.Println(a + b)", "}"]`, true},
+		{"order does not build", order, `["fmt.Println(a + b)", "a := 1", "b := 2", "}"]`, false},
+		{"order other lines", order, `["a := 1", "b := 3", "fmt.Println(a + b)", "}"]`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := graders[tt.q.Kind()].Grade(context.Background(), tt.q, json.RawMessage(tt.answer))
+			if err != nil {
+				t.Fatalf("Grade: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Grade(%s) = %v, want %v", tt.answer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name string
+		q    models.Question
+		want string
+	}{
+		{"single", models.Question{Answer: 2}, `2`},
+		{"multi", models.Question{Type: models.QuestionMulti, Answers: []int{1, 3}}, `[1,3]`},
+		{"fill", models.Question{Type: models.QuestionFill, Accepted: []string{"a", "b"}}, `"a"`},
+		{"fill without accepted", models.Question{Type: models.QuestionFill}, `""`},
+		{"order", models.Question{Type: models.QuestionOrder, Lines: []string{"x", "y"}}, `["x","y"]`},
+		{"short model answer", models.Question{Type: models.QuestionShort, Pattern: "^a$", Accepted: []string{"a"}}, `"a"`},
+		{"short pattern", models.Question{Type: models.QuestionShort, Pattern: "^a$"}, `"^a$"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := Key(context.Background(), tt.q)
+			if err != nil {
+				t.Fatalf("Key: %v", err)
+			}
+			got, _ := json.Marshal(key)
+			if string(got) != tt.want {
+				t.Errorf("Key = %s, want %s", got, tt.want)
+			}
+		})
+	}
+	if _, err := Key(context.Background(), models.Question{ID: "q", Type: "essay"}); err == nil {
+		t.Error("Key of an unknown type: no error")
+	}
+}
+
+func TestMultiCredit(t *testing.T) {
+	q := models.Question{Type: models.QuestionMulti, Options: []string{"a", "b", "c", "d"}, Answers: []int{0, 1}}
+	tests := []struct {
+		answer string
+		want   float64
+	}{
+		{`[0, 1]`, 1},
+		{`[0]`, 0.5},
+		{`[0, 2]`, 0},
+		{`[0, 1, 2]`, 0.5},
+		{`[2, 3]`, 0},
+		{`[]`, 0},
+		{`"0"`, 0},
+	}
+	for _, tt := range tests {
+		if got := (multiGrader{}).Credit(q, json.RawMessage(tt.answer)); got != tt.want {
+			t.Errorf("Credit(%s) = %v, want %v", tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestGrade(t *testing.T) {
+	quiz := models.Quiz{
+		Questions: []models.Question{
+			{ID: "q1", Options: []string{"a", "b"}, Answer: 0, Weight: 2},
+			{ID: "q2", Type: models.QuestionMulti, Options: []string{"a", "b", "c", "d"}, Answers: []int{0, 1}},
+			{ID: "q3", Type: models.QuestionFill, Accepted: []string{"x"}},
+		},
+	}
+	on := true
+	negative := quiz
+	negative.Policy = &models.ScoringPolicy{PassPercent: 60, NegativeMarking: &on}
+
+	tests := []struct {
+		name        string
+		quiz        models.Quiz
+		answers     map[string]string
+		correct     int
+		points      float64
+		percent     int
+		passed      bool
+		passPercent int
+	}{
+		{"all correct", quiz, map[string]string{"q1": `0`, "q2": `[0,1]`, "q3": `"x"`}, 3, 4, 100, true, 70},
+		{"unanswered", quiz, map[string]string{}, 0, 0, 0, false, 70},
+		{"null is unanswered", quiz, map[string]string{"q1": `null`}, 0, 0, 0, false, 70},
+		{"weights count", quiz, map[string]string{"q1": `0`, "q3": `"y"`}, 1, 2, 50, false, 70},
+		{"all or nothing without negative marking", quiz, map[string]string{"q1": `0`, "q2": `[0]`, "q3": `"x"`}, 2, 3, 75, true, 70},
+		{"partial credit with negative marking", negative, map[string]string{"q1": `0`, "q2": `[0]`}, 1, 2.5, 63, true, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers := make(map[string]json.RawMessage)
+			for id, a := range tt.answers {
+				answers[id] = json.RawMessage(a)
+			}
+			res, err := Grade(context.Background(), tt.quiz, answers)
+			if err != nil {
+				t.Fatalf("Grade: %v", err)
+			}
+			if res.Correct != tt.correct || res.Points != tt.points || res.Percent != tt.percent ||
+				res.Passed != tt.passed || res.PassPercent != tt.passPercent {
+				t.Errorf("Grade = correct %d, points %v, percent %d, passed %v, pass %d; want %d, %v, %d, %v, %d",
+					res.Correct, res.Points, res.Percent, res.Passed, res.PassPercent,
+					tt.correct, tt.points, tt.percent, tt.passed, tt.passPercent)
+			}
+			if res.Total != 3 || res.MaxPoints != 4 || len(res.Questions) != 3 {
+				t.Errorf("Grade = total %d, max points %d, %d questions; want 3, 4, 3", res.Total, res.MaxPoints, len(res.Questions))
+			}
+		})
+	}
+}
+
+func TestNormalizeOutput(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"a\nb\n", "a\nb"},
+		{"a  \r\nb\t\n\n\n", "a\nb"},
+		{"  a", "  a"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeOutput(tt.in); got != tt.want {
+			t.Errorf("normalizeOutput(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPresent(t *testing.T) {
+	quiz := models.Quiz{
+		Questions: []models.Question{
+			{ID: "single", Options: []string{"a", "b", "c", "d"}, Answer: 2},
+			{ID: "multi", Type: models.QuestionMulti, Options: []string{"a", "b", "c", "d"}, Answers: []int{1, 3}},
+			{ID: "single out of range", Options: []string{"a", "b"}, Answer: 2},
+			{ID: "single negative", Options: []string{"a", "b"}, Answer: -1},
+			{ID: "multi out of range", Type: models.QuestionMulti, Options: []string{"a", "b"}, Answers: []int{0, 4}},
+		},
+	}
+	var ids []string
+	for _, q := range quiz.Questions {
+		ids = append(ids, q.ID)
+	}
+
+	for seed := range uint64(8) {
+		a := Present(quiz, ids, seed)
+		if len(a.Quiz.Questions) != 2 {
+			t.Fatalf("seed %d: presented %d questions, want the 2 with valid keys", seed, len(a.Quiz.Questions))
+		}
+		single, multi := a.Quiz.Questions[0], a.Quiz.Questions[1]
+		if got := single.Options[single.Answer]; got != "c" {
+			t.Errorf("seed %d: single key shows %q, want %q", seed, got, "c")
+		}
+		got := []string{multi.Options[multi.Answers[0]], multi.Options[multi.Answers[1]]}
+		slices.Sort(got)
+		if !slices.Equal(got, []string{"b", "d"}) {
+			t.Errorf("seed %d: multi keys show %q, want [b d]", seed, got)
+		}
+	}
+}
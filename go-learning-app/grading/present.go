@@ -0,0 +1,182 @@
+package grading
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+
+	"go-learning-app/models"
+)
+
+// Draw picks the questions for one attempt. The pool is shuffled by seed and
+// questions in recentlyCorrect are moved to the back, so they are only drawn
+// when the rest of the pool is exhausted. n <= 0 draws every question.
+func Draw(quiz models.Quiz, n int, recentlyCorrect map[string]bool, seed uint64) []string {
+	ids := make([]string, len(quiz.Questions))
+	for i, q := range quiz.Questions {
+		ids[i] = q.ID
+	}
+	ids = shuffled(ids, seed)
+	slices.SortStableFunc(ids, func(a, b string) int {
+		switch {
+		case recentlyCorrect[a] == recentlyCorrect[b]:
+			return 0
+		case recentlyCorrect[a]:
+			return 1
+		default:
+			return -1
+		}
+	})
+	if n > 0 && n < len(ids) {
+		ids = ids[:n]
+	}
+	return ids
+}
+
+// Attempt is a quiz as one attempt presents it: the drawn questions in drawn
+// order, with options shuffled and answer keys remapped to the shuffled
+// order. It is fully determined by the question IDs and seed, so grading
+// can rebuild it from what was stored when the attempt started.
+type Attempt struct {
+	// Quiz is the presented quiz; grade submissions against it.
+	Quiz  models.Quiz
+	seed  uint64
+	perms map[string][]int // question ID -> original index of each shown option
+}
+
+// Present builds the attempt view of quiz for the given questions and seed.
+// Unknown question IDs are skipped, as are questions whose answer key names
+// an option they do not have, since no shuffle could keep that key right.
+func Present(quiz models.Quiz, questionIDs []string, seed uint64) Attempt {
+	a := Attempt{
+		Quiz:  models.Quiz{LessonID: quiz.LessonID, Policy: quiz.Policy, Version: quiz.Version},
+		seed:  seed,
+		perms: make(map[string][]int),
+	}
+	for _, id := range questionIDs {
+		i := slices.IndexFunc(quiz.Questions, func(q models.Question) bool { return q.ID == id })
+		if i < 0 {
+			continue
+		}
+		q := quiz.Questions[i]
+
+		if len(q.Options) > 0 {
+			perm := shuffled(identity(len(q.Options)), a.questionSeed(id))
+			shown := make([]int, len(perm)) // original index -> shown index
+			options := make([]string, len(perm))
+			for to, from := range perm {
+				options[to] = q.Options[from]
+				shown[from] = to
+			}
+			q.Options = options
+			if q.Kind() == models.QuestionSingle {
+				to, ok := shownIndex(shown, q.Answer)
+				if !ok {
+					continue
+				}
+				q.Answer = to
+			}
+			answers := make([]int, len(q.Answers))
+			valid := true
+			for j, ans := range q.Answers {
+				answers[j], valid = shownIndex(shown, ans)
+				if !valid {
+					break
+				}
+			}
+			if !valid {
+				continue
+			}
+			q.Answers = answers
+			a.perms[id] = perm
+		}
+		a.Quiz.Questions = append(a.Quiz.Questions, q)
+	}
+	return a
+}
+
+// shownIndex returns where option i of the authored question is shown, and
+// false if the question has no option i.
+func shownIndex(shown []int, i int) (int, bool) {
+	if i < 0 || i >= len(shown) {
+		return 0, false
+	}
+	return shown[i], true
+}
+
+// Public returns the learner-facing view of the attempt, with answers and
+// explanations removed and the lines of ordering questions shuffled.
+func (a Attempt) Public() models.PublicQuiz {
+	pq := models.PublicQuiz{LessonID: a.Quiz.LessonID, Version: a.Quiz.Version, Policy: PolicyOf(a.Quiz)}
+	for _, q := range a.Quiz.Questions {
+		pub := models.PublicQuestion{
+			ID:      q.ID,
+			Type:    q.Kind(),
+			Text:    q.Text,
+			Options: q.Options,
+			Code:    q.Code,
+			Points:  q.Points(),
+		}
+		if q.Kind() == models.QuestionOrder {
+			pub.Lines = shuffled(q.Lines, a.questionSeed(q.ID))
+		}
+		pq.Questions = append(pq.Questions, pub)
+	}
+	return pq
+}
+
+// Canonical maps a submitted answer from shown option indexes back to the
+// indexes in the authored quiz, so stored answers are comparable across
+// attempts. Answers to questions without options are returned unchanged.
+func (a Attempt) Canonical(questionID string, answer json.RawMessage) json.RawMessage {
+	perm, ok := a.perms[questionID]
+	if !ok || string(answer) == "null" {
+		return answer
+	}
+	original := func(i int) int {
+		if i < 0 || i >= len(perm) {
+			return i
+		}
+		return perm[i]
+	}
+
+	var single int
+	if err := json.Unmarshal(answer, &single); err == nil {
+		out, _ := json.Marshal(original(single))
+		return out
+	}
+	var multi []int
+	if err := json.Unmarshal(answer, &multi); err == nil && multi != nil {
+		for i, v := range multi {
+			multi[i] = original(v)
+		}
+		out, _ := json.Marshal(multi)
+		return out
+	}
+	return answer
+}
+
+// questionSeed mixes the attempt seed with a question ID so each question
+// gets its own shuffle.
+func (a Attempt) questionSeed(id string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return h.Sum64() ^ a.seed
+}
+
+func identity(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
+
+// shuffled returns a copy of items in a pseudo-random order fixed by seed.
+func shuffled[T any](items []T, seed uint64) []T {
+	out := append([]T(nil), items...)
+	r := rand.New(rand.NewPCG(seed, 0))
+	r.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
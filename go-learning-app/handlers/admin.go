@@ -0,0 +1,726 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"go-learning-app/data"
+	"go-learning-app/models"
+)
+
+// errExists is returned by a draft edit that would create something that
+// already exists.
+var errExists = errors.New("already exists")
+
+// adminState is the state of the authoring API; see EnableAdmin.
+type adminState struct {
+	token string
+	// mu serializes edits, each of which rewrites the whole draft.
+	mu sync.Mutex
+	// previewToken and preview cache the loaded draft for preview links.
+	previewMu    sync.Mutex
+	previewToken string
+	preview      *data.Catalog
+}
+
+// EnableAdmin turns on the authoring API under /api/admin. Requests must
+// send the token as "Authorization: Bearer <token>".
+func (h *Handler) EnableAdmin(token string) {
+	h.admin = &adminState{token: token}
+}
+
+// RequireAdmin wraps an authoring handler so it only runs for requests
+// that carry the admin token.
+func (h *Handler) RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.admin == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "admin API is disabled"})
+			return
+		}
+		want := "Bearer " + h.admin.token
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(want)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid admin token"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+// adminCourse is a course as instructors edit it. Chapters is only read
+// when a course is created, since a course needs at least one chapter.
+type adminCourse struct {
+	ID          string         `json:"id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Order       int            `json:"order"`
+	Chapters    []adminChapter `json:"chapters,omitempty"`
+}
+
+func (c adminCourse) model() models.Course {
+	return models.Course{ID: c.ID, Title: c.Title, Description: c.Description, Order: c.Order}
+}
+
+// adminChapter is a chapter as instructors edit it. Lessons is only read
+// when a chapter is created, since a chapter needs at least one lesson.
+type adminChapter struct {
+	ID          int                   `json:"id"`
+	Title       string                `json:"title"`
+	Description string                `json:"description"`
+	Gating      string                `json:"gating,omitempty"`
+	Policy      *models.ScoringPolicy `json:"policy,omitempty"`
+	Lessons     []adminLesson         `json:"lessons,omitempty"`
+}
+
+func (c adminChapter) model() models.Chapter {
+	gating := c.Gating
+	if gating == "" {
+		gating = models.GatingAdvisory
+	}
+	return models.Chapter{ID: c.ID, Title: c.Title, Description: c.Description, Gating: gating, Policy: c.Policy}
+}
+
+// adminLesson is a lesson as instructors edit it, including the fields
+// learners are not sent.
+type adminLesson struct {
+	ID         string                  `json:"id"`
+	ChapterID  int                     `json:"chapterId"`
+	Title      string                  `json:"title"`
+	Content    string                  `json:"content"`
+	Requires   []string                `json:"requires,omitempty"`
+	Completion *models.CompletionRule  `json:"completion,omitempty"`
+	Examples   []adminExample          `json:"examples"`
+	Exercise   *adminExercise          `json:"exercise,omitempty"`
+	Notes      []string                `json:"notes,omitempty"`
+	Changelog  []models.ChangelogEntry `json:"changelog,omitempty"`
+	// Version is computed from the content and ignored on input.
+	Version string `json:"version,omitempty"`
+}
+
+type adminExample struct {
+	Title string `json:"title"`
+	Code  string `json:"code"`
+	// Check is how the validate command checks the example; empty means
+	// the default for its kind of code.
+	Check            string `json:"check,omitempty"`
+	Nondeterministic bool   `json:"nondeterministic,omitempty"`
+	// Output is the recorded output, ignored on input: it is kept while
+	// the code is unchanged.
+	Output string `json:"output,omitempty"`
+}
+
+type adminExercise struct {
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	StarterCode    string `json:"starterCode"`
+	ExpectedOutput string `json:"expectedOutput,omitempty"`
+}
+
+func adminLessonOf(l models.Lesson) adminLesson {
+	a := adminLesson{
+		ID:         l.ID,
+		ChapterID:  l.ChapterID,
+		Title:      l.Title,
+		Content:    l.Content,
+		Requires:   l.Requires,
+		Completion: l.Completion,
+		Examples:   []adminExample{},
+		Notes:      l.Notes,
+		Changelog:  l.Changelog,
+		Version:    l.Version,
+	}
+	for _, ex := range l.CodeExamples {
+		a.Examples = append(a.Examples, adminExample{
+			Title: ex.Title, Code: ex.Code, Check: ex.Check,
+			Nondeterministic: ex.Nondeterministic, Output: ex.Output,
+		})
+	}
+	if ex := l.Exercise; ex != nil {
+		a.Exercise = &adminExercise{
+			Title:          ex.Title,
+			Description:    ex.Description,
+			StarterCode:    ex.StarterCode,
+			ExpectedOutput: ex.ExpectedOutput,
+		}
+	}
+	return a
+}
+
+func (a adminLesson) model() models.Lesson {
+	l := models.Lesson{
+		ID:           a.ID,
+		ChapterID:    a.ChapterID,
+		Title:        a.Title,
+		Content:      a.Content,
+		CodeExamples: []models.CodeExample{},
+		Notes:        a.Notes,
+		Requires:     a.Requires,
+		Completion:   a.Completion,
+		Changelog:    a.Changelog,
+	}
+	for _, ex := range a.Examples {
+		l.CodeExamples = append(l.CodeExamples, models.CodeExample{
+			Title: ex.Title, Code: ex.Code, Check: ex.Check, Nondeterministic: ex.Nondeterministic,
+		})
+	}
+	if ex := a.Exercise; ex != nil {
+		l.Exercise = &models.Exercise{
+			Title:          ex.Title,
+			Description:    ex.Description,
+			StarterCode:    ex.StarterCode,
+			ExpectedOutput: ex.ExpectedOutput,
+		}
+	}
+	return l
+}
+
+// draft returns the draft content, or the served content if there is no
+// draft yet.
+func (h *Handler) draft() (*data.Catalog, error) {
+	c, err := data.Load(h.db.Source(data.StateDraft))
+	if errors.Is(err, data.ErrNotFound) {
+		return h.catalog(), nil
+	}
+	return c, err
+}
+
+// editDraft applies edit to a copy of the draft of the request's course
+// and saves it if the edited course loads. On success it responds with
+// status and the result of respond, given the saved course; problems
+// loading the content are reported with status 422.
+func (h *Handler) editDraft(w http.ResponseWriter, r *http.Request, status int,
+	edit func(current *data.Store, d *data.Draft) error, respond func(saved *data.Store) any) {
+	h.admin.mu.Lock()
+	defer h.admin.mu.Unlock()
+
+	catalog, err := h.draft()
+	if err != nil {
+		log.Printf("load draft: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load draft"})
+		return
+	}
+	current, ok := catalog.Course(r.PathValue("course"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "course not found"})
+		return
+	}
+	d := current.Draft()
+	if err := edit(current, d); err != nil {
+		writeEditError(w, err)
+		return
+	}
+	saved, _, err := d.Check()
+	if err != nil {
+		writeProblems(w, err)
+		return
+	}
+	if h.saveDraft(w, catalog.With(saved)) {
+		writeJSON(w, status, respond(saved))
+	}
+}
+
+// saveDraft saves catalog as the draft, or responds with an error and
+// returns false.
+func (h *Handler) saveDraft(w http.ResponseWriter, catalog *data.Catalog) bool {
+	files, err := catalog.Files()
+	if err == nil {
+		err = h.db.SaveDraft(files)
+	}
+	if err != nil {
+		log.Printf("save draft: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save draft"})
+		return false
+	}
+	h.setPreview(catalog)
+	return true
+}
+
+// writeEditError responds with the error of a draft edit.
+func writeEditError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, data.ErrNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, errExists):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+}
+
+// writeProblems responds with the content errors of an edited draft.
+func writeProblems(w http.ResponseWriter, err error) {
+	problems := []string{}
+	for _, e := range unjoin(err) {
+		problems = append(problems, e.Error())
+	}
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "content is invalid", "problems": problems})
+}
+
+// GetAdminContent returns the courses of the draft with their chapters,
+// the draft and publishing status and the draft's preview link.
+func (h *Handler) GetAdminContent(w http.ResponseWriter, r *http.Request) {
+	current, err := h.draft()
+	if err != nil {
+		log.Printf("load draft: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load draft"})
+		return
+	}
+	status, err := h.db.GetContentStatus()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get content status"})
+		return
+	}
+	type course struct {
+		models.Course
+		Chapters []models.Chapter `json:"chapters"`
+	}
+	courses := []course{}
+	for _, s := range current.Courses() {
+		courses = append(courses, course{Course: s.Course, Chapters: s.GetChapters()})
+	}
+	resp := map[string]any{
+		"courses":     courses,
+		"status":      status,
+		"unpublished": status.Unpublished(),
+	}
+	if status.PreviewToken != "" {
+		resp["previewUrl"] = "/?preview=" + status.PreviewToken
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// CreateCourse creates a course with its first chapters and lessons.
+func (h *Handler) CreateCourse(w http.ResponseWriter, r *http.Request) {
+	var req adminCourse
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	h.admin.mu.Lock()
+	defer h.admin.mu.Unlock()
+
+	catalog, err := h.draft()
+	if err != nil {
+		log.Printf("load draft: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load draft"})
+		return
+	}
+	if _, ok := catalog.Course(req.ID); ok {
+		writeEditError(w, fmt.Errorf("course %s: %w", req.ID, errExists))
+		return
+	}
+	d := data.NewDraft(req.model())
+	for _, ch := range req.Chapters {
+		d.PutChapter(ch.model())
+		for _, l := range ch.Lessons {
+			l.ChapterID = ch.ID
+			if _, err := d.PutLesson(l.model()); err != nil {
+				writeEditError(w, err)
+				return
+			}
+		}
+	}
+	saved, _, err := d.Check()
+	if err != nil {
+		writeProblems(w, err)
+		return
+	}
+	if h.saveDraft(w, catalog.With(saved)) {
+		writeJSON(w, http.StatusCreated, saved.Course)
+	}
+}
+
+// UpdateCourse changes a course's title, description and catalog order.
+func (h *Handler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
+	var req adminCourse
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	h.editDraft(w, r, http.StatusOK, func(_ *data.Store, d *data.Draft) error {
+		d.PutCourse(req.model())
+		return nil
+	}, func(saved *data.Store) any {
+		return saved.Course
+	})
+}
+
+// DeleteCourse deletes a course with all its content. The learners'
+// records of the course are kept, so they come back if a course with the
+// same ID is created again.
+func (h *Handler) DeleteCourse(w http.ResponseWriter, r *http.Request) {
+	h.admin.mu.Lock()
+	defer h.admin.mu.Unlock()
+
+	catalog, err := h.draft()
+	if err != nil {
+		log.Printf("load draft: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load draft"})
+		return
+	}
+	rest, err := catalog.Without(r.PathValue("course"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "course not found"})
+		return
+	}
+	if len(rest.Courses()) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "cannot delete the only course"})
+		return
+	}
+	if h.saveDraft(w, rest) {
+		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	}
+}
+
+// CreateChapter creates a chapter with its first lessons.
+func (h *Handler) CreateChapter(w http.ResponseWriter, r *http.Request) {
+	var req adminChapter
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	h.editDraft(w, r, http.StatusCreated, func(current *data.Store, d *data.Draft) error {
+		if !d.PutChapter(req.model()) {
+			return errExists
+		}
+		for _, l := range req.Lessons {
+			if _, ok := current.GetLesson(l.ID); ok {
+				return fmt.Errorf("lesson %s: %w", l.ID, errExists)
+			}
+			l.ChapterID = req.ID
+			if _, err := d.PutLesson(l.model()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(saved *data.Store) any {
+		return chapterOf(saved, req.ID)
+	})
+}
+
+// UpdateChapter changes a chapter's title, description, gating and
+// scoring policy.
+func (h *Handler) UpdateChapter(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "chapter not found"})
+		return
+	}
+	var req adminChapter
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	req.ID = id
+	h.editDraft(w, r, http.StatusOK, func(current *data.Store, d *data.Draft) error {
+		if chapterOf(current, id) == nil {
+			return data.ErrNotFound
+		}
+		d.PutChapter(req.model())
+		return nil
+	}, func(saved *data.Store) any {
+		return chapterOf(saved, id)
+	})
+}
+
+// DeleteChapter deletes a chapter with its lessons and quizzes.
+func (h *Handler) DeleteChapter(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "chapter not found"})
+		return
+	}
+	h.editDraft(w, r, http.StatusOK, func(_ *data.Store, d *data.Draft) error {
+		return d.DeleteChapter(id)
+	}, func(*data.Store) any {
+		return map[string]bool{"ok": true}
+	})
+}
+
+// OrderLessons sets the order of a chapter's lessons.
+func (h *Handler) OrderLessons(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "chapter not found"})
+		return
+	}
+	var req struct {
+		Lessons []string `json:"lessons"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	h.editDraft(w, r, http.StatusOK, func(_ *data.Store, d *data.Draft) error {
+		return d.OrderLessons(id, req.Lessons)
+	}, func(saved *data.Store) any {
+		return chapterOf(saved, id)
+	})
+}
+
+// GetAdminLesson returns a draft lesson with its quiz, including answers.
+func (h *Handler) GetAdminLesson(w http.ResponseWriter, r *http.Request) {
+	catalog, err := h.draft()
+	if err != nil {
+		log.Printf("load draft: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load draft"})
+		return
+	}
+	current, ok := catalog.Course(r.PathValue("course"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "course not found"})
+		return
+	}
+	lesson, ok := current.GetLesson(r.PathValue("id"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "lesson not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, lessonResponse(current, lesson.ID))
+}
+
+// CreateLesson adds a lesson at the end of its chapter.
+func (h *Handler) CreateLesson(w http.ResponseWriter, r *http.Request) {
+	var req adminLesson
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	h.editDraft(w, r, http.StatusCreated, func(current *data.Store, d *data.Draft) error {
+		if _, ok := current.GetLesson(req.ID); ok {
+			return errExists
+		}
+		_, err := d.PutLesson(req.model())
+		return err
+	}, func(saved *data.Store) any {
+		return lessonResponse(saved, req.ID)
+	})
+}
+
+// UpdateLesson replaces a lesson's content, examples and exercise. Giving
+// another chapterId moves the lesson to the end of that chapter.
+func (h *Handler) UpdateLesson(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req adminLesson
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	req.ID = id
+	h.editDraft(w, r, http.StatusOK, func(current *data.Store, d *data.Draft) error {
+		old, ok := current.GetLesson(id)
+		if !ok {
+			return data.ErrNotFound
+		}
+		if req.ChapterID == 0 {
+			req.ChapterID = old.ChapterID
+		}
+		_, err := d.PutLesson(req.model())
+		return err
+	}, func(saved *data.Store) any {
+		return lessonResponse(saved, id)
+	})
+}
+
+// DeleteLesson deletes a lesson with its quiz.
+func (h *Handler) DeleteLesson(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	h.editDraft(w, r, http.StatusOK, func(_ *data.Store, d *data.Draft) error {
+		return d.DeleteLesson(id)
+	}, func(*data.Store) any {
+		return map[string]bool{"ok": true}
+	})
+}
+
+// PutQuiz creates or replaces a lesson's quiz; the order of its questions
+// is the order given.
+func (h *Handler) PutQuiz(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req models.Quiz
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	req.LessonID = id
+	h.editDraft(w, r, http.StatusOK, func(_ *data.Store, d *data.Draft) error {
+		_, err := d.PutQuiz(req)
+		return err
+	}, func(saved *data.Store) any {
+		return lessonResponse(saved, id)
+	})
+}
+
+// DeleteQuiz deletes a lesson's quiz.
+func (h *Handler) DeleteQuiz(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	h.editDraft(w, r, http.StatusOK, func(_ *data.Store, d *data.Draft) error {
+		return d.DeleteQuiz(id)
+	}, func(saved *data.Store) any {
+		return lessonResponse(saved, id)
+	})
+}
+
+// CreateQuestion adds a question to the end of a lesson's quiz, creating
+// the quiz if the lesson has none.
+func (h *Handler) CreateQuestion(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req models.Question
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	h.editDraft(w, r, http.StatusCreated, func(current *data.Store, d *data.Draft) error {
+		if _, ok := current.QuestionLesson(req.ID); ok {
+			return errExists
+		}
+		_, err := d.PutQuestion(id, req)
+		return err
+	}, func(saved *data.Store) any {
+		return lessonResponse(saved, id)
+	})
+}
+
+// UpdateQuestion replaces a question of a lesson's quiz.
+func (h *Handler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
+	id, qid := r.PathValue("id"), r.PathValue("questionId")
+	var req models.Question
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	req.ID = qid
+	h.editDraft(w, r, http.StatusOK, func(current *data.Store, d *data.Draft) error {
+		if lessonID, ok := current.QuestionLesson(qid); !ok || lessonID != id {
+			return data.ErrNotFound
+		}
+		_, err := d.PutQuestion(id, req)
+		return err
+	}, func(saved *data.Store) any {
+		return lessonResponse(saved, id)
+	})
+}
+
+// DeleteQuestion deletes a question from a lesson's quiz.
+func (h *Handler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
+	id, qid := r.PathValue("id"), r.PathValue("questionId")
+	h.editDraft(w, r, http.StatusOK, func(_ *data.Store, d *data.Draft) error {
+		return d.DeleteQuestion(id, qid)
+	}, func(saved *data.Store) any {
+		return lessonResponse(saved, id)
+	})
+}
+
+// Publish makes the draft the content learners see, without a restart.
+func (h *Handler) Publish(w http.ResponseWriter, r *http.Request) {
+	h.admin.mu.Lock()
+	defer h.admin.mu.Unlock()
+
+	err := h.db.Publish()
+	if errors.Is(err, data.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "there is no draft to publish"})
+		return
+	}
+	if err != nil {
+		log.Printf("publish: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to publish"})
+		return
+	}
+	catalog, err := data.Load(h.db.Source(data.StatePublished))
+	if err != nil {
+		log.Printf("load published content: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load published content"})
+		return
+	}
+	h.courses.Store(catalog)
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// DiscardDraft deletes the draft; the next edit starts from the published
+// content.
+func (h *Handler) DiscardDraft(w http.ResponseWriter, r *http.Request) {
+	h.admin.mu.Lock()
+	defer h.admin.mu.Unlock()
+
+	if err := h.db.DiscardDraft(); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to discard draft"})
+		return
+	}
+	h.setPreview(nil)
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// previewContent returns the draft if the request's preview query
+// parameter is the draft's preview token, and nil otherwise.
+func (h *Handler) previewContent(r *http.Request) *data.Catalog {
+	token := r.URL.Query().Get("preview")
+	if token == "" || h.admin == nil {
+		return nil
+	}
+	a := h.admin
+	a.previewMu.Lock()
+	defer a.previewMu.Unlock()
+	if a.preview == nil {
+		// Load the draft saved before the server started.
+		want, err := h.db.PreviewToken()
+		if err != nil {
+			return nil
+		}
+		c, err := data.Load(h.db.Source(data.StateDraft))
+		if err != nil {
+			return nil
+		}
+		a.previewToken, a.preview = want, c
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(a.previewToken)) != 1 {
+		return nil
+	}
+	return a.preview
+}
+
+// setPreview replaces the cached draft after it was saved or discarded.
+func (h *Handler) setPreview(c *data.Catalog) {
+	a := h.admin
+	a.previewMu.Lock()
+	defer a.previewMu.Unlock()
+	a.preview, a.previewToken = nil, ""
+	if c == nil {
+		return
+	}
+	if token, err := h.db.PreviewToken(); err == nil {
+		a.preview, a.previewToken = c, token
+	}
+}
+
+func chapterOf(s *data.Store, id int) *models.Chapter {
+	for _, ch := range s.GetChapters() {
+		if ch.ID == id {
+			return &ch
+		}
+	}
+	return nil
+}
+
+// lessonResponse is a draft lesson for instructors: the lesson with its
+// hidden fields, and its quiz with answers.
+func lessonResponse(s *data.Store, id string) map[string]any {
+	lesson, _ := s.GetLesson(id)
+	resp := map[string]any{"lesson": adminLessonOf(lesson)}
+	if quiz, ok := s.QuizSource(id); ok {
+		resp["quiz"] = quiz
+	}
+	return resp
+}
+
+// unjoin splits an error made by errors.Join.
+func unjoin(err error) []error {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		return j.Unwrap()
+	}
+	return []error{err}
+}
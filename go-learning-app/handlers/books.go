@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+
+	"go-learning-app/data"
+	"go-learning-app/publish"
+)
+
+// bookFormats are the formats ExportBook writes, by the format query
+// parameter.
+var bookFormats = map[string]struct {
+	contentType string
+	write       func(context.Context, *data.Store, io.Writer) error
+}{
+	"epub": {"application/epub+zip", publish.EPUB},
+	"html": {"text/html; charset=utf-8", publish.Handbook},
+}
+
+// ExportBook downloads a published course as an EPUB book, or with
+// format=html as a single page for printing a handbook. The lang query
+// parameter picks a translation, and draft=true exports the draft.
+func (h *Handler) ExportBook(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("format")
+	if name == "" {
+		name = "epub"
+	}
+	format, ok := bookFormats[name]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "format must be epub or html"})
+		return
+	}
+	catalog, err := h.exported(r)
+	if err != nil {
+		log.Printf("load draft: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load draft"})
+		return
+	}
+	store, ok := catalog.Course(r.PathValue("course"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "course not found"})
+		return
+	}
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		lang = data.DefaultLocale
+	}
+	if !slices.Contains(store.Locales(), lang) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "course has no such translation"})
+		return
+	}
+	var buf bytes.Buffer
+	if err := format.write(r.Context(), store.Localized(lang), &buf); err != nil {
+		log.Printf("export book %s: %v", store.Course.ID, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to export book"})
+		return
+	}
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+store.Course.ID+"."+name+`"`)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go-learning-app/data"
+	"go-learning-app/models"
+)
+
+// courseKey is the context key of the course a request is for.
+type courseKey struct{}
+
+// previewKey is the context key set on requests that preview the draft.
+type previewKey struct{}
+
+// InCourse wraps the handler of a route under /api/courses/{course}. It
+// answers 404 for an unknown course, and otherwise makes the course's
+// content available to the handler through h.content. A GET request with
+// a valid preview token gets the draft of the course, which may not be
+// published yet. Previews are read-only: any other request carrying a
+// preview token is refused, so a preview never records progress.
+func (h *Handler) InCourse(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("preview") && r.Method != http.MethodGet {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": msg(h.locale(r), "プレビュー中は学習の記録を保存できません")})
+			return
+		}
+		id := r.PathValue("course")
+		ctx := r.Context()
+		store, ok := h.catalog().Course(id)
+		if draft := h.previewContent(r); draft != nil {
+			store, ok = draft.Course(id)
+			ctx = context.WithValue(ctx, previewKey{}, true)
+		}
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "course not found"})
+			return
+		}
+		next(w, r.WithContext(context.WithValue(ctx, courseKey{}, store)))
+	}
+}
+
+// previewing reports whether the request previews the draft; see
+// InCourse. Handlers of previews must not write learner records.
+func previewing(r *http.Request) bool {
+	return r.Context().Value(previewKey{}) != nil
+}
+
+// catalog returns the courses currently served.
+func (h *Handler) catalog() *data.Catalog {
+	return h.courses.Load()
+}
+
+// content returns the Japanese store of the request's course; see
+// InCourse.
+func (h *Handler) content(r *http.Request) *data.Store {
+	return r.Context().Value(courseKey{}).(*data.Store)
+}
+
+// courseDB returns the records of the request's course.
+func (h *Handler) courseDB(r *http.Request) *data.CourseDB {
+	return h.db.Course(r.PathValue("course"))
+}
+
+// GetCourses lists the courses in catalog order, or the draft's with a
+// preview token. With a username query parameter, each course counts the
+// lessons the user has completed.
+func (h *Handler) GetCourses(w http.ResponseWriter, r *http.Request) {
+	catalog := h.catalog()
+	if draft := h.previewContent(r); draft != nil {
+		catalog = draft
+	}
+	lang := h.locale(r)
+	username := strings.TrimSpace(r.URL.Query().Get("username"))
+	if username == "" {
+		writeJSON(w, http.StatusOK, catalog.Summaries(lang, nil))
+		return
+	}
+	progress := make(map[string][]models.LessonProgress)
+	for _, s := range catalog.Courses() {
+		rows, err := h.db.Course(s.Course.ID).GetProgress(username)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get progress"})
+			return
+		}
+		progress[s.Course.ID] = s.ResolveProgress(rows)
+	}
+	writeJSON(w, http.StatusOK, catalog.Summaries(lang, progress))
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"go-learning-app/stddoc"
+)
+
+// GetDoc returns the documentation of a standard library package, such as
+// /api/doc/net/http, or of one of its declarations, such as
+// /api/doc/strings.Builder or /api/doc/strings.Builder.WriteString.
+func (h *Handler) GetDoc(w http.ResponseWriter, r *http.Request) {
+	path, name := stddoc.SplitRef(r.PathValue("ref"))
+	if name == "" {
+		pkg, err := h.docs.Package(path)
+		if !h.docFound(w, err) {
+			return
+		}
+		writeJSON(w, http.StatusOK, pkg)
+		return
+	}
+	pkg, sym, err := h.docs.Symbol(path, name)
+	if !h.docFound(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"package": map[string]string{"importPath": pkg.ImportPath, "name": pkg.Name, "synopsis": pkg.Synopsis},
+		"symbol":  sym,
+	})
+}
+
+// docFound writes the error response for a failed documentation lookup
+// and reports whether the request may go on.
+func (h *Handler) docFound(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, stddoc.ErrNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "documentation not found"})
+	default:
+		log.Printf("documentation: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to read documentation"})
+	}
+	return false
+}
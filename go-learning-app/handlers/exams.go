@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-learning-app/data"
+	"go-learning-app/grading"
+	"go-learning-app/models"
+)
+
+// examGrace is how long after the deadline a submission is still graded in
+// full, to allow for network latency. Later submissions are graded on the
+// answers saved before the deadline.
+const examGrace = 10 * time.Second
+
+// ExamStatus is an exam with a learner's standing on it.
+type ExamStatus struct {
+	models.Exam
+	Unlocked        bool      `json:"unlocked"`
+	Passed          bool      `json:"passed"`
+	BestScore       *int      `json:"bestScore"`
+	Attempts        int       `json:"attempts"`
+	ActiveSessionID int64     `json:"activeSessionId,omitempty"`
+	NextAttemptAt   time.Time `json:"nextAttemptAt,omitzero"`
+	// Reason explains why the exam cannot be started right now.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ExamSessionResponse is a session with the questions it presents. Result is
+// set only on the response that grades the session.
+type ExamSessionResponse struct {
+	Session    models.ExamSession      `json:"session"`
+	Exam       models.Exam             `json:"exam"`
+	Questions  []models.PublicQuestion `json:"questions"`
+	ServerTime time.Time               `json:"serverTime"`
+	Result     *grading.Result         `json:"result,omitempty"`
+}
+
+// ExamAnswersRequest is the request body for saving or submitting answers.
+type ExamAnswersRequest struct {
+	Username string                     `json:"username"`
+	Answers  map[string]json.RawMessage `json:"answers"`
+}
+
+// GetExams lists every exam with the standing of the user given in the
+// username query parameter.
+func (h *Handler) GetExams(w http.ResponseWriter, r *http.Request) {
+	store, lang := h.localized(r)
+	username := strings.TrimSpace(r.URL.Query().Get("username"))
+	if username == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg(lang, "ユーザー名を入力してください")})
+		return
+	}
+
+	statuses, err := h.examStatuses(r, store, lang, username, time.Now())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get exams"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"exams": statuses})
+}
+
+// StartExam starts a timed session of an exam, or resumes the user's open
+// session. The exam must be unlocked, not yet passed and allowed by its
+// attempt limit and cooldown.
+func (h *Handler) StartExam(w http.ResponseWriter, r *http.Request) {
+	store, lang := h.localized(r)
+	exam, ok := store.GetExam(r.PathValue("examId"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "exam not found"})
+		return
+	}
+	var req struct {
+		Username string `json:"username"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	username := strings.TrimSpace(req.Username)
+	if username == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg(lang, "ユーザー名を入力してください")})
+		return
+	}
+
+	now := time.Now()
+	statuses, err := h.examStatuses(r, store, lang, username, now)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to start exam"})
+		return
+	}
+	var status ExamStatus
+	for _, st := range statuses {
+		if st.ID == exam.ID {
+			status = st
+		}
+	}
+
+	if status.ActiveSessionID != 0 {
+		session, err := h.courseDB(r).GetExamSession(status.ActiveSessionID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load exam session"})
+			return
+		}
+		writeJSON(w, http.StatusOK, examSessionResponse(store, exam, session, nil, now))
+		return
+	}
+	switch {
+	case status.Passed:
+		writeJSON(w, http.StatusConflict, map[string]any{"error": status.Reason})
+		return
+	case !status.NextAttemptAt.IsZero():
+		writeJSON(w, http.StatusTooManyRequests, map[string]any{
+			"error":         status.Reason,
+			"nextAttemptAt": status.NextAttemptAt,
+		})
+		return
+	case status.Reason != "":
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": status.Reason})
+		return
+	}
+
+	pool := store.ExamPool(exam)
+	seed := rand.Int64()
+	session := models.ExamSession{
+		ExamID:      exam.ID,
+		Username:    username,
+		Seed:        seed,
+		QuestionIDs: grading.Draw(pool, exam.Questions, nil, uint64(seed)),
+		Answers:     map[string]json.RawMessage{},
+		StartedAt:   now.Truncate(time.Second),
+	}
+	session.Deadline = session.StartedAt.Add(time.Duration(exam.TimeLimitSec) * time.Second)
+	session.ID, err = h.courseDB(r).StartExamSession(session)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to start exam"})
+		return
+	}
+	writeJSON(w, http.StatusOK, examSessionResponse(store, exam, session, nil, now))
+}
+
+// GetExamSession returns a session of the user given in the username query
+// parameter, so an exam in progress can be resumed after a reload.
+func (h *Handler) GetExamSession(w http.ResponseWriter, r *http.Request) {
+	store, _ := h.localized(r)
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "exam session not found"})
+		return
+	}
+	username := strings.TrimSpace(r.URL.Query().Get("username"))
+
+	now := time.Now()
+	exam, session, result, err := h.examSession(r, store, id, username, now)
+	if errors.Is(err, data.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "exam session not found"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load exam session"})
+		return
+	}
+	writeJSON(w, http.StatusOK, examSessionResponse(store, exam, session, result, now))
+}
+
+// SaveExamAnswers saves the answers of an open session so far. Saving is
+// refused once the deadline has passed.
+func (h *Handler) SaveExamAnswers(w http.ResponseWriter, r *http.Request) {
+	store, lang := h.localized(r)
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "exam session not found"})
+		return
+	}
+	var req ExamAnswersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+
+	now := time.Now()
+	_, session, _, err := h.examSession(r, store, id, strings.TrimSpace(req.Username), now)
+	if errors.Is(err, data.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "exam session not found"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load exam session"})
+		return
+	}
+	if session.Submitted() || now.After(session.Deadline) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": msg(lang, "試験時間が終了しています")})
+		return
+	}
+
+	if req.Answers == nil {
+		req.Answers = map[string]json.RawMessage{}
+	}
+	err = h.courseDB(r).SaveExamAnswers(session.ID, req.Answers)
+	if errors.Is(err, data.ErrAlreadySubmitted) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": msg(lang, "この試験はすでに提出済みです")})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save answers"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"ok": true, "serverTime": now})
+}
+
+// SubmitExam grades a session. Answers sent after the deadline and grace
+// period are ignored in favour of those saved in time.
+func (h *Handler) SubmitExam(w http.ResponseWriter, r *http.Request) {
+	store, lang := h.localized(r)
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "exam session not found"})
+		return
+	}
+	var req ExamAnswersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+
+	now := time.Now()
+	exam, session, _, err := h.examSession(r, store, id, strings.TrimSpace(req.Username), now)
+	if errors.Is(err, data.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "exam session not found"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load exam session"})
+		return
+	}
+	if session.Submitted() {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": msg(lang, "この試験はすでに提出済みです")})
+		return
+	}
+
+	answers := session.Answers
+	if req.Answers != nil {
+		answers = req.Answers
+	}
+	session, result, err := h.finishExam(r, store, exam, session, answers, now)
+	if errors.Is(err, data.ErrAlreadySubmitted) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": msg(lang, "この試験はすでに提出済みです")})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to grade exam"})
+		return
+	}
+	writeJSON(w, http.StatusOK, examSessionResponse(store, exam, session, &result, now))
+}
+
+// examSession loads a user's session and finalizes it if its time ran out
+// without a submission. The result is set only when it was finalized.
+func (h *Handler) examSession(r *http.Request, store *data.Store, id int64, username string, now time.Time) (models.Exam, models.ExamSession, *grading.Result, error) {
+	session, err := h.courseDB(r).GetExamSession(id)
+	if err != nil {
+		return models.Exam{}, session, nil, err
+	}
+	exam, ok := store.GetExam(session.ExamID)
+	if !ok || session.Username != username {
+		return models.Exam{}, session, nil, data.ErrNotFound
+	}
+	// An expired session is graded when next loaded, but not by a preview,
+	// which would grade it against the draft.
+	if session.Submitted() || now.Before(session.Deadline.Add(examGrace)) || previewing(r) {
+		return exam, session, nil, nil
+	}
+
+	session, result, err := h.finishExam(r, store, exam, session, session.Answers, now)
+	if errors.Is(err, data.ErrAlreadySubmitted) {
+		// Finalized concurrently; reload the stored grade.
+		session, err = h.courseDB(r).GetExamSession(id)
+		return exam, session, nil, err
+	}
+	if err != nil {
+		return exam, session, nil, err
+	}
+	return exam, session, &result, nil
+}
+
+// finishExam grades and closes a session. Submissions past the deadline and
+// grace period are graded on the answers saved before the deadline.
+func (h *Handler) finishExam(r *http.Request, store *data.Store, exam models.Exam, session models.ExamSession, answers map[string]json.RawMessage, now time.Time) (models.ExamSession, grading.Result, error) {
+	if now.After(session.Deadline.Add(examGrace)) {
+		answers = session.Answers
+	}
+	view := grading.Present(store.ExamPool(exam), session.QuestionIDs, uint64(session.Seed))
+	result, err := grading.Grade(r.Context(), view.Quiz, answers)
+	if err != nil {
+		return session, result, err
+	}
+
+	session.Answers = answers
+	session.Score = result.Percent
+	session.Passed = result.Passed
+	if err := h.courseDB(r).FinishExamSession(session, now); err != nil {
+		return session, result, err
+	}
+	session.SubmittedAt = now
+	if err := h.queueMissed(r, session.Username, result); err != nil {
+		return session, result, err
+	}
+	return session, result, nil
+}
+
+// examStatuses returns the user's standing on every exam in the store's
+// locale, finalizing any session whose time ran out first.
+func (h *Handler) examStatuses(r *http.Request, store *data.Store, lang, username string, now time.Time) ([]ExamStatus, error) {
+	sessions, err := h.courseDB(r).GetExamSessions(username, "")
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range sessions {
+		if s.Submitted() {
+			continue
+		}
+		_, s, _, err := h.examSession(r, store, s.ID, username, now)
+		if err != nil {
+			return nil, err
+		}
+		sessions[i] = s
+	}
+
+	_, lessons, err := h.progress(r, username)
+	if err != nil {
+		return nil, err
+	}
+	passed := passedExams(sessions)
+	chapters := store.ResolveChapters(lessons, passed)
+
+	var statuses []ExamStatus
+	for _, exam := range store.GetExams() {
+		st := ExamStatus{Exam: exam, Passed: passed[exam.ID]}
+		var last models.ExamSession
+		for _, s := range sessions {
+			if s.ExamID != exam.ID {
+				continue
+			}
+			st.Attempts++
+			last = s
+			if !s.Submitted() {
+				st.ActiveSessionID = s.ID
+			} else if st.BestScore == nil || s.Score > *st.BestScore {
+				score := s.Score
+				st.BestScore = &score
+			}
+		}
+
+		st.Unlocked = true
+		for _, cp := range chapters {
+			switch {
+			case exam.ChapterID == 0:
+				st.Unlocked = st.Unlocked && cp.Completed
+			case cp.ChapterID == exam.ChapterID:
+				st.Unlocked = cp.LessonsCompleted == cp.LessonsTotal
+			}
+		}
+
+		switch {
+		case st.ActiveSessionID != 0:
+		case st.Passed:
+			st.Reason = msg(lang, "この試験には合格済みです")
+		case !st.Unlocked && exam.ChapterID == 0:
+			st.Reason = msg(lang, "すべての章を修了すると受験できます")
+		case !st.Unlocked:
+			st.Reason = msg(lang, "この章のレッスンをすべて完了すると受験できます")
+		case exam.MaxAttempts > 0 && st.Attempts >= exam.MaxAttempts:
+			st.Reason = msg(lang, "受験回数の上限に達しました")
+		case last.Submitted() && now.Before(last.SubmittedAt.Add(time.Duration(exam.CooldownSec)*time.Second)):
+			st.NextAttemptAt = last.SubmittedAt.Add(time.Duration(exam.CooldownSec) * time.Second)
+			st.Reason = msg(lang, "再受験までお待ちください")
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// chapterProgress resolves chapter completion from the user's lesson
+// progress and exam results, and whether the whole course is certified.
+func (h *Handler) chapterProgress(r *http.Request, username string, lessons []models.LessonProgress) ([]models.ChapterProgress, bool, error) {
+	sessions, err := h.courseDB(r).GetExamSessions(username, "")
+	if err != nil {
+		return nil, false, err
+	}
+	passed := passedExams(sessions)
+	return h.content(r).ResolveChapters(lessons, passed), passed[data.FinalExamID], nil
+}
+
+func examSessionResponse(store *data.Store, exam models.Exam, session models.ExamSession, result *grading.Result, now time.Time) ExamSessionResponse {
+	view := grading.Present(store.ExamPool(exam), session.QuestionIDs, uint64(session.Seed))
+	return ExamSessionResponse{
+		Session:    session,
+		Exam:       exam,
+		Questions:  view.Public().Questions,
+		ServerTime: now,
+		Result:     result,
+	}
+}
+
+func passedExams(sessions []models.ExamSession) map[string]bool {
+	passed := make(map[string]bool)
+	for _, s := range sessions {
+		if s.Passed {
+			passed[s.ExamID] = true
+		}
+	}
+	return passed
+}
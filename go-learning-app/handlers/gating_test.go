@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"go-learning-app/data"
+)
+
+// strictCourse is a course whose one chapter gates strictly: lesson 1-2
+// requires 1-1, which is complete once read.
+var strictCourse = fstest.MapFS{
+	"c/course.yaml":                  {Data: []byte("id: c\ntitle: Course\n")},
+	"c/chapter01/chapter.yaml":       {Data: []byte("id: 1\ntitle: Chapter\ngating: strict\nlessons:\n  - 1-1\n  - 1-2\n")},
+	"c/chapter01/1-1.md":             {Data: []byte("---\nid: 1-1\ntitle: First\ncompletion:\n  read: true\n---\nFirst lesson.\n")},
+	"c/chapter01/1-2.md":             {Data: []byte("---\nid: 1-2\ntitle: Second\nrequires:\n  - 1-1\nexamples:\n  - title: Example\n    file: _examples/1-2-1.go\n---\nSecond lesson.\n")},
+	"c/chapter01/_examples/1-2-1.go": {Data: []byte("package main\n\nfunc main() {}\n")},
+}
+
+func TestStrictGating(t *testing.T) {
+	catalog, err := data.LoadCatalog(strictCourse)
+	if err != nil {
+		t.Fatalf("LoadCatalog: %v", err)
+	}
+	db, err := data.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	h := New(catalog, db)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/courses/{course}/lessons/{id}", h.InCourse(h.GetLesson))
+	mux.HandleFunc("POST /api/courses/{course}/progress/{username}/{lessonId}/read", h.InCourse(h.MarkRead))
+	mux.HandleFunc("POST /api/courses/{course}/progress/{username}/{lessonId}/examples/{index}", h.InCourse(h.MarkExampleRun))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{"unlocked lesson", "GET", "/lessons/1-1?username=u", http.StatusOK},
+		{"locked lesson", "GET", "/lessons/1-2?username=u", http.StatusForbidden},
+		{"locked lesson without a user", "GET", "/lessons/1-2", http.StatusForbidden},
+		{"reading a locked lesson", "POST", "/progress/u/1-2/read", http.StatusForbidden},
+		{"running a locked lesson's example", "POST", "/progress/u/1-2/examples/0", http.StatusForbidden},
+		{"reading the prerequisite", "POST", "/progress/u/1-1/read", http.StatusOK},
+		{"lesson unlocked by the prerequisite", "GET", "/lessons/1-2?username=u", http.StatusOK},
+		{"still locked for another user", "GET", "/lessons/1-2?username=v", http.StatusForbidden},
+		{"running an unlocked lesson's example", "POST", "/progress/u/1-2/examples/0", http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tt.method, "/api/courses/c"+tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s: %s %s = %d, want %d: %s", tt.name, tt.method, tt.path, rec.Code, tt.want, rec.Body)
+		}
+	}
+}
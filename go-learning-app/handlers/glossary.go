@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-learning-app/models"
+)
+
+// GetGlossary returns the course's glossary terms.
+func (h *Handler) GetGlossary(w http.ResponseWriter, r *http.Request) {
+	store, _ := h.localized(r)
+	terms := store.Glossary()
+	if terms == nil {
+		terms = []models.GlossaryTerm{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"terms": terms})
+}
+
+// GetGlossaryTerm returns a glossary term with the lessons that mention
+// it and its related terms.
+func (h *Handler) GetGlossaryTerm(w http.ResponseWriter, r *http.Request) {
+	store, _ := h.localized(r)
+	term, ok := store.GlossaryTerm(r.PathValue("id"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "term not found"})
+		return
+	}
+	lessons := []models.LessonSummary{}
+	for _, id := range term.Mentions {
+		if l, ok := store.GetLesson(id); ok {
+			lessons = append(lessons, models.LessonSummary{ID: l.ID, Title: l.Title})
+		}
+	}
+	related := []models.GlossaryTerm{}
+	for _, id := range term.Related {
+		if t, ok := store.GlossaryTerm(id); ok {
+			related = append(related, t)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"term": term, "lessons": lessons, "related": related})
+}
@@ -1,57 +1,102 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"strconv"
 	"strings"
-	"time"
+	"sync/atomic"
 
 	"go-learning-app/data"
+	"go-learning-app/models"
+	"go-learning-app/runner"
+	"go-learning-app/stddoc"
 )
 
-// Handler holds the data store and provides HTTP handler methods.
+// Handler holds the course catalog and provides HTTP handler methods.
 type Handler struct {
-	store *data.Store
-	db    *data.DB
+	// courses is swapped as a whole when content is reloaded in
+	// development mode or published, so a request never sees a partly
+	// loaded catalog.
+	courses atomic.Pointer[data.Catalog]
+	db      *data.DB
+	// reload is set in development mode; see EnableReload.
+	reload *reloadHub
+	// admin is set when the authoring API is enabled; see EnableAdmin.
+	admin *adminState
+	// docs serves the standard library's documentation.
+	docs *stddoc.Docs
 }
 
-// New creates a new Handler with the given store and database.
-func New(store *data.Store, db *data.DB) *Handler {
-	return &Handler{store: store, db: db}
+// New creates a new Handler with the given catalog and database.
+func New(catalog *data.Catalog, db *data.DB) *Handler {
+	h := &Handler{db: db, docs: stddoc.New()}
+	h.courses.Store(catalog)
+	return h
 }
 
-// GetChapters returns all chapters as JSON.
+// GetChapters returns all chapters as JSON. With a username query
+// parameter, each lesson carries the user's missing prerequisites and
+// lock state.
 func (h *Handler) GetChapters(w http.ResponseWriter, r *http.Request) {
-	writeJSON(w, http.StatusOK, h.store.GetChapters())
+	store, _ := h.localized(r)
+	username := strings.TrimSpace(r.URL.Query().Get("username"))
+	if username == "" {
+		writeJSON(w, http.StatusOK, store.GetChapters())
+		return
+	}
+	_, lessons, err := h.progress(r, username)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get progress"})
+		return
+	}
+	writeJSON(w, http.StatusOK, store.ChaptersFor(lessons))
 }
 
-// GetLesson returns a single lesson by ID.
+// GetLesson returns a single lesson by ID. It refuses a lesson that strict
+// gating locks for the user in the username query parameter; without one,
+// no prerequisites count as completed.
 func (h *Handler) GetLesson(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
-	lesson, ok := h.store.GetLesson(id)
+	store, lang := h.localized(r)
+	lesson, ok := store.GetLesson(id)
 	if !ok {
 		writeJSON(w, http.StatusNotFound, map[string]string{"error": "lesson not found"})
 		return
 	}
+	if !h.checkUnlocked(w, r, lang, strings.TrimSpace(r.URL.Query().Get("username")), id) {
+		return
+	}
 	writeJSON(w, http.StatusOK, lesson)
 }
 
-// GetQuiz returns the quiz for a lesson.
-func (h *Handler) GetQuiz(w http.ResponseWriter, r *http.Request) {
-	lessonID := r.PathValue("lessonId")
-	quiz, ok := h.store.GetQuiz(lessonID)
-	if !ok {
-		writeJSON(w, http.StatusNotFound, map[string]string{"error": "quiz not found"})
-		return
+// checkUnlocked writes a 403 response listing the missing prerequisites if
+// strict gating locks the lesson for the user, and reports whether the
+// request may go on.
+func (h *Handler) checkUnlocked(w http.ResponseWriter, r *http.Request, lang, username, lessonID string) bool {
+	completed, _, err := h.progress(r, username)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get progress"})
+		return false
 	}
-	writeJSON(w, http.StatusOK, quiz)
+	done := make(map[string]bool, len(completed))
+	for _, id := range completed {
+		done[id] = true
+	}
+	missing, locked := h.content(r).MissingPrerequisites(lessonID, done)
+	if locked {
+		writeJSON(w, http.StatusForbidden, map[string]any{
+			"error":   msg(lang, "先に前提のレッスンを修了してください"),
+			"missing": missing,
+		})
+		return false
+	}
+	return true
 }
 
-// Login handles user login/registration and returns progress.
+// Login handles user login/registration and returns the user's locale
+// preference. Progress is kept per course; see GetProgress.
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Username string `json:"username"`
@@ -63,7 +108,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 
 	username := strings.TrimSpace(req.Username)
 	if username == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ユーザー名を入力してください"})
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg(h.locale(r), "ユーザー名を入力してください")})
 		return
 	}
 
@@ -72,55 +117,170 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	progress, err := h.db.GetProgress(username)
+	preferred, err := h.db.GetUserLocale(username)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get progress"})
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get locale"})
 		return
 	}
-	if progress == nil {
-		progress = []string{}
-	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
 		"username": username,
-		"progress": progress,
+		// locale is the saved preference, "" when there is none.
+		"locale":  preferred,
+		"locales": h.catalog().Locales(),
 	})
 }
 
-// GetProgress returns completed lessons for a user.
+// GetProgress returns completed lessons and the per-component breakdown
+// for a user in the course.
 func (h *Handler) GetProgress(w http.ResponseWriter, r *http.Request) {
 	username := r.PathValue("username")
 
-	progress, err := h.db.GetProgress(username)
+	completed, lessons, err := h.progress(r, username)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get progress"})
 		return
 	}
-	if progress == nil {
-		progress = []string{}
+
+	chapters, certified, err := h.chapterProgress(r, username, lessons)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get progress"})
+		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{"progress": progress})
+	writeJSON(w, http.StatusOK, map[string]any{
+		"progress":  completed,
+		"lessons":   lessons,
+		"chapters":  chapters,
+		"certified": certified,
+	})
 }
 
-// MarkProgress marks a lesson as completed for a user.
-func (h *Handler) MarkProgress(w http.ResponseWriter, r *http.Request) {
+// MarkRead records that the user has read a lesson.
+func (h *Handler) MarkRead(w http.ResponseWriter, r *http.Request) {
 	username := r.PathValue("username")
 	lessonID := r.PathValue("lessonId")
+	lesson, ok := h.content(r).GetLesson(lessonID)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "lesson not found"})
+		return
+	}
+	if !h.checkUnlocked(w, r, h.locale(r), username, lessonID) {
+		return
+	}
 
-	if err := h.db.MarkCompleted(username, lessonID); err != nil {
+	if err := h.courseDB(r).MarkRead(username, lessonID, lesson.Version); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save progress"})
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	h.writeLessonProgress(w, r, username, lessonID)
 }
 
-// ResetProgress deletes all progress for a user.
+// SubmitExercise runs the user's exercise solution and records a pass.
+// A solution passes when it differs from the starter code, runs without
+// error and, if the exercise defines one, prints the expected output.
+func (h *Handler) SubmitExercise(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	lessonID := r.PathValue("lessonId")
+	lang := h.locale(r)
+	lesson, ok := h.content(r).GetLesson(lessonID)
+	if !ok || lesson.Exercise == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "exercise not found"})
+		return
+	}
+	if !h.checkUnlocked(w, r, lang, username, lessonID) {
+		return
+	}
+
+	var req RunCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	if strings.TrimSpace(req.Code) == strings.TrimSpace(lesson.Exercise.StarterCode) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"passed": false,
+			"reason": msg(lang, "スターターコードから変更されていません"),
+		})
+		return
+	}
+
+	res, err := runner.Run(r.Context(), req.Code)
+	if err != nil && !errors.Is(err, runner.ErrTimeout) {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to run code"})
+		return
+	}
+
+	reason := ""
+	switch {
+	case err != nil:
+		reason = msg(lang, err.Error())
+	case res.Failed:
+		reason = msg(lang, "実行エラー")
+	case lesson.Exercise.ExpectedOutput != "" &&
+		strings.TrimSpace(res.Output) != strings.TrimSpace(lesson.Exercise.ExpectedOutput):
+		reason = msg(lang, "出力が期待した結果と一致しません")
+	}
+	if reason != "" {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"passed": false,
+			"reason": reason,
+			"output": res.Output,
+		})
+		return
+	}
+
+	if err := h.courseDB(r).MarkExercisePassed(username, lessonID, lesson.Version); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save progress"})
+		return
+	}
+
+	p, err := h.lessonProgress(r, username, lessonID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get progress"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"passed": true,
+		"output": res.Output,
+		"lesson": p,
+	})
+}
+
+// MarkExampleRun records that the user ran one of a lesson's code examples.
+func (h *Handler) MarkExampleRun(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	lessonID := r.PathValue("lessonId")
+	lesson, ok := h.content(r).GetLesson(lessonID)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "lesson not found"})
+		return
+	}
+	if !h.checkUnlocked(w, r, h.locale(r), username, lessonID) {
+		return
+	}
+
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil || index < 0 || index >= len(lesson.CodeExamples) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "example not found"})
+		return
+	}
+
+	if err := h.courseDB(r).MarkExampleRun(username, lessonID, index); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save progress"})
+		return
+	}
+
+	h.writeLessonProgress(w, r, username, lessonID)
+}
+
+// ResetProgress clears a user's completion in the course; quiz attempts
+// and exam sessions are kept.
 func (h *Handler) ResetProgress(w http.ResponseWriter, r *http.Request) {
 	username := r.PathValue("username")
 
-	if err := h.db.ResetProgress(username); err != nil {
+	if err := h.courseDB(r).ResetProgress(username); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to reset progress"})
 		return
 	}
@@ -128,6 +288,47 @@ func (h *Handler) ResetProgress(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 }
 
+// progress returns the IDs of completed lessons and the full per-lesson
+// breakdown for a user in the request's course.
+func (h *Handler) progress(r *http.Request, username string) ([]string, []models.LessonProgress, error) {
+	rows, err := h.courseDB(r).GetProgress(username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lessons := h.content(r).ResolveProgress(rows)
+	completed := []string{}
+	for _, p := range lessons {
+		if p.Completed {
+			completed = append(completed, p.LessonID)
+		}
+	}
+	return completed, lessons, nil
+}
+
+// lessonProgress returns the resolved progress of a single lesson.
+func (h *Handler) lessonProgress(r *http.Request, username, lessonID string) (models.LessonProgress, error) {
+	_, lessons, err := h.progress(r, username)
+	if err != nil {
+		return models.LessonProgress{}, err
+	}
+	for _, p := range lessons {
+		if p.LessonID == lessonID {
+			return p, nil
+		}
+	}
+	return models.LessonProgress{LessonID: lessonID}, nil
+}
+
+func (h *Handler) writeLessonProgress(w http.ResponseWriter, r *http.Request, username, lessonID string) {
+	p, err := h.lessonProgress(r, username, lessonID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get progress"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"ok": true, "lesson": p})
+}
+
 // RunCodeRequest is the request body for running code.
 type RunCodeRequest struct {
 	Code string `json:"code"`
@@ -147,42 +348,25 @@ func (h *Handler) RunCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	lang := h.locale(r)
 	if req.Code == "" {
-		writeJSON(w, http.StatusBadRequest, RunCodeResponse{Error: "コードが空です"})
+		writeJSON(w, http.StatusBadRequest, RunCodeResponse{Error: msg(lang, "コードが空です")})
 		return
 	}
 
-	// Create temp directory for code execution
-	tmpDir, err := os.MkdirTemp("", "gorun-*")
-	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, RunCodeResponse{Error: "Failed to create temp directory"})
+	res, err := runner.Run(r.Context(), req.Code)
+	if errors.Is(err, runner.ErrTimeout) {
+		writeJSON(w, http.StatusOK, RunCodeResponse{Output: res.Output, Error: msg(lang, err.Error())})
 		return
 	}
-	defer os.RemoveAll(tmpDir)
-
-	// Write code to temp file
-	codePath := filepath.Join(tmpDir, "main.go")
-	if err := os.WriteFile(codePath, []byte(req.Code), 0644); err != nil {
-		writeJSON(w, http.StatusInternalServerError, RunCodeResponse{Error: "Failed to write code"})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, RunCodeResponse{Error: "Failed to run code"})
 		return
 	}
 
-	// Execute with timeout (5 seconds)
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "go", "run", codePath)
-	output, err := cmd.CombinedOutput()
-
-	resp := RunCodeResponse{}
-	if ctx.Err() == context.DeadlineExceeded {
-		resp.Error = "実行がタイムアウトしました（5秒）"
-		resp.Output = string(output)
-	} else if err != nil {
-		resp.Error = "実行エラー"
-		resp.Output = string(output)
-	} else {
-		resp.Output = string(output)
+	resp := RunCodeResponse{Output: res.Output}
+	if res.Failed {
+		resp.Error = msg(lang, "実行エラー")
 	}
 
 	writeJSON(w, http.StatusOK, resp)
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go-learning-app/data"
+)
+
+// messages translates the Japanese messages shown to learners, keyed by
+// locale and then by the Japanese text. A missing entry shows the Japanese.
+var messages = map[string]map[string]string{
+	"en": {
+		"ユーザー名を入力してください":          "Please enter a username",
+		"試験時間が終了しています":            "The exam time is over",
+		"この試験はすでに提出済みです":          "This exam has already been submitted",
+		"このクイズはすでに提出済みです":         "This quiz has already been submitted",
+		"受験回数の上限に達しました":           "You have used all your attempts",
+		"この試験には合格済みです":            "You have already passed this exam",
+		"すべての章を修了すると受験できます":       "Available once you complete every chapter",
+		"この章のレッスンをすべて完了すると受験できます": "Available once you complete every lesson in this chapter",
+		"再受験までお待ちください":            "Please wait before retaking the exam",
+		"スターターコードから変更されていません":     "The code has not been changed from the starter code",
+		"実行エラー": "Runtime error",
+		"出力が期待した結果と一致しません":     "The output does not match the expected result",
+		"コードが空です":              "The code is empty",
+		"実行がタイムアウトしました（5秒）":    "Execution timed out (5 seconds)",
+		"%s のポイントを思い出してください":   "Recall the key points of %s",
+		"対応していない言語です":          "Unsupported language",
+		"検索語を入力してください":         "Please enter a search term",
+		"先に前提のレッスンを修了してください":   "Complete the prerequisite lessons first",
+		"この項目はまだ復習の時期ではありません":  "This item is not due for review yet",
+		"プレビュー中は学習の記録を保存できません": "Progress is not saved while previewing",
+	},
+}
+
+// msg returns the message in the given locale.
+func msg(locale, ja string) string {
+	if m, ok := messages[locale][ja]; ok {
+		return m
+	}
+	return ja
+}
+
+// locale picks the content locale for a request: the lang query parameter,
+// then the user's saved preference, then the Accept-Language header, and
+// finally Japanese. The user is the one in the path or the username query
+// parameter.
+func (h *Handler) locale(r *http.Request) string {
+	available := h.catalog().Locales()
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		if loc := matchLocale(lang, available); loc != "" {
+			return loc
+		}
+	}
+	username := r.PathValue("username")
+	if username == "" {
+		username = strings.TrimSpace(r.URL.Query().Get("username"))
+	}
+	if username != "" {
+		if loc, err := h.db.GetUserLocale(username); err == nil && slices.Contains(available, loc) {
+			return loc
+		}
+	}
+	if loc := negotiate(r.Header.Get("Accept-Language"), available); loc != "" {
+		return loc
+	}
+	return data.DefaultLocale
+}
+
+// localized returns the request's course in the request's locale, and the
+// locale.
+func (h *Handler) localized(r *http.Request) (*data.Store, string) {
+	lang := h.locale(r)
+	return h.content(r).Localized(lang), lang
+}
+
+// negotiate returns the available locale the Accept-Language header
+// prefers most, or "" if it accepts none of them.
+func negotiate(header string, available []string) string {
+	type pref struct {
+		tag string
+		q   float64
+	}
+	var prefs []pref
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if tag != "" && q > 0 {
+			prefs = append(prefs, pref{tag, q})
+		}
+	}
+	// A stable sort keeps the header's order among equal weights.
+	slices.SortStableFunc(prefs, func(a, b pref) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return 0
+	})
+	for _, p := range prefs {
+		if loc := matchLocale(p.tag, available); loc != "" {
+			return loc
+		}
+	}
+	return ""
+}
+
+// matchLocale matches a language tag such as "en-US" against the available
+// locales, exactly and then by its primary language.
+func matchLocale(tag string, available []string) string {
+	tag = strings.ToLower(tag)
+	for _, loc := range available {
+		if strings.ToLower(loc) == tag {
+			return loc
+		}
+	}
+	base, _, _ := strings.Cut(tag, "-")
+	for _, loc := range available {
+		if strings.ToLower(loc) == base {
+			return loc
+		}
+	}
+	return ""
+}
+
+// SetLocale saves the user's preferred content locale. An empty locale
+// clears the preference so the browser's languages decide again.
+func (h *Handler) SetLocale(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	var req struct {
+		Locale string `json:"locale"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	if req.Locale != "" && !slices.Contains(h.catalog().Locales(), req.Locale) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg(h.locale(r), "対応していない言語です")})
+		return
+	}
+
+	err := h.db.SetUserLocale(username, req.Locale)
+	if errors.Is(err, data.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "user not found"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save locale"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"locale": h.locale(r)})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"go-learning-app/data"
+)
+
+// maxPackageUpload bounds the size of an uploaded course package.
+const maxPackageUpload = 32 << 20
+
+// ExportCourse downloads a published course as a course package, or with
+// draft=true the course of the draft.
+func (h *Handler) ExportCourse(w http.ResponseWriter, r *http.Request) {
+	catalog, err := h.exported(r)
+	if err != nil {
+		log.Printf("load draft: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load draft"})
+		return
+	}
+	store, ok := catalog.Course(r.PathValue("course"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "course not found"})
+		return
+	}
+	var buf bytes.Buffer
+	if err := store.WritePackage(&buf); err != nil {
+		log.Printf("export course %s: %v", store.Course.ID, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to export course"})
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+store.Course.ID+`.zip"`)
+	w.Write(buf.Bytes())
+}
+
+// exported returns the content an export reads: the published content,
+// or the draft with the draft query parameter set to true.
+func (h *Handler) exported(r *http.Request) (*data.Catalog, error) {
+	if draft, _ := strconv.ParseBool(r.URL.Query().Get("draft")); draft {
+		return h.draft()
+	}
+	return h.catalog(), nil
+}
+
+// ImportCourse adds the course of an uploaded course package to the
+// draft. It responds with what the import changes; with dryRun=true
+// nothing is saved. A course with the same ID is only replaced with
+// replace=true, and otherwise reported with status 409.
+func (h *Handler) ImportCourse(w http.ResponseWriter, r *http.Request) {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+	replace, _ := strconv.ParseBool(r.URL.Query().Get("replace"))
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPackageUpload))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "package is too large"})
+		return
+	}
+	pkg, err := data.ReadPackage(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		var ce *data.ContentError
+		if !errors.As(err, &ce) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "not a course package"})
+			return
+		}
+		writeProblems(w, err)
+		return
+	}
+	// The draft keeps courses in the form Files writes, so the package is
+	// compared in that form too; files the loader does not read, such as
+	// assets, are kept as they are.
+	files, err := pkg.Store.Files()
+	if err != nil {
+		log.Printf("import course %s: %v", pkg.Manifest.ID, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to import course"})
+		return
+	}
+	canonical := *pkg
+	canonical.Files = files
+
+	h.admin.mu.Lock()
+	defer h.admin.mu.Unlock()
+
+	catalog, err := h.draft()
+	if err != nil {
+		log.Printf("load draft: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load draft"})
+		return
+	}
+	var currentFiles map[string][]byte
+	current, exists := catalog.Course(pkg.Manifest.ID)
+	if exists {
+		if currentFiles, err = current.Files(); err != nil {
+			log.Printf("import course %s: %v", pkg.Manifest.ID, err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to import course"})
+			return
+		}
+	}
+	plan := data.PlanImport(&canonical, current, currentFiles)
+	switch {
+	case dryRun:
+		writeJSON(w, http.StatusOK, plan)
+	case exists && !replace:
+		writeJSON(w, http.StatusConflict, map[string]any{"error": "course " + plan.Course.ID + " already exists", "plan": plan})
+	case h.saveDraft(w, catalog.With(pkg.Store)):
+		status := http.StatusCreated
+		if exists {
+			status = http.StatusOK
+		}
+		writeJSON(w, status, plan)
+	}
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-learning-app/analysis"
+	"go-learning-app/data"
+	"go-learning-app/grading"
+	"go-learning-app/models"
+)
+
+// recentAttempts is how many of a learner's latest attempts are checked
+// for correctly answered questions to avoid drawing again.
+const recentAttempts = 2
+
+// GetQuiz starts a quiz attempt for the user given in the username query
+// parameter. It draws questions from the lesson's pool, shuffles them with
+// a per-attempt seed and returns them without answers or explanations,
+// together with the scoring policy. It refuses once the policy's attempt
+// limit is used up, and while strict gating locks the lesson. A preview
+// starts no session, so its quiz cannot be submitted.
+func (h *Handler) GetQuiz(w http.ResponseWriter, r *http.Request) {
+	lessonID := r.PathValue("lessonId")
+	store, lang := h.localized(r)
+	quiz, ok := store.GetQuiz(lessonID)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "quiz not found"})
+		return
+	}
+	username := strings.TrimSpace(r.URL.Query().Get("username"))
+	if username == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg(lang, "ユーザー名を入力してください")})
+		return
+	}
+	if !h.checkUnlocked(w, r, lang, username, lessonID) {
+		return
+	}
+
+	used, err := h.courseDB(r).CountAttempts(username, lessonID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to start quiz"})
+		return
+	}
+	policy := grading.PolicyOf(quiz)
+	if policy.MaxAttempts > 0 && used >= policy.MaxAttempts {
+		writeJSON(w, http.StatusForbidden, map[string]any{
+			"error":  msg(lang, "受験回数の上限に達しました"),
+			"policy": policy,
+		})
+		return
+	}
+
+	recent, err := h.courseDB(r).RecentlyCorrect(username, lessonID, recentAttempts)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to start quiz"})
+		return
+	}
+	seed := rand.Int64()
+	ids := grading.Draw(quiz, quiz.Draw, recent, uint64(seed))
+	var sessionID int64
+	if !previewing(r) {
+		sessionID, err = h.courseDB(r).StartQuizSession(username, lessonID, quiz.Version, seed, ids)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to start quiz"})
+			return
+		}
+	}
+
+	pq := grading.Present(quiz, ids, uint64(seed)).Public()
+	pq.SessionID = sessionID
+	pq.AttemptsUsed = used
+	writeJSON(w, http.StatusOK, pq)
+}
+
+// SubmitQuizRequest is the request body for submitting quiz answers.
+// Option indexes refer to the shuffled order the session presented.
+type SubmitQuizRequest struct {
+	Username  string                     `json:"username"`
+	SessionID int64                      `json:"sessionId"`
+	Answers   map[string]json.RawMessage `json:"answers"`
+}
+
+// SubmitQuiz grades a quiz session on the server, records the attempt and
+// returns correctness and explanations. It is the only way to pass a quiz.
+func (h *Handler) SubmitQuiz(w http.ResponseWriter, r *http.Request) {
+	lessonID := r.PathValue("lessonId")
+	store, lang := h.localized(r)
+	quiz, ok := store.GetQuiz(lessonID)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "quiz not found"})
+		return
+	}
+
+	var req SubmitQuizRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	username := strings.TrimSpace(req.Username)
+	if username == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg(lang, "ユーザー名を入力してください")})
+		return
+	}
+
+	session, err := h.courseDB(r).GetQuizSession(req.SessionID)
+	if errors.Is(err, data.ErrNotFound) || (err == nil && (session.Username != username || session.LessonID != lessonID)) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "quiz session not found"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to load quiz session"})
+		return
+	}
+	if session.Submitted {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": msg(lang, "このクイズはすでに提出済みです")})
+		return
+	}
+
+	// Sessions started before the limit was reached still count against it.
+	if policy := grading.PolicyOf(quiz); policy.MaxAttempts > 0 {
+		used, err := h.courseDB(r).CountAttempts(username, lessonID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save attempt"})
+			return
+		}
+		if used >= policy.MaxAttempts {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": msg(lang, "受験回数の上限に達しました")})
+			return
+		}
+	}
+
+	attemptView := grading.Present(quiz, session.QuestionIDs, uint64(session.Seed))
+	result, err := grading.Grade(r.Context(), attemptView.Quiz, req.Answers)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to grade quiz"})
+		return
+	}
+	attempt := models.QuizAttempt{
+		SessionID:   session.ID,
+		Username:    username,
+		LessonID:    lessonID,
+		QuizVersion: session.QuizVersion,
+		Correct:     result.Correct,
+		Total:       result.Total,
+		Score:       result.Percent,
+		Passed:      result.Passed,
+		DurationMs:  max(time.Since(session.StartedAt).Milliseconds(), 0),
+	}
+	for _, q := range result.Questions {
+		attempt.Answers = append(attempt.Answers, models.AttemptAnswer{
+			QuestionID: q.QuestionID,
+			Answer:     attemptView.Canonical(q.QuestionID, q.Selected),
+			Correct:    q.Correct,
+		})
+	}
+	attemptID, err := h.courseDB(r).RecordAttempt(attempt)
+	if errors.Is(err, data.ErrAlreadySubmitted) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": msg(lang, "このクイズはすでに提出済みです")})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save attempt"})
+		return
+	}
+	if err := h.queueMissed(r, username, result); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update review queue"})
+		return
+	}
+
+	p, err := h.lessonProgress(r, username, lessonID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get progress"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"attemptId": attemptID,
+		"result":    result,
+		"lesson":    p,
+	})
+}
+
+// GetScores returns the best and latest quiz score per lesson for a user.
+func (h *Handler) GetScores(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+
+	scores, err := h.courseDB(r).GetScores(username)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get scores"})
+		return
+	}
+	if scores == nil {
+		scores = []models.LessonScores{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"scores": scores})
+}
+
+// GetAttempts returns a user's full attempt history for one lesson quiz.
+func (h *Handler) GetAttempts(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+	lessonID := r.PathValue("lessonId")
+
+	attempts, err := h.courseDB(r).GetAttempts(username, lessonID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get attempts"})
+		return
+	}
+	if attempts == nil {
+		attempts = []models.QuizAttempt{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"attempts": attempts})
+}
+
+// GetQuestionStats returns per-question correct rates across all learners,
+// hardest first. The optional lessonId query parameter limits it to one quiz.
+// It is registered behind RequireAdmin.
+func (h *Handler) GetQuestionStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.courseDB(r).GetQuestionStats(r.URL.Query().Get("lessonId"))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get stats"})
+		return
+	}
+	if stats == nil {
+		stats = []models.QuestionStats{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"questions": stats})
+}
+
+// GetItemAnalysis returns difficulty, discrimination and distractor counts
+// per question for quiz authors. The optional lessonId query parameter
+// limits it to one quiz. The option counts show the keyed answers, so it is
+// registered behind RequireAdmin.
+func (h *Handler) GetItemAnalysis(w http.ResponseWriter, r *http.Request) {
+	lessonID := r.URL.Query().Get("lessonId")
+	if _, ok := h.content(r).GetQuiz(lessonID); lessonID != "" && !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "quiz not found"})
+		return
+	}
+
+	items, err := analysis.Report(h.content(r), h.courseDB(r), lessonID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to analyze questions"})
+		return
+	}
+	if items == nil {
+		items = []analysis.Item{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-learning-app/data"
+)
+
+// reloadPing is how often an idle event stream sends a comment so proxies
+// do not close it.
+const reloadPing = 25 * time.Second
+
+// ReloadState is sent to browsers over the development event stream.
+type ReloadState struct {
+	// Version counts successful reloads. A browser reloads its content when
+	// it sees the version change.
+	Version int `json:"version"`
+	// Error is the problem with the content on disk, if the latest reload
+	// failed. The previous content is still being served.
+	Error string `json:"error,omitempty"`
+}
+
+// reloadHub fans reload states out to connected event streams.
+type reloadHub struct {
+	mu    sync.Mutex
+	state ReloadState
+	subs  map[chan ReloadState]struct{}
+}
+
+// EnableReload turns on development mode: GET /api/dev/events streams
+// reload states and Reload swaps in new content.
+func (h *Handler) EnableReload() {
+	h.reload = &reloadHub{subs: make(map[chan ReloadState]struct{})}
+}
+
+// Reload replaces the catalog with one freshly loaded from disk, or, when
+// loading failed, keeps the current catalog and reports err to browsers.
+func (h *Handler) Reload(catalog *data.Catalog, err error) {
+	hub := h.reload
+	if hub == nil {
+		return
+	}
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	if err != nil {
+		hub.state.Error = err.Error()
+		log.Printf("コンテンツの再読み込みに失敗しました:\n%v", err)
+	} else {
+		h.courses.Store(catalog)
+		hub.state.Version++
+		hub.state.Error = ""
+		log.Printf("コンテンツを再読み込みしました")
+	}
+	for ch := range hub.subs {
+		// Each stream only needs the latest state, so replace an unsent one.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- hub.state
+	}
+}
+
+func (hub *reloadHub) subscribe() (chan ReloadState, ReloadState) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	ch := make(chan ReloadState, 1)
+	hub.subs[ch] = struct{}{}
+	return ch, hub.state
+}
+
+func (hub *reloadHub) unsubscribe(ch chan ReloadState) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	delete(hub.subs, ch)
+}
+
+// DevEvents streams reload states as server-sent events, starting with the
+// current one. Outside development mode it answers 204, which tells the
+// browser's EventSource not to reconnect.
+func (h *Handler) DevEvents(w http.ResponseWriter, r *http.Request) {
+	if h.reload == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
+		return
+	}
+
+	ch, state := h.reload.subscribe()
+	defer h.reload.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(format string, args ...any) bool {
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+	event := func(state ReloadState) bool {
+		b, _ := json.Marshal(state)
+		return send("event: reload\ndata: %s\n\n", b)
+	}
+
+	if !event(state) {
+		return
+	}
+	ping := time.NewTicker(reloadPing)
+	defer ping.Stop()
+	for {
+		ok := true
+		select {
+		case <-r.Context().Done():
+			return
+		case state := <-ch:
+			ok = event(state)
+		case <-ping.C:
+			ok = send(": ping\n\n")
+		}
+		if !ok {
+			return
+		}
+	}
+}
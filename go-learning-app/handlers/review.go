@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-learning-app/data"
+	"go-learning-app/grading"
+	"go-learning-app/models"
+	"go-learning-app/review"
+)
+
+// noteDelay is how long after a lesson is completed its notes first come up
+// for review as flashcards.
+const noteDelay = 24 * time.Hour
+
+// ReviewCard is a due review item ready to show. Question items carry the
+// question without its answer; note items carry a flashcard front and back.
+type ReviewCard struct {
+	models.ReviewItem
+	LessonTitle string                 `json:"lessonTitle"`
+	Question    *models.PublicQuestion `json:"question,omitempty"`
+	Front       string                 `json:"front,omitempty"`
+	Back        string                 `json:"back,omitempty"`
+}
+
+// GetDueReviews returns the review cards due now for the user given in the
+// username query parameter. Notes of completed lessons are queued first.
+func (h *Handler) GetDueReviews(w http.ResponseWriter, r *http.Request) {
+	username := strings.TrimSpace(r.URL.Query().Get("username"))
+	store, lang := h.localized(r)
+	if username == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg(lang, "ユーザー名を入力してください")})
+		return
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	now := time.Now()
+	// A preview shows the queue as it is, without queueing the draft's notes.
+	if !previewing(r) {
+		if err := h.queueNotes(r, username, now); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to update review queue"})
+			return
+		}
+	}
+
+	items, total, err := h.courseDB(r).DueReviewItems(username, now, limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get review queue"})
+		return
+	}
+
+	cards := []ReviewCard{}
+	for _, it := range items {
+		if card, ok := reviewCard(store, lang, it); ok {
+			cards = append(cards, card)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"due":   total,
+		"cards": cards,
+	})
+}
+
+// GradeReviewRequest is the request body for grading a review. Question
+// items are graded from Answer; note flashcards are self-rated with Quality
+// on the SM-2 scale of 0 to 5.
+type GradeReviewRequest struct {
+	Username string          `json:"username"`
+	ItemID   string          `json:"itemId"`
+	Answer   json.RawMessage `json:"answer"`
+	Quality  *int            `json:"quality"`
+}
+
+// GradeReview grades one review of an item that is due and reschedules
+// the item.
+func (h *Handler) GradeReview(w http.ResponseWriter, r *http.Request) {
+	var req GradeReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+		return
+	}
+	username := strings.TrimSpace(req.Username)
+
+	item, err := h.courseDB(r).GetReviewItem(username, req.ItemID)
+	if errors.Is(err, data.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "review item not found"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get review item"})
+		return
+	}
+	// Grading an item again before it is due would push it further out
+	// each time.
+	now := time.Now()
+	if item.DueAt.After(now) {
+		_, lang := h.localized(r)
+		writeJSON(w, http.StatusConflict, map[string]string{"error": msg(lang, "この項目はまだ復習の時期ではありません")})
+		return
+	}
+
+	resp := map[string]any{}
+	var quality int
+	switch item.Kind {
+	case review.KindQuestion:
+		store, _ := h.localized(r)
+		quiz, ok := store.GetQuiz(item.LessonID)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "quiz not found"})
+			return
+		}
+		questionID := review.QuestionOf(item.ID)
+		attempt := grading.Present(quiz, []string{questionID}, reviewSeed(item))
+		if len(attempt.Quiz.Questions) == 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "question not found"})
+			return
+		}
+		result, err := grading.Grade(r.Context(), attempt.Quiz, map[string]json.RawMessage{questionID: req.Answer})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to grade review"})
+			return
+		}
+		qr := result.Questions[0]
+		quality = review.QualityAgain
+		if qr.Correct {
+			quality = review.QualityGood
+		}
+		resp["correct"] = qr.Correct
+		resp["answer"] = qr.Answer
+		resp["explanation"] = qr.Explanation
+	default:
+		if req.Quality == nil || *req.Quality < 0 || *req.Quality > 5 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "quality must be between 0 and 5"})
+			return
+		}
+		quality = *req.Quality
+	}
+
+	item = review.Schedule(item, quality, now)
+	if err := h.courseDB(r).SaveReviewItem(username, item); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to save review"})
+		return
+	}
+	resp["item"] = item
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// queueMissed puts the questions answered wrong in a quiz result into the
+// user's review queue, due immediately. The result may come from a lesson
+// quiz or an exam, so each question is filed under its own lesson.
+func (h *Handler) queueMissed(r *http.Request, username string, result grading.Result) error {
+	now := time.Now()
+	for _, q := range result.Questions {
+		if q.Correct {
+			continue
+		}
+		lessonID, ok := h.content(r).QuestionLesson(q.QuestionID)
+		if !ok {
+			continue
+		}
+		id := review.QuestionItemID(q.QuestionID)
+		item, err := h.courseDB(r).GetReviewItem(username, id)
+		switch {
+		case errors.Is(err, data.ErrNotFound):
+			item = review.New(id, review.KindQuestion, lessonID, now)
+		case err != nil:
+			return err
+		default:
+			item = review.Lapse(item, now)
+		}
+		if err := h.courseDB(r).SaveReviewItem(username, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queueNotes adds the notes of the user's completed lessons to the review
+// queue as flashcards. Notes that are already queued keep their schedule.
+func (h *Handler) queueNotes(r *http.Request, username string, now time.Time) error {
+	_, lessons, err := h.progress(r, username)
+	if err != nil {
+		return err
+	}
+
+	var items []models.ReviewItem
+	for _, p := range lessons {
+		if !p.Completed {
+			continue
+		}
+		lesson, ok := h.content(r).GetLesson(p.LessonID)
+		if !ok {
+			continue
+		}
+		for i := range lesson.Notes {
+			items = append(items, review.New(
+				review.NoteItemID(lesson.ID, i), review.KindNote, lesson.ID, now.Add(noteDelay),
+			))
+		}
+	}
+	return h.courseDB(r).AddReviewItems(username, items)
+}
+
+// reviewCard builds the card for a due item from the store in the given
+// locale. It reports false if the content behind the item no longer exists.
+func reviewCard(store *data.Store, lang string, it models.ReviewItem) (ReviewCard, bool) {
+	lesson, ok := store.GetLesson(it.LessonID)
+	if !ok {
+		return ReviewCard{}, false
+	}
+	card := ReviewCard{ReviewItem: it, LessonTitle: lesson.Title}
+
+	switch it.Kind {
+	case review.KindQuestion:
+		quiz, ok := store.GetQuiz(it.LessonID)
+		if !ok {
+			return ReviewCard{}, false
+		}
+		questionID := review.QuestionOf(it.ID)
+		pub := grading.Present(quiz, []string{questionID}, reviewSeed(it)).Public()
+		if len(pub.Questions) == 0 {
+			return ReviewCard{}, false
+		}
+		card.Question = &pub.Questions[0]
+	case review.KindNote:
+		index, ok := review.NoteIndexOf(it.ID)
+		if !ok || index >= len(lesson.Notes) {
+			return ReviewCard{}, false
+		}
+		card.Front = fmt.Sprintf(msg(lang, "%s のポイントを思い出してください"), lesson.Title)
+		card.Back = lesson.Notes[index]
+	default:
+		return ReviewCard{}, false
+	}
+	return card, true
+}
+
+// reviewSeed fixes the option order of a question card until the item is
+// rescheduled, so grading sees the same order the learner did.
+func reviewSeed(it models.ReviewItem) uint64 {
+	return uint64(it.DueAt.Unix())
+}
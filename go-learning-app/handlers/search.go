@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-learning-app/search"
+)
+
+// SearchResult is a matching lesson section with where to find it.
+type SearchResult struct {
+	search.Result
+	LessonTitle string `json:"lessonTitle"`
+	ChapterID   int    `json:"chapterId"`
+	// URL is the app route that opens the lesson at the section.
+	URL string `json:"url"`
+}
+
+// Search returns the lesson sections matching the q query parameter in the
+// request's locale, ranked best first. limit caps the results at 50.
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	store, lang := h.localized(r)
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg(lang, "検索語を入力してください")})
+		return
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	results := []SearchResult{}
+	for _, res := range store.Search(q, limit) {
+		lesson, ok := store.GetLesson(res.LessonID)
+		if !ok {
+			continue
+		}
+		results = append(results, SearchResult{
+			Result:      res,
+			LessonTitle: lesson.Title,
+			ChapterID:   lesson.ChapterID,
+			URL:         "#lesson/" + lesson.ID + "/" + res.Section,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"query": q, "results": results})
+}
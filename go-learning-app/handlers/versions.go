@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-learning-app/models"
+)
+
+// GetChangelog returns a lesson's changelog, newest first, with the
+// current versions of the lesson and its quiz.
+func (h *Handler) GetChangelog(w http.ResponseWriter, r *http.Request) {
+	store, _ := h.localized(r)
+	lesson, ok := store.GetLesson(r.PathValue("id"))
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "lesson not found"})
+		return
+	}
+	quiz, _ := store.GetQuiz(lesson.ID)
+	changelog := lesson.Changelog
+	if changelog == nil {
+		changelog = []models.ChangelogEntry{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"lessonId":    lesson.ID,
+		"version":     lesson.Version,
+		"quizVersion": quiz.Version,
+		"changelog":   changelog,
+	})
+}
+
+// GetUpdatedLessons lists the lessons a user has completed that were
+// rewritten since, with the changelog entries made after they completed
+// them. Reading the lesson again, or passing its new quiz, clears it.
+func (h *Handler) GetUpdatedLessons(w http.ResponseWriter, r *http.Request) {
+	store, _ := h.localized(r)
+	_, lessons, err := h.progress(r, r.PathValue("username"))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get progress"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"lessons": store.UpdatedLessons(lessons)})
+}
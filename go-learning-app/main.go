@@ -1,21 +1,43 @@
 package main
 
 import (
+	"context"
 	"embed"
+	"errors"
+	"flag"
 	"fmt"
 	"io/fs"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"go-learning-app/data"
 	"go-learning-app/handlers"
+	"go-learning-app/watch"
 )
 
 //go:embed static/*
 var staticFiles embed.FS
 
+// reloadInterval is how often development mode checks content for changes.
+const reloadInterval = 500 * time.Millisecond
+
 func main() {
+	// Subcommands such as "items" run and exit instead of serving.
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	dev := flag.Bool("dev", false, "serve content from -content and reload it when files change")
+	contentDir := flag.String("content", "content", "content directory watched in -dev mode")
+	flag.Parse()
+
 	// Initialize SQLite database
 	db, err := data.NewDB(data.DBPath())
 	if err != nil {
@@ -23,22 +45,117 @@ func main() {
 	}
 	defer db.Close()
 
-	store := data.NewStore()
-	h := handlers.New(store, db)
+	// Content published through the admin API replaces the built-in content.
+	catalog, err := data.Load(db.Source(data.StatePublished))
+	if errors.Is(err, data.ErrNotFound) {
+		catalog, err = data.NewCatalog()
+	}
+	if err != nil {
+		log.Fatalf("コンテンツの読み込みに失敗しました:\n%v", err)
+	}
+	for _, s := range catalog.Courses() {
+		if err := db.Course(s.Course.ID).StampLegacyProgress(s.Versions()); err != nil {
+			log.Fatalf("進捗のバージョンの記録に失敗しました: %v", err)
+		}
+	}
+	h := handlers.New(catalog, db)
+	if token := os.Getenv("ADMIN_TOKEN"); token != "" {
+		h.EnableAdmin(token)
+	}
+	if *dev {
+		// Start from the files on disk; if they are broken, the built-in
+		// content is served and the error shown until they are fixed.
+		h.EnableReload()
+		load := func() { h.Reload(data.LoadCatalog(os.DirFS(*contentDir))) }
+		load()
+		go watch.Poll(context.Background(), *contentDir, reloadInterval, load)
+		fmt.Printf("開発モード: %s の変更を監視しています\n", *contentDir)
+	}
 
 	mux := http.NewServeMux()
 
-	// API routes
-	mux.HandleFunc("GET /api/chapters", h.GetChapters)
-	mux.HandleFunc("GET /api/lessons/{id}", h.GetLesson)
-	mux.HandleFunc("GET /api/quiz/{lessonId}", h.GetQuiz)
+	// Course catalog and routes shared by all courses
+	mux.HandleFunc("GET /api/courses", h.GetCourses)
 	mux.HandleFunc("POST /api/run", h.RunCode)
+	mux.HandleFunc("POST /api/login", h.Login)
+
+	// Standard library documentation from the installed toolchain
+	mux.HandleFunc("GET /api/doc/{ref...}", h.GetDoc)
+
+	// Content language preference
+	mux.HandleFunc("PUT /api/users/{username}/locale", h.SetLocale)
+
+	// API routes, one set per course
+	course := func(pattern string, handler http.HandlerFunc) {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.HandleFunc(method+" /api/courses/{course}"+path, h.InCourse(handler))
+	}
+	course("GET /chapters", h.GetChapters)
+	course("GET /lessons/{id}", h.GetLesson)
+	course("GET /lessons/{id}/changelog", h.GetChangelog)
+	course("GET /quiz/{lessonId}", h.GetQuiz)
+	course("POST /quiz/{lessonId}/submit", h.SubmitQuiz)
+	course("GET /search", h.Search)
+	course("GET /glossary", h.GetGlossary)
+	course("GET /glossary/{id}", h.GetGlossaryTerm)
 
 	// Progress API routes
-	mux.HandleFunc("POST /api/login", h.Login)
-	mux.HandleFunc("GET /api/progress/{username}", h.GetProgress)
-	mux.HandleFunc("POST /api/progress/{username}/{lessonId}", h.MarkProgress)
-	mux.HandleFunc("DELETE /api/progress/{username}", h.ResetProgress)
+	course("GET /progress/{username}", h.GetProgress)
+	course("GET /progress/{username}/updated", h.GetUpdatedLessons)
+	course("POST /progress/{username}/{lessonId}/read", h.MarkRead)
+	course("POST /progress/{username}/{lessonId}/exercise", h.SubmitExercise)
+	course("POST /progress/{username}/{lessonId}/examples/{index}", h.MarkExampleRun)
+	course("DELETE /progress/{username}", h.ResetProgress)
+
+	// Quiz attempt history
+	course("GET /attempts/{username}", h.GetScores)
+	course("GET /attempts/{username}/{lessonId}", h.GetAttempts)
+
+	// Question statistics reveal the answer keys, so only authors see them
+	course("GET /stats/questions", h.RequireAdmin(h.GetQuestionStats))
+	course("GET /stats/items", h.RequireAdmin(h.GetItemAnalysis))
+
+	// Spaced-repetition review
+	course("GET /review/due", h.GetDueReviews)
+	course("POST /review/grade", h.GradeReview)
+
+	// Timed chapter and final exams
+	course("GET /exams", h.GetExams)
+	course("POST /exams/{examId}/start", h.StartExam)
+	course("GET /exams/sessions/{id}", h.GetExamSession)
+	course("PUT /exams/sessions/{id}/answers", h.SaveExamAnswers)
+	course("POST /exams/sessions/{id}/submit", h.SubmitExam)
+
+	// Authoring: edit the draft content, preview and publish it
+	mux.HandleFunc("GET /api/admin/content", h.RequireAdmin(h.GetAdminContent))
+	mux.HandleFunc("POST /api/admin/courses", h.RequireAdmin(h.CreateCourse))
+	mux.HandleFunc("PUT /api/admin/courses/{course}", h.RequireAdmin(h.UpdateCourse))
+	mux.HandleFunc("DELETE /api/admin/courses/{course}", h.RequireAdmin(h.DeleteCourse))
+	mux.HandleFunc("POST /api/admin/courses/import", h.RequireAdmin(h.ImportCourse))
+	mux.HandleFunc("GET /api/admin/courses/{course}/package", h.RequireAdmin(h.ExportCourse))
+	mux.HandleFunc("GET /api/admin/courses/{course}/book", h.RequireAdmin(h.ExportBook))
+	admin := func(pattern string, handler http.HandlerFunc) {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.HandleFunc(method+" /api/admin/courses/{course}"+path, h.RequireAdmin(handler))
+	}
+	admin("POST /chapters", h.CreateChapter)
+	admin("PUT /chapters/{id}", h.UpdateChapter)
+	admin("DELETE /chapters/{id}", h.DeleteChapter)
+	admin("PUT /chapters/{id}/order", h.OrderLessons)
+	admin("POST /lessons", h.CreateLesson)
+	admin("GET /lessons/{id}", h.GetAdminLesson)
+	admin("PUT /lessons/{id}", h.UpdateLesson)
+	admin("DELETE /lessons/{id}", h.DeleteLesson)
+	admin("PUT /lessons/{id}/quiz", h.PutQuiz)
+	admin("DELETE /lessons/{id}/quiz", h.DeleteQuiz)
+	admin("POST /lessons/{id}/quiz/questions", h.CreateQuestion)
+	admin("PUT /lessons/{id}/quiz/questions/{questionId}", h.UpdateQuestion)
+	admin("DELETE /lessons/{id}/quiz/questions/{questionId}", h.DeleteQuestion)
+	mux.HandleFunc("POST /api/admin/publish", h.RequireAdmin(h.Publish))
+	mux.HandleFunc("DELETE /api/admin/draft", h.RequireAdmin(h.DiscardDraft))
+
+	// Content reload events in development mode
+	mux.HandleFunc("GET /api/dev/events", h.DevEvents)
 
 	// Static files
 	staticFS, err := fs.Sub(staticFiles, "static")
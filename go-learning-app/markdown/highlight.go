@@ -0,0 +1,217 @@
+package markdown
+
+import (
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// builtins are the predeclared identifiers highlighted as builtins.
+var builtins = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true,
+	"copy": true, "delete": true, "imag": true, "len": true, "make": true,
+	"max": true, "min": true, "new": true, "panic": true, "print": true,
+	"println": true, "real": true, "recover": true,
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true,
+	"complex128": true, "error": true, "float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"rune": true, "string": true, "uint": true, "uint8": true, "uint16": true,
+	"uint32": true, "uint64": true, "uintptr": true,
+}
+
+// DocLink returns the href of the documentation of a standard library
+// package, or of one of its declarations if name is not empty. It returns
+// "" for packages that are not documented, which are left unlinked.
+type DocLink func(path, name string) string
+
+// HighlightGo returns Go source as escaped HTML, with tokens wrapped in
+// spans of the classes Prism uses, so highlighted lesson text and the
+// examples highlighted in the browser share one stylesheet. Source that
+// does not scan cleanly is still highlighted as far as it goes.
+//
+// With a non-nil docLink, references to the standard library are linked
+// to its documentation: the packages the source imports and the exported
+// names used through them, and calls of builtin functions. In fragments
+// without import declarations, names such as fmt.Println are linked if
+// the standard library has a package of that name.
+func HighlightGo(src string, docLink DocLink) string {
+	type scanned struct {
+		start, end int
+		tok        token.Token
+		lit        string
+	}
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, []byte(src), nil, scanner.ScanComments)
+	var toks []scanned
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		// Semicolons the scanner inserts at line ends are not in the text.
+		if tok == token.SEMICOLON && lit != ";" {
+			continue
+		}
+		start := file.Offset(pos)
+		end := start + len(tok.String())
+		if lit != "" {
+			end = start + len(lit)
+		}
+		if end > len(src) {
+			continue
+		}
+		toks = append(toks, scanned{start, end, tok, lit})
+	}
+
+	// links maps the offsets of linked tokens to their hrefs.
+	links := make(map[int]string)
+	if docLink != nil {
+		imports := stdImports(src, links, docLink)
+		fragment := !slices.ContainsFunc(toks, func(t scanned) bool { return t.tok == token.IMPORT })
+		for i, t := range toks {
+			if t.tok != token.IDENT || i > 0 && toks[i-1].tok == token.PERIOD {
+				continue
+			}
+			next := func(k int) scanned {
+				if i+k < len(toks) {
+					return toks[i+k]
+				}
+				return scanned{tok: token.EOF}
+			}
+			sel := next(2)
+			qualified := next(1).tok == token.PERIOD && sel.tok == token.IDENT && token.IsExported(sel.lit)
+			path, ok := imports[t.lit]
+			if !ok && fragment && qualified && docLink(t.lit, "") != "" {
+				// With no imports to go by, a fragment's qualified names
+				// are taken to refer to the package of that name.
+				path, ok = t.lit, true
+			}
+			if ok {
+				links[t.start] = docLink(path, "")
+				if qualified {
+					links[sel.start] = docLink(path, sel.lit)
+				}
+			} else if builtins[t.lit] && next(1).tok == token.LPAREN {
+				links[t.start] = docLink("builtin", t.lit)
+			}
+		}
+	}
+
+	var b strings.Builder
+	last := 0
+	span := func(class string, t scanned) {
+		b.WriteString(html.EscapeString(src[last:t.start]))
+		href, linked := links[t.start]
+		if linked {
+			b.WriteString(`<a class="doc-link" href="` + html.EscapeString(href) + `">`)
+		}
+		if class != "" {
+			b.WriteString(`<span class="token ` + class + `">`)
+		}
+		b.WriteString(html.EscapeString(src[t.start:t.end]))
+		if class != "" {
+			b.WriteString(`</span>`)
+		}
+		if linked {
+			b.WriteString(`</a>`)
+		}
+		last = t.end
+	}
+	for _, t := range toks {
+		if t.start < last {
+			continue
+		}
+		tok, lit := t.tok, t.lit
+		switch {
+		case tok == token.COMMENT:
+			span("comment", t)
+		case tok == token.STRING || tok == token.CHAR:
+			span("string", t)
+		case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+			span("number", t)
+		case tok.IsKeyword():
+			span("keyword", t)
+		case tok == token.IDENT:
+			switch {
+			case lit == "true" || lit == "false":
+				span("boolean", t)
+			case lit == "nil":
+				span("keyword", t)
+			case strings.HasPrefix(strings.TrimLeft(src[t.end:], " \t"), "("):
+				if builtins[lit] {
+					span("builtin", t)
+				} else {
+					span("function", t)
+				}
+			case builtins[lit]:
+				span("builtin", t)
+			default:
+				if _, ok := links[t.start]; ok {
+					span("", t)
+				}
+			}
+		case tok.IsOperator():
+			class := "operator"
+			switch tok {
+			case token.LPAREN, token.RPAREN, token.LBRACK, token.RBRACK,
+				token.LBRACE, token.RBRACE, token.COMMA, token.PERIOD,
+				token.SEMICOLON, token.COLON:
+				class = "punctuation"
+			}
+			span(class, t)
+		}
+	}
+	b.WriteString(html.EscapeString(src[last:]))
+	return b.String()
+}
+
+// stdImports returns the standard library packages the source imports,
+// by the names it refers to them with, and links their import paths.
+func stdImports(src string, links map[int]string, docLink DocLink) map[string]string {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ImportsOnly)
+	shift := 0
+	if err != nil {
+		// Fragments may start with their imports, leaving out the package
+		// clause; one is put on the same line so offsets only shift.
+		const clause = "package p; "
+		f, err = parser.ParseFile(fset, "", clause+src, parser.ImportsOnly)
+		if err != nil {
+			return nil
+		}
+		shift = len(clause)
+	}
+	imports := make(map[string]string)
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || docLink(path, "") == "" {
+			continue
+		}
+		links[fset.Position(spec.Path.Pos()).Offset-shift] = docLink(path, "")
+		name := pathName(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name != "_" && name != "." {
+			imports[name] = path
+		}
+	}
+	return imports
+}
+
+// pathName returns the name of the package at a standard library path:
+// its last element, skipping a major version suffix such as v2.
+func pathName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	return name
+}
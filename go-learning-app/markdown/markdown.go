@@ -0,0 +1,226 @@
+// Package markdown renders lesson text to HTML that is safe to insert into
+// the page. Text is CommonMark with GitHub's tables, strikethrough and
+// autolinks; inline HTML such as <code> is allowed but passes through the
+// same allowlist as everything else, so authors cannot inject scripts,
+// event handlers or styles. Fenced Go code is highlighted on the server
+// and can link to the standard library's documentation, and headings get
+// IDs and anchor links for a table of contents.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Heading is an entry of a table of contents.
+type Heading struct {
+	Level int `json:"level"`
+	// ID is the heading's slug, unique within the rendered text; the
+	// heading element's id is the options' IDPrefix followed by it.
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Options configures Render.
+type Options struct {
+	// IDPrefix is put before heading IDs in the HTML, so they cannot clash
+	// with the IDs of the page around the text.
+	IDPrefix string
+	// Link returns the href of a heading's anchor link, given its ID. Nil
+	// leaves headings without anchor links.
+	Link func(id string) string
+	// Terms are linked where they first occur in the text, outside
+	// headings, links and code.
+	Terms []Term
+	// DocLink links references to the standard library in fenced Go code
+	// to its documentation; see HighlightGo. Nil leaves code unlinked.
+	DocLink DocLink
+}
+
+// Term is a word to link, such as a glossary entry.
+type Term struct {
+	// Words are the forms of the term; the first occurrence of any of them
+	// is linked.
+	Words []string
+	Href  string
+	// Title is shown when hovering over the link.
+	Title string
+}
+
+// newMarkdown returns the Markdown converter for one text, whose fenced Go
+// code links to the standard library with docLink.
+func newMarkdown(docLink DocLink) goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithExtensions(
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Strikethrough,
+			extension.Linkify,
+		),
+		goldmark.WithRendererOptions(
+			// Raw HTML is rendered here and cleaned by the policy afterwards.
+			html.WithUnsafe(),
+			renderer.WithNodeRenderers(util.Prioritized(codeRenderer{docLink: docLink}, 100)),
+		),
+	)
+}
+
+// policy is the allowlist every rendered text passes through: the usual
+// formatting elements, and only the classes and IDs this package writes.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// Lesson text is written by the course's authors, so only links
+	// leaving the site are marked nofollow.
+	p.RequireNoFollowOnLinks(false)
+	p.RequireNoFollowOnFullyQualifiedLinks(true)
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w-]+$`)).OnElements("pre", "code")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^token [a-z]+$`)).OnElements("span")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^(heading-anchor|glossary-term|doc-link)$`)).OnElements("a")
+	// Glossary links show the definition, which has any punctuation.
+	p.AllowAttrs("title").OnElements("a")
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	return p
+}()
+
+// Render renders Markdown to sanitized HTML and returns the headings it
+// contains, in order.
+func Render(src string, opts Options) (string, []Heading) {
+	source := []byte(src)
+	md := newMarkdown(opts.DocLink)
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	var toc []Heading
+	seen := make(map[string]int)
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		title := plainText(h, source)
+		id := slug(title)
+		if seen[id]++; seen[id] > 1 {
+			id += "-" + strconv.Itoa(seen[id])
+		}
+		h.SetAttributeString("id", []byte(opts.IDPrefix+id))
+		if opts.Link != nil {
+			a := ast.NewLink()
+			a.Destination = []byte(opts.Link(id))
+			a.SetAttributeString("class", []byte("heading-anchor"))
+			a.AppendChild(a, ast.NewString([]byte("#")))
+			h.AppendChild(h, a)
+		}
+		toc = append(toc, Heading{Level: h.Level, ID: id, Title: title})
+		return ast.WalkSkipChildren, nil
+	})
+	linkTerms(doc, source, opts.Terms)
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, source, doc); err != nil {
+		// Rendering only fails if writing to buf does, which it cannot.
+		panic(err)
+	}
+	return policy.Sanitize(buf.String()), toc
+}
+
+// RenderInline renders a single line of Markdown, such as a note or an
+// exercise description, without wrapping it in a paragraph.
+func RenderInline(src string) string {
+	out, _ := Render(src, Options{})
+	out = strings.TrimSpace(out)
+	if inner, ok := strings.CutPrefix(out, "<p>"); ok && strings.Count(out, "<p>") == 1 {
+		out = strings.TrimSuffix(inner, "</p>")
+	}
+	return out
+}
+
+// plainText returns the text of a node without markup.
+func plainText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch c := c.(type) {
+		case *ast.Text:
+			b.Write(c.Value(source))
+		case *ast.String:
+			b.Write(c.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(b.String())
+}
+
+// slug makes a heading ID from its text: letters and digits in any
+// script, lowercased, with everything else collapsed to single dashes.
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		return "section"
+	}
+	return b.String()
+}
+
+// codeRenderer renders fenced code blocks, highlighting Go.
+type codeRenderer struct {
+	docLink DocLink
+}
+
+func (r codeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderCode)
+	reg.Register(ast.KindCodeBlock, r.renderCode)
+}
+
+func (r codeRenderer) renderCode(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var code bytes.Buffer
+	lines := n.Lines()
+	for i := range lines.Len() {
+		seg := lines.At(i)
+		code.Write(seg.Value(source))
+	}
+	lang := ""
+	if f, ok := n.(*ast.FencedCodeBlock); ok {
+		lang = string(f.Language(source))
+	}
+	switch lang {
+	case "go":
+		w.WriteString(`<pre class="language-go"><code class="language-go">`)
+		w.WriteString(HighlightGo(code.String(), r.docLink))
+	case "":
+		w.WriteString(`<pre><code>`)
+		w.Write(util.EscapeHTML(code.Bytes()))
+	default:
+		class := `language-` + string(util.EscapeHTML([]byte(lang)))
+		w.WriteString(`<pre class="` + class + `"><code class="` + class + `">`)
+		w.Write(util.EscapeHTML(code.Bytes()))
+	}
+	w.WriteString("</code></pre>\n")
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,138 @@
+package markdown
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// codeTag matches the inline HTML tags whose content is not linked.
+var codeTag = regexp.MustCompile(`(?i)<(/?)(code|kbd|pre|a)\b`)
+
+// linkTerms links the first occurrence of each term. Longer terms are
+// placed first, so a term is not linked inside a longer one that contains
+// it.
+func linkTerms(doc ast.Node, source []byte, terms []Term) {
+	order := make([]int, len(terms))
+	for i := range order {
+		order[i] = i
+	}
+	longest := func(t Term) int {
+		n := 0
+		for _, w := range t.Words {
+			n = max(n, len(w))
+		}
+		return n
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return longest(terms[b]) - longest(terms[a]) })
+
+	for _, i := range order {
+		t := terms[i]
+		var found *ast.Text
+		var start, end int
+		walkLinkable(doc, source, func(n *ast.Text) bool {
+			value := n.Value(source)
+			start = -1
+			for _, w := range t.Words {
+				if w == "" {
+					continue
+				}
+				j := Index(string(value), w)
+				if j >= 0 && (start < 0 || j < start || j == start && len(w) > end-start) {
+					start, end = j, j+len(w)
+				}
+			}
+			if start < 0 {
+				return true
+			}
+			found = n
+			return false
+		})
+		if found != nil {
+			split(found, start, end, t)
+		}
+	}
+}
+
+// walkLinkable calls fn for each text node that may be linked, in document
+// order, until fn returns false.
+func walkLinkable(doc ast.Node, source []byte, fn func(*ast.Text) bool) {
+	// depth counts the inline code and link tags written as raw HTML that
+	// are open around the current node.
+	depth := 0
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Heading, *ast.Link, *ast.AutoLink, *ast.CodeSpan, *ast.Image:
+			return ast.WalkSkipChildren, nil
+		case *ast.Paragraph, *ast.TextBlock:
+			depth = 0
+		case *ast.RawHTML:
+			for i := range n.Segments.Len() {
+				seg := n.Segments.At(i)
+				for _, m := range codeTag.FindAllSubmatch(seg.Value(source), -1) {
+					if len(m[1]) == 0 {
+						depth++
+					} else if depth > 0 {
+						depth--
+					}
+				}
+			}
+		case *ast.Text:
+			if depth == 0 && !fn(n) {
+				return ast.WalkStop, nil
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// split turns the text from start to end of n into a link to t.
+func split(n *ast.Text, start, end int, t Term) {
+	parent := n.Parent()
+	seg := n.Segment
+	if start > 0 {
+		parent.InsertBefore(parent, n, ast.NewTextSegment(text.NewSegment(seg.Start, seg.Start+start)))
+	}
+	link := ast.NewLink()
+	link.Destination = []byte(t.Href)
+	if t.Title != "" {
+		link.Title = []byte(t.Title)
+	}
+	link.SetAttributeString("class", []byte("glossary-term"))
+	link.AppendChild(link, ast.NewTextSegment(text.NewSegment(seg.Start+start, seg.Start+end)))
+	parent.InsertBefore(parent, n, link)
+	// n keeps the rest of the text and its line break.
+	n.Segment = text.NewSegment(seg.Start+end, seg.Stop)
+}
+
+// Index returns the index of the first occurrence of word in s, or -1.
+// Words written in Latin letters or digits only match whole, so "slice"
+// is not found in "slices"; other words, such as Japanese ones, match
+// anywhere.
+func Index(s, word string) int {
+	if word == "" {
+		return -1
+	}
+	for i := 0; ; {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return -1
+		}
+		start, end := i+j, i+j+len(word)
+		if !(isWordByte(word[0]) && start > 0 && isWordByte(s[start-1])) &&
+			!(isWordByte(word[len(word)-1]) && end < len(s) && isWordByte(s[end])) {
+			return start
+		}
+		i = start + 1
+	}
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
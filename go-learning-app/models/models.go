@@ -1,54 +1,483 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
+// Course is a course of chapters hosted alongside others. Chapter, lesson,
+// question and exam IDs are only unique within a course.
+type Course struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Order places the course in the catalog; ties are broken by ID.
+	Order int `json:"order"`
+}
+
+// CourseSummary is a course as the catalog lists it. Completed is only
+// set when the catalog is listed for a user.
+type CourseSummary struct {
+	Course
+	Chapters  int  `json:"chapters"`
+	Lessons   int  `json:"lessons"`
+	Completed *int `json:"completed,omitempty"`
+}
+
 // Chapter represents a learning chapter containing multiple lessons.
 type Chapter struct {
 	ID          int             `json:"id"`
 	Title       string          `json:"title"`
 	Description string          `json:"description"`
 	Lessons     []LessonSummary `json:"lessons"`
+	// Policy is the default scoring policy of the chapter's quizzes.
+	Policy *ScoringPolicy `json:"policy,omitempty"`
+	// Gating is how the prerequisites of the chapter's lessons are
+	// enforced: GatingStrict or GatingAdvisory.
+	Gating string `json:"gating"`
 }
 
+// Prerequisite gating modes. Strict gating locks a lesson until its
+// prerequisites are complete; advisory gating only recommends them.
+const (
+	GatingStrict   = "strict"
+	GatingAdvisory = "advisory"
+)
+
 // LessonSummary is a brief view of a lesson used in chapter listings.
 type LessonSummary struct {
 	ID    string `json:"id"`
 	Title string `json:"title"`
+	// Requires lists the lessons to complete before this one.
+	Requires []string `json:"requires,omitempty"`
+	// Missing and Locked are set when chapters are listed for a learner:
+	// the prerequisites not yet complete, and whether strict gating keeps
+	// the lesson closed because of them.
+	Missing []string `json:"missing,omitempty"`
+	Locked  bool     `json:"locked,omitempty"`
 }
 
 // Lesson is the full lesson content including code examples and notes.
 type Lesson struct {
-	ID           string        `json:"id"`
-	ChapterID    int           `json:"chapterId"`
-	Title        string        `json:"title"`
-	Content      string        `json:"content"`
+	ID        string `json:"id"`
+	ChapterID int    `json:"chapterId"`
+	Title     string `json:"title"`
+	// Content is the lesson text in Markdown, as written.
+	Content string `json:"content"`
+	// ContentHTML is Content rendered to sanitized HTML, with IDs and
+	// anchor links on its headings, which TOC lists.
+	ContentHTML  string        `json:"contentHtml"`
+	TOC          []Heading     `json:"toc,omitempty"`
 	CodeExamples []CodeExample `json:"codeExamples"`
 	Notes        []string      `json:"notes,omitempty"`
-	Exercise     *Exercise     `json:"exercise,omitempty"`
+	// NotesHTML are the notes rendered like ContentHTML, one per note.
+	NotesHTML []string  `json:"notesHtml,omitempty"`
+	Exercise  *Exercise `json:"exercise,omitempty"`
+	// Requires lists the lessons to complete before this one.
+	Requires []string `json:"requires,omitempty"`
+	// Completion is the rule for when the lesson counts as complete.
+	// Lessons that leave it nil use DefaultCompletionRule.
+	Completion *CompletionRule `json:"completion,omitempty"`
+	// Version is a hash of the lesson's Japanese text and code. It changes
+	// whenever the lesson is rewritten; translations share it.
+	Version string `json:"version"`
+	// Changelog lists the changes made to the lesson and its quiz, newest
+	// first. It is served separately from the lesson.
+	Changelog []ChangelogEntry `json:"-"`
+}
+
+// Heading is a heading of a lesson's text, as listed in its table of
+// contents. The heading's element ID is "section-heading-" followed by ID.
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// ChangelogEntry describes one revision of a lesson for learners.
+type ChangelogEntry struct {
+	// Date is the day of the change, as YYYY-MM-DD.
+	Date string `json:"date"`
+	Note string `json:"note"`
+}
+
+// CompletionRule lists the components a learner must finish for a lesson
+// to count as complete.
+type CompletionRule struct {
+	Read     bool `json:"read"`
+	Quiz     bool `json:"quiz"`
+	Exercise bool `json:"exercise"`
+	Examples bool `json:"examples"`
+}
+
+// DefaultCompletionRule requires reading the lesson and passing its quiz.
+var DefaultCompletionRule = CompletionRule{Read: true, Quiz: true}
+
+// Satisfied reports whether p meets every component the rule requires.
+func (r CompletionRule) Satisfied(p LessonProgress) bool {
+	if r.Read && !p.Read {
+		return false
+	}
+	if r.Quiz && !p.QuizPassed {
+		return false
+	}
+	if r.Exercise && !p.ExercisePassed {
+		return false
+	}
+	if r.Examples && p.ExamplesRun < p.ExamplesTotal {
+		return false
+	}
+	return true
+}
+
+// LessonProgress is a learner's per-component progress on one lesson.
+type LessonProgress struct {
+	LessonID       string         `json:"lessonId"`
+	Read           bool           `json:"read"`
+	QuizPassed     bool           `json:"quizPassed"`
+	QuizScore      *int           `json:"quizScore,omitempty"`
+	ExercisePassed bool           `json:"exercisePassed"`
+	ExamplesRun    int            `json:"examplesRun"`
+	ExamplesTotal  int            `json:"examplesTotal"`
+	Rule           CompletionRule `json:"rule"`
+	Completed      bool           `json:"completed"`
+	// LessonVersion and QuizVersion are the versions of the lesson and its
+	// quiz the learner last read and passed, and VersionAt when the older
+	// of them was recorded. They are empty for progress recorded before
+	// content was versioned.
+	LessonVersion string    `json:"-"`
+	QuizVersion   string    `json:"-"`
+	VersionAt     time.Time `json:"-"`
+	// Updated is set on a completed lesson whose content or quiz has
+	// changed since the learner completed it.
+	Updated bool `json:"updated,omitempty"`
+}
+
+// LessonUpdate is a completed lesson that changed after the learner
+// completed it.
+type LessonUpdate struct {
+	LessonID  string `json:"lessonId"`
+	ChapterID int    `json:"chapterId"`
+	Title     string `json:"title"`
+	// LessonChanged and QuizChanged tell which part changed: the lesson
+	// the learner read or the quiz they passed.
+	LessonChanged bool `json:"lessonChanged"`
+	QuizChanged   bool `json:"quizChanged"`
+	// Changes are the changelog entries dated on or after the day the
+	// learner's versions were recorded.
+	Changes []ChangelogEntry `json:"changes"`
 }
 
 // CodeExample holds a titled code snippet.
 type CodeExample struct {
 	Title string `json:"title"`
 	Code  string `json:"code"`
+	// CodeHTML is Code highlighted, with references to the standard
+	// library linked to its documentation.
+	CodeHTML string `json:"codeHtml"`
+	// File is the content file the code was loaded from.
+	File string `json:"-"`
+	// Check is how the validate command checks the code; see the Check*
+	// constants.
+	Check string `json:"-"`
+	// Output is what the example prints, recorded by the validate command
+	// so learners can see it without running anything. It is empty for
+	// examples that are not run.
+	Output string `json:"output,omitempty"`
+	// Nondeterministic marks an example whose output differs between runs
+	// on purpose, such as one printing the time; Output is one sample.
+	Nondeterministic bool `json:"nondeterministic,omitempty"`
 }
 
+// Checks the validate command runs on a code example.
+const (
+	// CheckRun runs the example and compares its output with the recorded
+	// golden output, unless the example is nondeterministic. It is the
+	// default.
+	CheckRun = "run"
+	// CheckCompile only type-checks the example, for programs that serve
+	// forever or print something different on every run.
+	CheckCompile = "compile"
+	// CheckNone skips the example, for snippets that are not a complete
+	// package, such as several files shown together.
+	CheckNone = "none"
+)
+
 // Exercise defines a coding exercise with starter code.
 type Exercise struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
-	StarterCode string `json:"starterCode"`
+	// DescriptionHTML is Description rendered like Lesson.ContentHTML.
+	DescriptionHTML string `json:"descriptionHtml"`
+	StarterCode     string `json:"starterCode"`
+	// StarterFile is the content file the starter code was loaded from.
+	StarterFile string `json:"-"`
+	// ExpectedOutput, when set, is the trimmed output a solution must print.
+	ExpectedOutput string `json:"-"`
 }
 
-// Quiz holds the questions for a particular lesson.
+// GlossaryTerm is an entry of a course's glossary. Lesson text links the
+// first occurrence of the term, or of one of its aliases, to the entry.
+type GlossaryTerm struct {
+	ID      string   `json:"id"`
+	Term    string   `json:"term"`
+	Reading string   `json:"reading,omitempty"`
+	Aliases []string `json:"aliases,omitempty"`
+	// Definition is Markdown; DefinitionHTML is it rendered like
+	// Lesson.ContentHTML.
+	Definition     string `json:"definition"`
+	DefinitionHTML string `json:"definitionHtml"`
+	// Lesson is the lesson that introduces the term, if any.
+	Lesson string `json:"lesson,omitempty"`
+	// Related lists the IDs of related terms.
+	Related []string `json:"related,omitempty"`
+	// Mentions lists the lessons whose text mentions the term, in teaching
+	// order.
+	Mentions []string `json:"mentions"`
+}
+
+// Quiz holds the question pool for a particular lesson.
 type Quiz struct {
 	LessonID  string     `json:"lessonId"`
 	Questions []Question `json:"questions"`
+	// Draw is how many questions one attempt draws from the pool.
+	// Zero means every question.
+	Draw int `json:"draw,omitempty"`
+	// Policy overrides the chapter's scoring policy. Store.GetQuiz resolves
+	// it, so quizzes it returns never have a nil Policy.
+	Policy *ScoringPolicy `json:"policy,omitempty"`
+	// Version is a hash of the questions, answers and explanations.
+	Version string `json:"version"`
+}
+
+// ScoringPolicy controls how a quiz is graded. Zero and nil fields inherit
+// from the chapter's policy and then from DefaultScoringPolicy.
+type ScoringPolicy struct {
+	// PassPercent is the minimum score, as a percentage of the total
+	// question weight, that passes.
+	PassPercent int `json:"passPercent,omitempty"`
+	// MaxAttempts limits how many times a learner can submit the quiz.
+	// Zero means unlimited.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// NegativeMarking gives partial credit on multi-select questions: each
+	// correct option chosen adds a share of the points and each wrong one
+	// takes a share away. Without it they are all-or-nothing. It is a
+	// pointer so a quiz can turn off what its chapter turns on.
+	NegativeMarking *bool `json:"negativeMarking,omitempty"`
+}
+
+// NegativeMarks reports whether the policy uses negative marking.
+func (p ScoringPolicy) NegativeMarks() bool {
+	return p.NegativeMarking != nil && *p.NegativeMarking
+}
+
+// DefaultScoringPolicy applies to quizzes and chapters that set no policy.
+var DefaultScoringPolicy = ScoringPolicy{PassPercent: 70}
+
+// Inherit fills the zero and nil fields of p from parent.
+func (p ScoringPolicy) Inherit(parent ScoringPolicy) ScoringPolicy {
+	if p.PassPercent == 0 {
+		p.PassPercent = parent.PassPercent
+	}
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = parent.MaxAttempts
+	}
+	if p.NegativeMarking == nil {
+		p.NegativeMarking = parent.NegativeMarking
+	}
+	return p
 }
 
-// Question is a single multiple-choice quiz question.
+// Question types. An empty Question.Type means QuestionSingle.
+const (
+	// QuestionSingle has exactly one correct option, Answer.
+	QuestionSingle = "single"
+	// QuestionMulti has several correct options, Answers.
+	QuestionMulti = "multi"
+	// QuestionOutput asks what Code prints; it is checked by running Code.
+	QuestionOutput = "output"
+	// QuestionFill asks for the text that replaces the ___ blank in Code.
+	// Any of Accepted is correct.
+	QuestionFill = "fill"
+	// QuestionOrder asks to arrange Lines (shown shuffled) into a working
+	// program. Code, if set, is a fixed prefix such as package and imports.
+	QuestionOrder = "order"
+	// QuestionShort is a free-form short answer matched by the regular
+	// expression Pattern.
+	QuestionShort = "short"
+)
+
+// Question is a single quiz question. Type selects which of the other
+// fields apply; see the Question* constants.
 type Question struct {
 	ID          string   `json:"id"`
+	Type        string   `json:"type,omitempty"`
 	Text        string   `json:"text"`
-	Options     []string `json:"options"`
+	Options     []string `json:"options,omitempty"`
 	Answer      int      `json:"answer"`
+	Answers     []int    `json:"answers,omitempty"`
+	Code        string   `json:"code,omitempty"`
+	Accepted    []string `json:"accepted,omitempty"`
+	Lines       []string `json:"lines,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
 	Explanation string   `json:"explanation"`
+	// Weight is how many points the question is worth; zero means 1.
+	Weight int `json:"weight,omitempty"`
+}
+
+// Kind returns the question type, defaulting to QuestionSingle.
+func (q Question) Kind() string {
+	if q.Type == "" {
+		return QuestionSingle
+	}
+	return q.Type
+}
+
+// Points returns the question's weight, defaulting to 1.
+func (q Question) Points() int {
+	return max(q.Weight, 1)
+}
+
+// PublicQuiz is the learner-facing view of a quiz. It omits answers and
+// explanations, which are only revealed after server-side grading.
+type PublicQuiz struct {
+	LessonID string `json:"lessonId"`
+	// SessionID identifies the attempt; it must be sent back on submit.
+	SessionID int64            `json:"sessionId"`
+	Version   string           `json:"version"`
+	Questions []PublicQuestion `json:"questions"`
+	Policy    ScoringPolicy    `json:"policy"`
+	// AttemptsUsed counts the learner's graded attempts before this one.
+	AttemptsUsed int `json:"attemptsUsed"`
+}
+
+// PublicQuestion is a Question without its answer and explanation.
+// Lines of an ordering question are shuffled.
+type PublicQuestion struct {
+	ID      string   `json:"id"`
+	Type    string   `json:"type"`
+	Text    string   `json:"text"`
+	Options []string `json:"options,omitempty"`
+	Code    string   `json:"code,omitempty"`
+	Lines   []string `json:"lines,omitempty"`
+	Points  int      `json:"points"`
+}
+
+// QuizSession is a started quiz attempt. The drawn questions and the seed
+// that shuffled them are stored so the submission is graded against exactly
+// what the learner saw.
+type QuizSession struct {
+	ID          int64
+	Username    string
+	LessonID    string
+	Seed        int64
+	QuestionIDs []string
+	// QuizVersion is the version of the quiz the session was drawn from.
+	QuizVersion string
+	StartedAt   time.Time
+	Submitted   bool
+}
+
+// QuizAttempt is one graded submission of a lesson quiz.
+type QuizAttempt struct {
+	ID          int64           `json:"id"`
+	SessionID   int64           `json:"-"`
+	Username    string          `json:"username"`
+	LessonID    string          `json:"lessonId"`
+	QuizVersion string          `json:"quizVersion,omitempty"`
+	Correct     int             `json:"correct"`
+	Total       int             `json:"total"`
+	Score       int             `json:"score"`
+	Passed      bool            `json:"passed"`
+	DurationMs  int64           `json:"durationMs"`
+	SubmittedAt time.Time       `json:"submittedAt"`
+	Answers     []AttemptAnswer `json:"answers,omitempty"`
+}
+
+// AttemptAnswer is the learner's answer to one question in an attempt.
+// Answer holds the submitted value as JSON.
+type AttemptAnswer struct {
+	QuestionID string          `json:"questionId"`
+	Answer     json.RawMessage `json:"answer"`
+	Correct    bool            `json:"correct"`
+}
+
+// LessonScores summarizes a learner's quiz attempts on one lesson.
+type LessonScores struct {
+	LessonID string    `json:"lessonId"`
+	Attempts int       `json:"attempts"`
+	Best     int       `json:"best"`
+	Latest   int       `json:"latest"`
+	LatestAt time.Time `json:"latestAt"`
+}
+
+// QuestionStats aggregates how often a question is answered correctly.
+type QuestionStats struct {
+	QuestionID string `json:"questionId"`
+	Attempts   int    `json:"attempts"`
+	Correct    int    `json:"correct"`
+	Percent    int    `json:"percent"`
+}
+
+// ReviewItem is a quiz question or note flashcard in a learner's
+// spaced-repetition queue, with its SM-2 scheduling state.
+type ReviewItem struct {
+	ID           string    `json:"id"`
+	Kind         string    `json:"kind"`
+	LessonID     string    `json:"lessonId"`
+	Ease         float64   `json:"ease"`
+	IntervalDays int       `json:"intervalDays"`
+	Repetitions  int       `json:"repetitions"`
+	DueAt        time.Time `json:"dueAt"`
+	ReviewedAt   time.Time `json:"reviewedAt,omitzero"`
+}
+
+// Exam is a timed test drawn from the quizzes of one chapter, or of the
+// whole course for the final exam (ChapterID 0).
+type Exam struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	ChapterID int    `json:"chapterId,omitempty"`
+	// Questions is how many questions a session draws from the pool.
+	Questions int `json:"questions"`
+	// TimeLimitSec is enforced on the server from the session start.
+	TimeLimitSec int `json:"timeLimitSec"`
+	// MaxAttempts limits the number of sessions; zero means unlimited.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// CooldownSec is the wait after a failed attempt before the next one.
+	CooldownSec int `json:"cooldownSec,omitempty"`
+	// Policy grades the exam. Its MaxAttempts is unused; the exam's own
+	// limits attempts.
+	Policy ScoringPolicy `json:"policy"`
+}
+
+// ExamSession is one learner's attempt at an exam. Answers are saved as
+// the learner goes so the session survives a page reload.
+type ExamSession struct {
+	ID          int64                      `json:"id"`
+	ExamID      string                     `json:"examId"`
+	Username    string                     `json:"-"`
+	Seed        int64                      `json:"-"`
+	QuestionIDs []string                   `json:"-"`
+	Answers     map[string]json.RawMessage `json:"answers"`
+	StartedAt   time.Time                  `json:"startedAt"`
+	Deadline    time.Time                  `json:"deadline"`
+	SubmittedAt time.Time                  `json:"submittedAt,omitzero"`
+	Score       int                        `json:"score"`
+	Passed      bool                       `json:"passed"`
+}
+
+// Submitted reports whether the session has been graded.
+func (s ExamSession) Submitted() bool {
+	return !s.SubmittedAt.IsZero()
+}
+
+// ChapterProgress is a learner's progress on a chapter. A chapter is
+// complete once every lesson is complete and its exam is passed.
+type ChapterProgress struct {
+	ChapterID        int  `json:"chapterId"`
+	LessonsCompleted int  `json:"lessonsCompleted"`
+	LessonsTotal     int  `json:"lessonsTotal"`
+	ExamPassed       bool `json:"examPassed"`
+	Completed        bool `json:"completed"`
 }
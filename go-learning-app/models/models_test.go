@@ -0,0 +1,31 @@
+package models
+
+import "testing"
+
+func TestScoringPolicyInherit(t *testing.T) {
+	on, off := true, false
+	chapter := ScoringPolicy{PassPercent: 80, MaxAttempts: 3, NegativeMarking: &on}
+	tests := []struct {
+		name     string
+		quiz     ScoringPolicy
+		parent   ScoringPolicy
+		pass     int
+		attempts int
+		negative bool
+	}{
+		{"empty inherits everything", ScoringPolicy{}, chapter, 80, 3, true},
+		{"quiz overrides pass mark", ScoringPolicy{PassPercent: 60}, chapter, 60, 3, true},
+		{"quiz turns negative marking off", ScoringPolicy{NegativeMarking: &off}, chapter, 80, 3, false},
+		{"quiz turns negative marking on", ScoringPolicy{NegativeMarking: &on}, DefaultScoringPolicy, 70, 0, true},
+		{"default has none", ScoringPolicy{}, DefaultScoringPolicy, 70, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.quiz.Inherit(tt.parent)
+			if got.PassPercent != tt.pass || got.MaxAttempts != tt.attempts || got.NegativeMarks() != tt.negative {
+				t.Errorf("Inherit = pass %d, attempts %d, negative %v; want %d, %d, %v",
+					got.PassPercent, got.MaxAttempts, got.NegativeMarks(), tt.pass, tt.attempts, tt.negative)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package publish
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"hash/crc32"
+	"html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"go-learning-app/data"
+	"go-learning-app/models"
+)
+
+//go:embed book
+var bookFiles embed.FS
+
+// bookTemplates are the parts of the EPUB book and of the printable
+// handbook, which share the chapters, answer key and glossary.
+var bookTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"safe": func(s string) template.HTML { return template.HTML(s) },
+}).Funcs(labelFuncs(data.DefaultLocale)).ParseFS(bookFiles, "book/*.tmpl"))
+
+// packageTemplate is the EPUB package document, which is XML.
+var packageTemplate = texttemplate.Must(texttemplate.New("content.opf").Funcs(texttemplate.FuncMap{
+	"xml": texttemplate.HTMLEscapeString,
+}).ParseFS(bookFiles, "book/content.opf"))
+
+// book is a course laid out as a book: chapters with their lessons and
+// an end-of-chapter quiz, then the answer key and the glossary.
+type book struct {
+	Lang     string
+	Course   models.Course
+	Chapters []bookChapter
+	Glossary []models.GlossaryTerm
+	// Labels are the lessons' labels and Terms the glossary's terms, by
+	// ID, for the glossary's links.
+	Labels map[string]string
+	Terms  map[string]string
+	Links  bookLinks
+
+	chapterOf map[string]int
+}
+
+// LessonLink links to a lesson by its ID.
+func (b *book) LessonLink(id string) string {
+	return b.Links.Lesson(b.chapterOf[id], id)
+}
+
+// bookChapter is a chapter with the links of the book it is part of, so
+// its template can link to the answer key.
+type bookChapter struct {
+	chapter
+	Links bookLinks
+}
+
+// HasQuiz reports whether any lesson of the chapter has a quiz.
+func (c bookChapter) HasQuiz() bool {
+	for _, l := range c.Lessons {
+		if len(l.Quiz) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// bookLinks are the hrefs between the parts of a book: across the files
+// of an EPUB book, or within the single page of a handbook.
+type bookLinks struct {
+	epub bool
+}
+
+func (b bookLinks) file(name string) string {
+	if b.epub {
+		return name
+	}
+	return ""
+}
+
+// Chapter links to a chapter.
+func (b bookLinks) Chapter(id int) string {
+	return fmt.Sprintf("%s#chapter-%d", b.file(chapterFile(id)), id)
+}
+
+// Lesson links to a lesson of a chapter.
+func (b bookLinks) Lesson(chapterID int, lessonID string) string {
+	return b.file(chapterFile(chapterID)) + "#lesson-" + lessonID
+}
+
+// Question links to a question of a chapter's quiz.
+func (b bookLinks) Question(chapterID int, lessonID string, n int) string {
+	return fmt.Sprintf("%s#question-%s-%d", b.file(chapterFile(chapterID)), lessonID, n)
+}
+
+// AnswerKey links to the answer key.
+func (b bookLinks) AnswerKey() string {
+	return b.file(answersFile) + "#answers"
+}
+
+// Glossary links to the glossary.
+func (b bookLinks) Glossary() string {
+	return b.file(glossaryFile) + "#glossary"
+}
+
+// Answer links to a question's answer in the answer key.
+func (b bookLinks) Answer(lessonID string, n int) string {
+	return fmt.Sprintf("%s#answer-%s-%d", b.file(answersFile), lessonID, n)
+}
+
+// Term links to a glossary term.
+func (b bookLinks) Term(id string) string {
+	return b.file(glossaryFile) + "#term-" + id
+}
+
+// Files of an EPUB book besides the chapters, which chapterFile names.
+const (
+	titleFile    = "title.xhtml"
+	navFile      = "nav.xhtml"
+	answersFile  = "answers.xhtml"
+	glossaryFile = "glossary.xhtml"
+	styleFile    = "style.css"
+)
+
+func chapterFile(id int) string {
+	return fmt.Sprintf("chapter-%d.xhtml", id)
+}
+
+// newBook lays a course out as a book, with lesson text linking as the
+// book's links do. Headings get IDs unique within the book and no anchor
+// links, which are of no use on paper.
+func newBook(ctx context.Context, store *data.Store, links bookLinks) (*book, error) {
+	chapters, err := course(ctx, store, data.Links{
+		HeadingIDs: func(lessonID string) string { return "lesson-" + lessonID + "-heading-" },
+		Term:       links.Term,
+	})
+	if err != nil {
+		return nil, err
+	}
+	b := &book{
+		Lang:      store.Locale,
+		Course:    store.Course,
+		Glossary:  store.Glossary(),
+		Labels:    make(map[string]string),
+		Terms:     make(map[string]string),
+		Links:     links,
+		chapterOf: make(map[string]int),
+	}
+	for _, ch := range chapters {
+		b.Chapters = append(b.Chapters, bookChapter{chapter: ch, Links: links})
+		for _, l := range ch.Lessons {
+			b.Labels[l.ID] = l.Label
+			b.chapterOf[l.ID] = ch.ID
+		}
+	}
+	for _, t := range b.Glossary {
+		b.Terms[t.ID] = t.Term
+	}
+	return b, nil
+}
+
+// Handbook writes a course as a single HTML page laid out for printing:
+// a cover and contents, each chapter on new pages with its lessons and
+// an end-of-chapter quiz, then the answer key and the glossary.
+func Handbook(ctx context.Context, store *data.Store, w io.Writer) error {
+	b, err := newBook(ctx, store, bookLinks{})
+	if err != nil {
+		return err
+	}
+	style, err := bookFiles.ReadFile("book/" + styleFile)
+	if err != nil {
+		return err
+	}
+	tmpl, err := localize(bookTemplates, b.Lang)
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, "handbook.tmpl", struct {
+		*book
+		Style template.CSS
+	}{b, template.CSS(style)})
+}
+
+// EPUB writes a course as an EPUB 3 book with the parts of the handbook,
+// one file per chapter.
+func EPUB(ctx context.Context, store *data.Store, w io.Writer) error {
+	b, err := newBook(ctx, store, bookLinks{epub: true})
+	if err != nil {
+		return err
+	}
+	z := zip.NewWriter(w)
+	// The mimetype comes first, uncompressed and with its size in the
+	// header, so readers can identify the file by its first bytes.
+	mimetype := []byte("application/epub+zip")
+	f, err := z.CreateRaw(&zip.FileHeader{
+		Name:               "mimetype",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(mimetype),
+		CompressedSize64:   uint64(len(mimetype)),
+		UncompressedSize64: uint64(len(mimetype)),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(mimetype); err != nil {
+		return err
+	}
+	add := func(name string, content []byte) error {
+		f, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(content)
+		return err
+	}
+	container, err := bookFiles.ReadFile("book/container.xml")
+	if err != nil {
+		return err
+	}
+	if err := add("META-INF/container.xml", container); err != nil {
+		return err
+	}
+	style, err := bookFiles.ReadFile("book/" + styleFile)
+	if err != nil {
+		return err
+	}
+	if err := add("OEBPS/"+styleFile, style); err != nil {
+		return err
+	}
+
+	tmpl, err := localize(bookTemplates, b.Lang)
+	if err != nil {
+		return err
+	}
+	// page renders one of the book's templates as an XHTML document.
+	type item struct{ ID, Href, Properties string }
+	var spine []item
+	page := func(name, title, part string, data any, properties string) error {
+		var body bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&body, part, data); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		var doc bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&doc, "xhtml.tmpl", struct {
+			Lang, Title string
+			Body        template.HTML
+		}{b.Lang, title, template.HTML(body.String())}); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		content, err := xhtml(doc.Bytes())
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		spine = append(spine, item{ID: strings.TrimSuffix(name, ".xhtml"), Href: name, Properties: properties})
+		return add("OEBPS/"+name, content)
+	}
+
+	if err := page(titleFile, b.Course.Title, "title", b, ""); err != nil {
+		return err
+	}
+	if err := page(navFile, label(b.Lang, "目次"), "contents", b, "nav"); err != nil {
+		return err
+	}
+	for _, ch := range b.Chapters {
+		if err := page(chapterFile(ch.ID), ch.Title, "chapter", ch, ""); err != nil {
+			return err
+		}
+	}
+	if err := page(answersFile, label(b.Lang, "解答"), "answers", b, ""); err != nil {
+		return err
+	}
+	if len(b.Glossary) > 0 {
+		if err := page(glossaryFile, label(b.Lang, "用語集"), "glossary", b, ""); err != nil {
+			return err
+		}
+	}
+
+	var opf bytes.Buffer
+	if err := packageTemplate.Execute(&opf, struct {
+		*book
+		ID       string
+		Modified string
+		Style    string
+		Items    []item
+	}{
+		book:     b,
+		ID:       "go-learning-app:" + b.Course.ID + ":" + b.Lang,
+		Modified: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Style:    styleFile,
+		Items:    spine,
+	}); err != nil {
+		return err
+	}
+	if err := add("OEBPS/content.opf", opf.Bytes()); err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+// xhtml reserializes an HTML document as the XHTML that EPUB requires:
+// void elements are closed and every attribute has a value.
+func xhtml(doc []byte) ([]byte, error) {
+	root, err := html.Parse(bytes.NewReader(doc))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	if err := html.Render(&buf, root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,67 @@
+package publish
+
+import (
+	"fmt"
+	"html/template"
+)
+
+// labels translates the fixed text of the pages per locale, keyed by the
+// Japanese text. Text a locale leaves out is shown in Japanese.
+var labels = map[string]map[string]string{
+	"en": {
+		"第%d章: %s":   "Chapter %d: %s",
+		"第%d章の確認クイズ": "Chapter %d quiz",
+		"目次":         "Contents",
+		"解答":         "Answers",
+		"解答へ":        "See the answer",
+		"用語集":        "Glossary",
+		"出力":         "Output",
+		"出力例（実行するたびに変わります）": "Sample output (changes with every run)",
+		"ポイント":     "Key points",
+		"演習: %s":   "Exercise: %s",
+		"スターターコード": "Starter code",
+		"確認クイズ":    "Quiz",
+		"次の行を並べ替えてください。": "Put these lines in order.",
+		"答えを見る":     "Show the answer",
+		"正解":        "Answer",
+		"正解: ":      "Answer: ",
+		"、":         ", ",
+		"出てくるレッスン:": "Appears in:",
+		"関連用語:":     "Related terms:",
+		"本文":        "Text",
+		"演習":        "Exercise",
+		"クイズ":       "Quiz",
+		"検索":        "Search",
+		"検索語":       "Search terms",
+		"検索には JavaScript が必要です。": "Search needs JavaScript.",
+		"「%s」に一致する箇所はありませんでした。":  "Nothing matches “%s”.",
+		"%s件見つかりました。":            "%s results found.",
+	},
+}
+
+// label returns the text in the locale.
+func label(locale, text string) string {
+	if t, ok := labels[locale][text]; ok {
+		return t
+	}
+	return text
+}
+
+// labelFuncs are the template functions that translate fixed text: t
+// translates a text and tf a format, which it then fills in. Templates are
+// parsed with the Japanese ones and localized with localize.
+func labelFuncs(locale string) template.FuncMap {
+	return template.FuncMap{
+		"t":  func(text string) string { return label(locale, text) },
+		"tf": func(format string, args ...any) string { return fmt.Sprintf(label(locale, format), args...) },
+	}
+}
+
+// localize returns a copy of tmpl that shows its fixed text in the locale.
+func localize(tmpl *template.Template, locale string) (*template.Template, error) {
+	c, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return c.Funcs(labelFuncs(locale)), nil
+}
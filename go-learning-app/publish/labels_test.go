@@ -0,0 +1,31 @@
+package publish
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestLocalize(t *testing.T) {
+	tmpl := template.Must(template.New("page").Funcs(labelFuncs("ja")).Parse(`{{tf "第%d章: %s" 1 "Go"}} / {{t "解答"}} / {{t "未訳"}}`))
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"ja", "第1章: Go / 解答 / 未訳"},
+		{"en", "Chapter 1: Go / Answers / 未訳"},
+	}
+	for _, tt := range tests {
+		l, err := localize(tmpl, tt.locale)
+		if err != nil {
+			t.Fatalf("localize(%q): %v", tt.locale, err)
+		}
+		var b strings.Builder
+		if err := l.Execute(&b, nil); err != nil {
+			t.Fatalf("Execute(%q): %v", tt.locale, err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.locale, b.String(), tt.want)
+		}
+	}
+}
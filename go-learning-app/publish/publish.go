@@ -0,0 +1,161 @@
+// Package publish renders a course into files that are read without the
+// server: a static website for training rooms without a network and for
+// read-only hosting, and an EPUB book and a printable handbook. Code is
+// shown with the output the validate command recorded instead of being
+// run.
+package publish
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go-learning-app/data"
+	"go-learning-app/grading"
+	"go-learning-app/markdown"
+	"go-learning-app/models"
+)
+
+// chapter is a chapter with its lessons, rendered for the pages.
+type chapter struct {
+	models.Chapter
+	Lessons []lesson
+}
+
+// lesson is a lesson rendered for the pages, with its quiz.
+type lesson struct {
+	models.Lesson
+	// Label is the lesson's number and title, as the app shows it.
+	Label string
+	// Starter is the exercise's starter code, highlighted.
+	Starter string
+	Quiz    []question
+}
+
+// question is a quiz question with its answer, for reading.
+type question struct {
+	Number int
+	Kind   string
+	Text   string
+	// Options are labeled A, B, ... for choice questions.
+	Options []string
+	// Code is shown with output, fill and ordering questions.
+	Code string
+	// Lines are an ordering question's lines, trimmed and sorted so the
+	// page does not give the order away.
+	Lines       []string
+	Answer      answer
+	Explanation string
+}
+
+// answer is a question's correct answer in one of three forms.
+type answer struct {
+	// Options are the correct options of a choice question.
+	Options []string
+	// Code is an output to print or a program to arrange.
+	Code string
+	// Text is an answer to write.
+	Text string
+}
+
+// course renders a store's chapters, lessons and quizzes in teaching
+// order, with lesson text linking as links says.
+func course(ctx context.Context, store *data.Store, links data.Links) ([]chapter, error) {
+	var chapters []chapter
+	for _, ch := range store.GetChapters() {
+		c := chapter{Chapter: ch}
+		for _, summary := range ch.Lessons {
+			l, ok := store.GetLesson(summary.ID)
+			if !ok {
+				continue
+			}
+			rendered := lesson{
+				Lesson: store.RenderLesson(l, links),
+				Label:  l.ID + " " + l.Title,
+			}
+			if l.Exercise != nil && l.Exercise.StarterCode != "" {
+				rendered.Starter = markdown.HighlightGo(l.Exercise.StarterCode, nil)
+			}
+			if quiz, ok := store.GetQuiz(l.ID); ok {
+				questions, err := quizQuestions(ctx, quiz)
+				if err != nil {
+					return nil, fmt.Errorf("lesson %s: %w", l.ID, err)
+				}
+				rendered.Quiz = questions
+			}
+			c.Lessons = append(c.Lessons, rendered)
+		}
+		chapters = append(chapters, c)
+	}
+	return chapters, nil
+}
+
+// optionLabel returns the label of the i-th option: A, B, ...
+func optionLabel(i int) string {
+	return string(rune('A' + i))
+}
+
+// quizQuestions converts a quiz's questions, finding their answers as
+// grading reveals them.
+func quizQuestions(ctx context.Context, quiz models.Quiz) ([]question, error) {
+	var questions []question
+	for i, q := range quiz.Questions {
+		key, err := grading.Key(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("answer to question %s: %w", q.ID, err)
+		}
+		out := question{
+			Number:      i + 1,
+			Kind:        q.Kind(),
+			Text:        q.Text,
+			Code:        q.Code,
+			Explanation: q.Explanation,
+		}
+		for j, o := range q.Options {
+			out.Options = append(out.Options, optionLabel(j)+". "+o)
+		}
+		switch k := key.(type) {
+		case int:
+			out.Answer.Options = []string{out.Options[k]}
+		case []int:
+			for _, j := range slices.Sorted(slices.Values(k)) {
+				out.Answer.Options = append(out.Answer.Options, out.Options[j])
+			}
+		case []string:
+			for _, line := range k {
+				out.Lines = append(out.Lines, strings.TrimSpace(line))
+			}
+			slices.Sort(out.Lines)
+			out.Answer.Code = orderedProgram(q.Code, k)
+		case string:
+			if out.Kind == models.QuestionOutput {
+				out.Answer.Code = k
+			} else {
+				out.Answer.Text = k
+			}
+		}
+		questions = append(questions, out)
+	}
+	return questions, nil
+}
+
+// orderedProgram is an ordering question's program in the keyed order,
+// after its fixed prefix.
+func orderedProgram(prefix string, lines []string) string {
+	body := strings.Join(lines, "\n")
+	if prefix == "" {
+		return body
+	}
+	return prefix + "\n" + body
+}
+
+// tags matches the HTML tags textOf removes.
+var tags = regexp.MustCompile(`<[^>]*>`)
+
+// textOf returns the text of rendered HTML, for searching.
+func textOf(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(tags.ReplaceAllString(s, " "))), " ")
+}
@@ -0,0 +1,215 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+
+	"go-learning-app/data"
+	"go-learning-app/models"
+)
+
+//go:embed site
+var siteFiles embed.FS
+
+// siteTemplates are the pages of the static site.
+var siteTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"safe":      func(s string) template.HTML { return template.HTML(s) },
+	"lessonURL": lessonURL,
+	"add":       func(a, b int) int { return a + b },
+}).Funcs(labelFuncs(data.DefaultLocale)).ParseFS(siteFiles, "site/*.tmpl"))
+
+// siteLinks link lesson text within the static site. There is no
+// documentation to link code to.
+var siteLinks = data.Links{
+	Heading: func(_, slug string) string { return "#section-heading-" + slug },
+	Term:    func(id string) string { return "glossary.html#term-" + id },
+}
+
+// lessonURL returns the page of a lesson.
+func lessonURL(id string) string {
+	return "lesson-" + id + ".html"
+}
+
+// sitePage is what every page of the site shows around its body.
+type sitePage struct {
+	Lang     string
+	Course   models.Course
+	Chapters []chapter
+	Glossary []models.GlossaryTerm
+	// Labels are the lessons' labels and Terms the glossary's terms, by
+	// ID, for the glossary's links.
+	Labels map[string]string
+	Terms  map[string]string
+	// Title is the page's own title; Current is the lesson it shows.
+	Title   string
+	Current string
+}
+
+// lessonPage is a lesson with the lessons before and after it.
+type lessonPage struct {
+	sitePage
+	Lesson     lesson
+	Chapter    models.Chapter
+	Prev, Next *lesson
+}
+
+// searchEntry is a section of a lesson in the search index.
+type searchEntry struct {
+	Lesson  string `json:"lesson"`
+	Section string `json:"section"`
+	URL     string `json:"url"`
+	Text    string `json:"text"`
+}
+
+// Site writes a course as a static website into dir: an index of its
+// chapters, a page per lesson with its code examples, exercise and quiz,
+// the glossary, and a search page that runs in the browser. The pages
+// only link to each other, so the site also works opened from disk.
+func Site(ctx context.Context, store *data.Store, dir string) error {
+	chapters, err := course(ctx, store, siteLinks)
+	if err != nil {
+		return err
+	}
+	site := sitePage{
+		Lang:     store.Locale,
+		Course:   store.Course,
+		Chapters: chapters,
+		Glossary: store.Glossary(),
+		Labels:   make(map[string]string),
+		Terms:    make(map[string]string),
+	}
+	for _, ch := range chapters {
+		for _, l := range ch.Lessons {
+			site.Labels[l.ID] = l.Label
+		}
+	}
+	for _, t := range site.Glossary {
+		site.Terms[t.ID] = t.Term
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	write := func(name string, content []byte) error {
+		return os.WriteFile(filepath.Join(dir, name), content, 0o644)
+	}
+	tmpl, err := localize(siteTemplates, store.Locale)
+	if err != nil {
+		return err
+	}
+	page := func(name, part string, data any) error {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, part, data); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return write(name, buf.Bytes())
+	}
+
+	if err := page("index.html", "index.tmpl", site); err != nil {
+		return err
+	}
+	var all []*lesson
+	for i := range chapters {
+		for j := range chapters[i].Lessons {
+			all = append(all, &chapters[i].Lessons[j])
+		}
+	}
+	var index []searchEntry
+	for i, l := range all {
+		p := lessonPage{sitePage: site, Lesson: *l}
+		p.Title, p.Current = l.Label, l.ID
+		for _, ch := range chapters {
+			if ch.ID == l.ChapterID {
+				p.Chapter = ch.Chapter
+			}
+		}
+		if i > 0 {
+			p.Prev = all[i-1]
+		}
+		if i+1 < len(all) {
+			p.Next = all[i+1]
+		}
+		if err := page(lessonURL(l.ID), "lesson.tmpl", p); err != nil {
+			return err
+		}
+		index = append(index, searchEntries(site.Lang, *l)...)
+	}
+	if len(site.Glossary) > 0 {
+		p := site
+		p.Title = label(site.Lang, "用語集")
+		if err := page("glossary.html", "glossary.tmpl", p); err != nil {
+			return err
+		}
+	}
+	p := site
+	p.Title = label(site.Lang, "検索")
+	if err := page("search.html", "search.tmpl", p); err != nil {
+		return err
+	}
+
+	// The index is a script rather than JSON, which browsers do not let
+	// pages opened from disk fetch.
+	raw, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := write("search-index.js", append(append([]byte("const SEARCH_INDEX = "), raw...), ";\n"...)); err != nil {
+		return err
+	}
+	for _, name := range []string{"style.css", "search.js"} {
+		content, err := siteFiles.ReadFile("site/" + name)
+		if err != nil {
+			return err
+		}
+		if err := write(name, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchEntries returns the sections of a lesson that search finds, as
+// the app's search has them. Quiz explanations are left out, as they give
+// the answers away. Section names are in the locale.
+func searchEntries(locale string, l lesson) []searchEntry {
+	url := lessonURL(l.ID)
+	entries := []searchEntry{{
+		Lesson: l.Label, Section: label(locale, "本文"), URL: url + "#section-content",
+		Text: l.Title + " " + textOf(l.ContentHTML),
+	}}
+	for i, ex := range l.CodeExamples {
+		entries = append(entries, searchEntry{
+			Lesson: l.Label, Section: ex.Title, URL: fmt.Sprintf("%s#section-example-%d", url, i+1),
+			Text: ex.Title + " " + ex.Code,
+		})
+	}
+	if l.Exercise != nil {
+		entries = append(entries, searchEntry{
+			Lesson: l.Label, Section: label(locale, "演習"), URL: url + "#section-exercise",
+			Text: l.Exercise.Title + " " + textOf(l.Exercise.DescriptionHTML),
+		})
+	}
+	if len(l.NotesHTML) > 0 {
+		var text string
+		for _, n := range l.NotesHTML {
+			text += textOf(n) + " "
+		}
+		entries = append(entries, searchEntry{Lesson: l.Label, Section: label(locale, "ポイント"), URL: url + "#section-notes", Text: text})
+	}
+	if len(l.Quiz) > 0 {
+		var text string
+		for _, q := range l.Quiz {
+			text += q.Text + " "
+			for _, o := range q.Options {
+				text += o + " "
+			}
+		}
+		entries = append(entries, searchEntry{Lesson: l.Label, Section: label(locale, "クイズ"), URL: url + "#section-quiz", Text: text})
+	}
+	return entries
+}
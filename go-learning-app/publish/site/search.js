@@ -0,0 +1,46 @@
+// Search over SEARCH_INDEX, written by the export, for pages opened from
+// disk as well as from a server. Every word of the query must occur in a
+// section; sections are ranked by how often the words occur.
+(function () {
+    const params = new URLSearchParams(window.location.search);
+    const query = (params.get('q') || '').trim();
+    const input = document.getElementById('searchQuery');
+    const results = document.getElementById('searchResults');
+    input.value = query;
+    if (!query) return;
+
+    const escape = s => s.replace(/[&<>"']/g, c => ({ '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;' })[c]);
+    const words = query.toLowerCase().split(/\s+/);
+    const count = (text, word) => text.split(word).length - 1;
+
+    const hits = [];
+    for (const entry of SEARCH_INDEX) {
+        const text = entry.text.toLowerCase();
+        if (!words.every(w => text.includes(w))) continue;
+        hits.push({ entry, score: words.reduce((n, w) => n + count(text, w), 0) });
+    }
+    hits.sort((a, b) => b.score - a.score);
+
+    // An excerpt around the first match, with every word marked.
+    const snippet = text => {
+        const at = text.toLowerCase().indexOf(words[0]);
+        const start = Math.max(0, at - 40);
+        let s = escape(text.slice(start, start + 160));
+        for (const w of words) {
+            s = s.replace(new RegExp(escape(w).replace(/[.*+?^${}()|[\]\\]/g, '\\$&'), 'gi'), m => `<mark>${m}</mark>`);
+        }
+        return (start > 0 ? '…' : '') + s + (start + 160 < text.length ? '…' : '');
+    };
+
+    // The messages come in the page's language, with %s for the query or
+    // the number of hits.
+    const message = (name, value) => results.dataset[name].replace('%s', value);
+    results.innerHTML = hits.length === 0
+        ? `<p>${message('none', escape(query))}</p>`
+        : `<p>${message('found', hits.length)}</p>` + hits.map(({ entry }) => `
+            <div class="search-result">
+                <a href="${escape(entry.url)}">${escape(entry.lesson)}</a>
+                <div class="search-result-section">${escape(entry.section)}</div>
+                <div class="search-result-snippet">${snippet(entry.text)}</div>
+            </div>`).join('');
+})();
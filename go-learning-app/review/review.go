@@ -0,0 +1,106 @@
+package review
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-learning-app/models"
+)
+
+const (
+	// DefaultEase is the ease factor of a newly queued item.
+	DefaultEase = 2.5
+	// MinEase is the lowest ease factor an item can drop to.
+	MinEase = 1.3
+)
+
+// Quality grades for a review, on the SM-2 scale of 0 (blackout) to 5
+// (perfect recall). Anything below QualityPass resets the item.
+const (
+	QualityAgain = 1
+	QualityPass  = 3
+	QualityGood  = 4
+	QualityEasy  = 5
+)
+
+// Item kinds and their ID prefixes.
+const (
+	KindQuestion = "question"
+	KindNote     = "note"
+)
+
+// QuestionItemID returns the review item ID of a quiz question.
+func QuestionItemID(questionID string) string {
+	return KindQuestion + ":" + questionID
+}
+
+// NoteItemID returns the review item ID of a lesson's index-th note.
+func NoteItemID(lessonID string, index int) string {
+	return fmt.Sprintf("%s:%s:%d", KindNote, lessonID, index)
+}
+
+// QuestionOf returns the quiz question ID of a question item ID.
+func QuestionOf(itemID string) string {
+	return strings.TrimPrefix(itemID, KindQuestion+":")
+}
+
+// NoteIndexOf returns the note index of a note item ID.
+func NoteIndexOf(itemID string) (int, bool) {
+	i := strings.LastIndexByte(itemID, ':')
+	if i < 0 || !strings.HasPrefix(itemID, KindNote+":") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(itemID[i+1:])
+	return n, err == nil && n >= 0
+}
+
+// New returns a new item of the given kind that is due at due.
+func New(id, kind, lessonID string, due time.Time) models.ReviewItem {
+	return models.ReviewItem{
+		ID:       id,
+		Kind:     kind,
+		LessonID: lessonID,
+		Ease:     DefaultEase,
+		DueAt:    due,
+	}
+}
+
+// Schedule applies a review of the given quality (0-5) at now using the
+// SM-2 algorithm and returns the rescheduled item.
+func Schedule(item models.ReviewItem, quality int, now time.Time) models.ReviewItem {
+	quality = min(max(quality, 0), 5)
+
+	if quality < QualityPass {
+		item.Repetitions = 0
+		item.IntervalDays = 1
+	} else {
+		item.Repetitions++
+		switch item.Repetitions {
+		case 1:
+			item.IntervalDays = 1
+		case 2:
+			item.IntervalDays = 6
+		default:
+			item.IntervalDays = int(math.Round(float64(item.IntervalDays) * item.Ease))
+		}
+	}
+
+	q := float64(5 - quality)
+	item.Ease = max(item.Ease+0.1-q*(0.08+q*0.02), MinEase)
+	item.DueAt = now.AddDate(0, 0, item.IntervalDays)
+	item.ReviewedAt = now
+	return item
+}
+
+// Lapse returns an item that was just answered wrong outside a review (for
+// example in a quiz): it starts over and is due immediately.
+func Lapse(item models.ReviewItem, now time.Time) models.ReviewItem {
+	item.Repetitions = 0
+	item.IntervalDays = 0
+	item.Ease = max(item.Ease-0.2, MinEase)
+	item.DueAt = now
+	return item
+}
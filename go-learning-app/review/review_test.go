@@ -0,0 +1,91 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"go-learning-app/models"
+)
+
+func TestSchedule(t *testing.T) {
+	now := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		item     models.ReviewItem
+		quality  int
+		reps     int
+		interval int
+		ease     float64
+	}{
+		{"first pass", models.ReviewItem{Ease: DefaultEase}, QualityGood, 1, 1, 2.5},
+		{"second pass", models.ReviewItem{Ease: DefaultEase, Repetitions: 1, IntervalDays: 1}, QualityGood, 2, 6, 2.5},
+		{"later pass multiplies by ease", models.ReviewItem{Ease: DefaultEase, Repetitions: 2, IntervalDays: 6}, QualityGood, 3, 15, 2.5},
+		{"easy raises ease", models.ReviewItem{Ease: DefaultEase}, QualityEasy, 1, 1, 2.6},
+		{"pass lowers ease", models.ReviewItem{Ease: DefaultEase}, QualityPass, 1, 1, 2.36},
+		{"fail starts over", models.ReviewItem{Ease: DefaultEase, Repetitions: 4, IntervalDays: 40}, QualityAgain, 0, 1, 1.96},
+		{"ease stays above minimum", models.ReviewItem{Ease: MinEase}, 0, 0, 1, MinEase},
+		{"quality is clamped", models.ReviewItem{Ease: DefaultEase}, 9, 1, 1, 2.6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Schedule(tt.item, tt.quality, now)
+			if got.Repetitions != tt.reps || got.IntervalDays != tt.interval {
+				t.Errorf("Schedule = %d repetitions, %d days; want %d, %d", got.Repetitions, got.IntervalDays, tt.reps, tt.interval)
+			}
+			if diff := got.Ease - tt.ease; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Schedule ease = %v, want %v", got.Ease, tt.ease)
+			}
+			if want := now.AddDate(0, 0, tt.interval); !got.DueAt.Equal(want) {
+				t.Errorf("Schedule due = %v, want %v", got.DueAt, want)
+			}
+			if !got.ReviewedAt.Equal(now) {
+				t.Errorf("Schedule reviewed = %v, want %v", got.ReviewedAt, now)
+			}
+		})
+	}
+}
+
+func TestLapse(t *testing.T) {
+	now := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ease, want float64
+	}{
+		{DefaultEase, 2.3},
+		{1.4, MinEase},
+	}
+	for _, tt := range tests {
+		item := models.ReviewItem{Ease: tt.ease, Repetitions: 3, IntervalDays: 20, DueAt: now.AddDate(0, 0, 20)}
+		got := Lapse(item, now)
+		if got.Repetitions != 0 || got.IntervalDays != 0 || !got.DueAt.Equal(now) {
+			t.Errorf("Lapse = %d repetitions, %d days, due %v; want 0, 0, %v", got.Repetitions, got.IntervalDays, got.DueAt, now)
+		}
+		if diff := got.Ease - tt.want; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("Lapse ease from %v = %v, want %v", tt.ease, got.Ease, tt.want)
+		}
+	}
+}
+
+func TestItemIDs(t *testing.T) {
+	if id := QuestionItemID("1-1-2"); id != "question:1-1-2" || QuestionOf(id) != "1-1-2" {
+		t.Errorf("QuestionItemID = %q, QuestionOf = %q", id, QuestionOf(id))
+	}
+
+	tests := []struct {
+		id    string
+		index int
+		ok    bool
+	}{
+		{NoteItemID("1-1", 2), 2, true},
+		{NoteItemID("10-3", 0), 0, true},
+		{"note:1-1:x", 0, false},
+		{"note:1-1:-1", 0, false},
+		{"question:1-1-1", 0, false},
+		{"note", 0, false},
+	}
+	for _, tt := range tests {
+		index, ok := NoteIndexOf(tt.id)
+		if ok != tt.ok || (ok && index != tt.index) {
+			t.Errorf("NoteIndexOf(%q) = %d, %v; want %d, %v", tt.id, index, ok, tt.index, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// Timeout is the maximum wall-clock time a program may run.
+const Timeout = 5 * time.Second
+
+// ErrTimeout is returned when a program exceeds Timeout.
+var ErrTimeout = errors.New("実行がタイムアウトしました（5秒）")
+
+// Result is the combined output of a program run.
+type Result struct {
+	Output string
+	// Failed is true when the program did not compile or exited non-zero.
+	Failed bool
+}
+
+// Run compiles and runs a single-file Go program and returns its combined
+// stdout and stderr. A non-nil error means the program could not be run at
+// all (or timed out); compile and runtime errors are reported via Result.Failed.
+func Run(ctx context.Context, code string) (Result, error) {
+	tmpDir, err := os.MkdirTemp("", "gorun-*")
+	if err != nil {
+		return Result{}, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	codePath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(codePath, []byte(code), 0644); err != nil {
+		return Result{}, fmt.Errorf("write code: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "go", "run", codePath)
+	// Run outside any module so programs cannot import the app's
+	// dependencies, and the go tool never touches the app's go.mod.
+	cmd.Dir = tmpDir
+	// Killing "go run" does not kill the program it started, which keeps
+	// the output pipe open, so stop waiting for the pipe shortly after.
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+
+	res := Result{Output: string(output)}
+	if ctx.Err() == context.DeadlineExceeded {
+		res.Failed = true
+		return res, ErrTimeout
+	}
+	if err != nil {
+		res.Failed = true
+	}
+	return res, nil
+}
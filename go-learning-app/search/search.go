@@ -0,0 +1,204 @@
+// Package search is an in-memory full-text index over course content. It
+// tokenizes Japanese text into character bigrams, since it has no spaces
+// between words, and keeps dotted Go identifiers such as fmt.Sprintf whole,
+// so both prose and code can be searched. Results are ranked with BM25.
+package search
+
+import (
+	"html"
+	"math"
+	"slices"
+	"strings"
+)
+
+// BM25 parameters: k1 limits how much repeating a term adds, b how much a
+// long section is penalized.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// titleBoost weights a term in a section's title over one in its text.
+const titleBoost = 3
+
+// snippetRunes is the length a snippet is cut to, and snippetLead how many
+// runes it shows before the first match.
+const (
+	snippetRunes = 120
+	snippetLead  = 30
+)
+
+// Doc is one searchable section of a lesson.
+type Doc struct {
+	LessonID string
+	// Section names the part of the lesson, e.g. "content", "example-1",
+	// "exercise", "notes" or "quiz".
+	Section string
+	Title   string
+	// Text is plain prose and Code is source code. Both are searched; the
+	// snippet is taken from whichever matches first.
+	Text string
+	Code string
+}
+
+// Result is a matching section.
+type Result struct {
+	LessonID string  `json:"lessonId"`
+	Section  string  `json:"section"`
+	Title    string  `json:"title"`
+	Score    float64 `json:"score"`
+	// Snippet is an HTML-escaped excerpt with the matches in <mark>.
+	Snippet string `json:"snippet"`
+}
+
+// Index is an inverted index of docs. It is safe for concurrent searches
+// once built.
+type Index struct {
+	docs []Doc
+	// postings maps a term to the weighted count of the term in each doc
+	// that contains it.
+	postings map[string]map[int]float64
+	lengths  []float64
+	avgLen   float64
+}
+
+// New indexes docs.
+func New(docs []Doc) *Index {
+	idx := &Index{
+		docs:     docs,
+		postings: make(map[string]map[int]float64),
+		lengths:  make([]float64, len(docs)),
+	}
+	var total float64
+	for i, d := range docs {
+		add := func(text string, weight float64) {
+			for _, t := range tokenize(text) {
+				p := idx.postings[t.term]
+				if p == nil {
+					p = make(map[int]float64)
+					idx.postings[t.term] = p
+				}
+				p[i] += weight
+				idx.lengths[i] += weight
+			}
+		}
+		add(d.Title, titleBoost)
+		add(d.Text, 1)
+		add(d.Code, 1)
+		total += idx.lengths[i]
+	}
+	if len(docs) > 0 {
+		idx.avgLen = total / float64(len(docs))
+	}
+	return idx
+}
+
+// Search returns up to limit sections containing every term of the query,
+// best first.
+func (idx *Index) Search(query string, limit int) []Result {
+	qterms := terms(query)
+	if len(qterms) == 0 {
+		return nil
+	}
+
+	scores := make(map[int]float64)
+	for n, term := range qterms {
+		p := idx.postings[term]
+		idf := math.Log(1 + (float64(len(idx.docs))-float64(len(p))+0.5)/(float64(len(p))+0.5))
+		for i := range scores {
+			if _, ok := p[i]; !ok {
+				delete(scores, i)
+			}
+		}
+		for i, tf := range p {
+			if _, ok := scores[i]; !ok && n > 0 {
+				continue
+			}
+			norm := tf * (k1 + 1) / (tf + k1*(1-b+b*idx.lengths[i]/idx.avgLen))
+			scores[i] += idf * norm
+		}
+		if len(scores) == 0 {
+			return nil
+		}
+	}
+
+	ids := make([]int, 0, len(scores))
+	for i := range scores {
+		ids = append(ids, i)
+	}
+	slices.SortFunc(ids, func(a, b int) int {
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		}
+		return a - b
+	})
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	want := make(map[string]bool, len(qterms))
+	for _, t := range qterms {
+		want[t] = true
+	}
+	results := make([]Result, 0, len(ids))
+	for _, i := range ids {
+		d := idx.docs[i]
+		results = append(results, Result{
+			LessonID: d.LessonID,
+			Section:  d.Section,
+			Title:    d.Title,
+			Score:    math.Round(scores[i]*1000) / 1000,
+			Snippet:  snippet(d, want),
+		})
+	}
+	return results
+}
+
+// snippet excerpts the first field of d that matches, starting a little
+// before the first match, and marks every match in the excerpt.
+func snippet(d Doc, want map[string]bool) string {
+	for _, text := range []string{d.Text, d.Code, d.Title} {
+		text = strings.Join(strings.Fields(text), " ")
+		rs := []rune(text)
+		var marks [][2]int
+		for _, t := range tokenize(text) {
+			if want[t.term] {
+				marks = append(marks, [2]int{t.start, t.end})
+			}
+		}
+		if len(marks) == 0 {
+			continue
+		}
+
+		start := max(0, marks[0][0]-snippetLead)
+		end := min(len(rs), start+snippetRunes)
+		var sb strings.Builder
+		if start > 0 {
+			sb.WriteString("…")
+		}
+		inMark := false
+		for i := start; i < end; i++ {
+			marked := slices.ContainsFunc(marks, func(m [2]int) bool { return m[0] <= i && i < m[1] })
+			if marked != inMark {
+				if marked {
+					sb.WriteString("<mark>")
+				} else {
+					sb.WriteString("</mark>")
+				}
+				inMark = marked
+			}
+			sb.WriteString(html.EscapeString(string(rs[i])))
+		}
+		if inMark {
+			sb.WriteString("</mark>")
+		}
+		if end < len(rs) {
+			sb.WriteString("…")
+		}
+		return sb.String()
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"words are lowercased", "Hello, World", []string{"hello", "world"}},
+		{"single letters are dropped", "a b cd", []string{"cd"}},
+		{"dotted identifier", "fmt.Sprintf(x)", []string{"fmt", "sprintf", "fmt.sprintf"}},
+		{"three parts", "net.http.Client", []string{"net", "http", "client", "net.http.client"}},
+		{"trailing dot is not a join", "end. next", []string{"end", "next"}},
+		{"underscores and digits", "max_len2", []string{"max_len2"}},
+		{"fullwidth is folded", "ＧＯ言語", []string{"go", "言", "言語", "語"}},
+		{"japanese bigrams", "変数宣言", []string{"変", "変数", "数", "数宣", "宣", "宣言", "言"}},
+		{"katakana with prolonged mark", "ルーム", []string{"ル", "ルー", "ー", "ーム", "ム"}},
+		{"mixed text", "goroutineを使う", []string{"goroutine", "を", "を使", "使", "使う", "う"}},
+		{"punctuation separates runs", "型、値", []string{"型", "値"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, tok := range tokenize(tt.text) {
+				got = append(got, tok.term)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeOffsets(t *testing.T) {
+	text := "使う fmt.Println"
+	want := []token{
+		{"使", 0, 1}, {"使う", 0, 2}, {"う", 1, 2},
+		{"fmt", 3, 6}, {"println", 7, 14}, {"fmt.println", 3, 14},
+	}
+	if got := tokenize(text); !slices.Equal(got, want) {
+		t.Errorf("tokenize(%q) = %v, want %v", text, got, want)
+	}
+}
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"変数", []string{"変数"}},
+		{"変数宣言", []string{"変数", "数宣", "宣言"}},
+		{"型", []string{"型"}},
+		{"型 と 値", []string{"型", "と", "値"}},
+		{"Go go GO", []string{"go"}},
+		{"fmt.Println", []string{"fmt", "println", "fmt.println"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		if got := terms(tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("terms(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	idx := New([]Doc{
+		{LessonID: "1-1", Section: "content", Title: "はじめに", Text: "Goのプログラムはmainパッケージから始まります。"},
+		{LessonID: "1-2", Section: "content", Title: "変数", Text: "変数はvarで宣言します。変数の型は推論されます。"},
+		{LessonID: "1-2", Section: "example-1", Title: "変数の例", Code: `var x int = 1
+fmt.Println(x)`},
+		{LessonID: "2-1", Section: "content", Title: "関数", Text: "関数はfuncで定義します。fmt.Printlnで表示します。"},
+		{LessonID: "2-2", Section: "notes", Title: "ポイント", Text: "メソッドはレシーバを持つ関数です。"},
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string
+	}{
+		{"title outranks text", "関数", 0, []string{"2-1/content", "2-2/notes"}},
+		{"japanese word", "変数", 0, []string{"1-2/content", "1-2/example-1"}},
+		{"every term must match", "変数 func", 0, nil},
+		{"terms across fields", "変数 println", 0, []string{"1-2/example-1"}},
+		{"dotted identifier", "fmt.Println", 0, []string{"1-2/example-1", "2-1/content"}},
+		{"limit", "fmt", 1, []string{"1-2/example-1"}},
+		{"unknown term", "channel", 0, nil},
+		{"empty query", "", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range idx.Search(tt.query, tt.limit) {
+				got = append(got, r.LessonID+"/"+r.Section)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchScoresDescend(t *testing.T) {
+	idx := New([]Doc{
+		{LessonID: "a", Text: "error error error handling"},
+		{LessonID: "b", Text: "error handling with many other words around it in a longer section"},
+		{LessonID: "c", Text: "nothing relevant"},
+	})
+	results := idx.Search("error", 0)
+	if len(results) != 2 || results[0].LessonID != "a" || results[0].Score <= results[1].Score {
+		t.Errorf("Search = %+v, want a ranked above b", results)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  Doc
+		want []string
+		not  []string
+	}{
+		{
+			name: "marks and escapes",
+			doc:  Doc{Text: "use <b>fmt</b> & fmt.Println"},
+			want: []string{"&lt;b&gt;<mark>fmt</mark>&lt;/b&gt;", "&amp;", "<mark>fmt.Println</mark>"},
+		},
+		{
+			name: "falls back to code",
+			doc:  Doc{Text: "no match here", Code: "x := fmt.Sprint(1)"},
+			want: []string{"<mark>fmt</mark>.Sprint"},
+		},
+		{
+			name: "long text is cut around the match",
+			doc:  Doc{Text: strings.Repeat("あ", 100) + "fmt" + strings.Repeat("い", 200)},
+			want: []string{"…", "<mark>fmt</mark>"},
+			not:  []string{strings.Repeat("あ", snippetLead+1), strings.Repeat("い", snippetRunes)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := snippet(tt.doc, map[string]bool{"fmt": true, "fmt.println": true})
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("snippet = %q, want it to contain %q", got, s)
+				}
+			}
+			for _, s := range tt.not {
+				if strings.Contains(got, s) {
+					t.Errorf("snippet = %q, want it not to contain %q", got, s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package search
+
+import (
+	"unicode"
+)
+
+// token is a term and the rune offsets of the text it came from.
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into search terms:
+//
+//   - Runs of letters, digits and underscores are lowercased words. Words
+//     joined by dots, like fmt.Sprintf, also give the dotted identifier.
+//   - Runs of Japanese characters, which are not separated by spaces, give
+//     their overlapping bigrams, and each character on its own so a single
+//     character query still matches.
+//
+// Fullwidth ASCII is folded to ASCII first, so ＧＯ matches go.
+func tokenize(text string) []token {
+	rs := []rune(text)
+	for i, r := range rs {
+		rs[i] = fold(r)
+	}
+
+	var toks []token
+	for i := 0; i < len(rs); {
+		switch {
+		case isCJK(rs[i]):
+			j := i
+			for j < len(rs) && isCJK(rs[j]) {
+				j++
+			}
+			for k := i; k < j; k++ {
+				toks = append(toks, token{string(rs[k]), k, k + 1})
+				if k+1 < j {
+					toks = append(toks, token{string(rs[k : k+2]), k, k + 2})
+				}
+			}
+			i = j
+		case isWord(rs[i]):
+			// A dotted identifier is a sequence of words with single dots
+			// between them.
+			start := i
+			var words []token
+			for {
+				j := i
+				for j < len(rs) && isWord(rs[j]) {
+					j++
+				}
+				words = append(words, token{string(rs[i:j]), i, j})
+				if j+1 < len(rs) && rs[j] == '.' && isWord(rs[j+1]) {
+					i = j + 1
+					continue
+				}
+				i = j
+				break
+			}
+			for _, w := range words {
+				if len(w.term) > 1 {
+					toks = append(toks, w)
+				}
+			}
+			if len(words) > 1 {
+				toks = append(toks, token{string(rs[start:i]), start, i})
+			}
+		default:
+			i++
+		}
+	}
+	return toks
+}
+
+// terms returns the distinct terms of a query in order. A Japanese run of
+// two or more characters is searched by its bigrams only.
+func terms(query string) []string {
+	toks := tokenize(query)
+	seen := make(map[string]bool)
+	var out []string
+	for i, t := range toks {
+		if t.end-t.start == 1 && isCJK([]rune(t.term)[0]) {
+			// A lone character is only a term when no bigram covers it.
+			covered := (i+1 < len(toks) && toks[i+1].start == t.start && toks[i+1].end == t.end+1) ||
+				(i > 0 && toks[i-1].end == t.end && toks[i-1].start == t.start-1)
+			if covered {
+				continue
+			}
+		}
+		if !seen[t.term] {
+			seen[t.term] = true
+			out = append(out, t.term)
+		}
+	}
+	return out
+}
+
+// fold lowercases r and maps fullwidth ASCII to ASCII.
+func fold(r rune) rune {
+	if r >= '！' && r <= '～' {
+		r -= '！' - '!'
+	}
+	return unicode.ToLower(r)
+}
+
+func isWord(r rune) bool {
+	return r == '_' || (r < 0x3000 && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+}
+
+// isCJK reports whether r is a Japanese or Chinese character, including
+// the katakana prolonged sound mark.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー' || r == '々'
+}
@@ -0,0 +1,629 @@
+// Content editor for instructors: edits the draft through the admin API,
+// previews it in the learner app and publishes it.
+const Admin = {
+    TOKEN_KEY: 'go-learning-admin-token',
+    token: '',
+    courses: [],
+    // course is the ID of the course being edited; chapters are its.
+    course: '',
+    chapters: [],
+    lessonId: null,
+
+    async init() {
+        // Follow the learner app's theme; the editor has no toggle of its own.
+        const theme = localStorage.getItem('go-learning-theme');
+        if (theme) document.documentElement.setAttribute('data-theme', theme);
+
+        this.token = sessionStorage.getItem(this.TOKEN_KEY) || '';
+        if (!this.token || !(await this.load())) this._showLogin();
+    },
+
+    async login() {
+        this.token = document.getElementById('tokenInput').value.trim();
+        if (await this.load()) {
+            sessionStorage.setItem(this.TOKEN_KEY, this.token);
+            document.getElementById('adminLogin').style.display = 'none';
+        } else {
+            document.getElementById('adminLoginError').textContent = 'トークンが正しくないか、編集機能が無効です';
+        }
+    },
+
+    _showLogin() {
+        document.getElementById('admin').style.display = 'none';
+        document.getElementById('adminLogin').style.display = '';
+    },
+
+    // _request calls the admin API and returns the response body. Content
+    // that fails to load is reported with the loader's problems.
+    async _request(method, path, body) {
+        const res = await fetch(`/api/admin${path}`, {
+            method,
+            headers: { 'Authorization': `Bearer ${this.token}`, 'Content-Type': 'application/json' },
+            body: body === undefined ? undefined : JSON.stringify(body),
+        });
+        const data = await res.json();
+        if (!res.ok) {
+            const err = new Error(data.error || `${method} ${path} failed`);
+            err.status = res.status;
+            err.problems = data.problems || [];
+            throw err;
+        }
+        return data;
+    },
+
+    // _courseRequest calls an admin API route of the course being edited.
+    _courseRequest(method, path, body) {
+        return this._request(method, `/courses/${encodeURIComponent(this.course)}${path}`, body);
+    },
+
+    // load fetches the draft's courses and status, and reports whether the
+    // token was accepted.
+    async load() {
+        let data;
+        try {
+            data = await this._request('GET', '/content');
+        } catch (e) {
+            if (e.status === 401 || e.status === 404) return false;
+            this._showProblems(e);
+            return true;
+        }
+        this.courses = data.courses;
+        if (!this.courses.some(c => c.id === this.course)) this.course = this.courses[0].id;
+        this.chapters = this.courses.find(c => c.id === this.course).chapters;
+        document.getElementById('admin').style.display = '';
+        this._renderStatus(data);
+        this._renderNav();
+        return true;
+    },
+
+    // _run performs an edit, reloads the chapter list and reports failures.
+    async _run(edit) {
+        this._showProblems(null);
+        try {
+            const result = await edit();
+            await this.load();
+            return result;
+        } catch (e) {
+            this._showProblems(e);
+            return null;
+        }
+    },
+
+    _showProblems(err) {
+        const el = document.getElementById('adminProblems');
+        if (!err) {
+            el.style.display = 'none';
+            return;
+        }
+        el.innerHTML = `
+            <div class="admin-problems-title">${this._esc(err.message)}</div>
+            ${err.problems && err.problems.length
+                ? `<ul>${err.problems.map(p => `<li>${this._esc(p)}</li>`).join('')}</ul>`
+                : ''}
+        `;
+        el.style.display = '';
+    },
+
+    _renderStatus(data) {
+        const status = data.status;
+        const fmt = (t) => t ? new Date(t).toLocaleString('ja-JP') : '—';
+        document.getElementById('adminStatus').innerHTML = `
+            <span>下書き: ${fmt(status.draftAt)}</span>
+            <span>公開: ${fmt(status.publishedAt)}</span>
+            ${data.unpublished ? '<span class="admin-badge">未公開の変更あり</span>' : ''}
+            ${data.previewUrl ? `<a class="btn btn-secondary" href="${data.previewUrl}" target="_blank">プレビュー</a>` : ''}
+            <button class="btn btn-primary" onclick="Admin.publish()" ${data.unpublished ? '' : 'disabled'}>公開</button>
+            <button class="btn btn-secondary" onclick="Admin.discard()" ${status.draftAt ? '' : 'disabled'}>下書きを破棄</button>
+        `;
+    },
+
+    _renderNav() {
+        const chapters = this.chapters.map(ch => `
+            <div class="admin-chapter">
+                <div class="admin-chapter-title" onclick="Admin.editChapter(${ch.id})">
+                    第${ch.id}章 ${this._esc(ch.title)}
+                </div>
+                <ul>
+                    ${ch.lessons.map((l, i) => `
+                        <li class="${l.id === this.lessonId ? 'active' : ''}">
+                            <span onclick="Admin.editLesson('${l.id}')">${l.id} ${this._esc(l.title)}</span>
+                            <button onclick="Admin.move(${ch.id}, ${i}, -1)" ${i === 0 ? 'disabled' : ''} title="上へ">↑</button>
+                            <button onclick="Admin.move(${ch.id}, ${i}, 1)" ${i === ch.lessons.length - 1 ? 'disabled' : ''} title="下へ">↓</button>
+                        </li>
+                    `).join('')}
+                </ul>
+                <button class="admin-add" onclick="Admin.newLesson(${ch.id})">+ レッスンを追加</button>
+            </div>
+        `).join('');
+        document.getElementById('adminNav').innerHTML = `
+            <div class="admin-course">
+                <select onchange="Admin.selectCourse(this.value)" title="コース">
+                    ${this.courses.map(c => `<option value="${this._esc(c.id)}" ${c.id === this.course ? 'selected' : ''}>${this._esc(c.title)}</option>`).join('')}
+                </select>
+                <button class="admin-add" onclick="Admin.editCourse()">コースを編集</button>
+                <button class="admin-add" onclick="Admin.newCourse()">+ コースを追加</button>
+                <button class="admin-add" onclick="Admin.showImport()">パッケージから取り込む</button>
+            </div>
+            ${chapters}
+            <button class="admin-add" onclick="Admin.newChapter()">+ チャプターを追加</button>
+        `;
+    },
+
+    async move(chapterId, index, delta) {
+        const ids = this.chapters.find(ch => ch.id === chapterId).lessons.map(l => l.id);
+        [ids[index], ids[index + delta]] = [ids[index + delta], ids[index]];
+        await this._run(() => this._courseRequest('PUT', `/chapters/${chapterId}/order`, { lessons: ids }));
+    },
+
+    async publish() {
+        if (!confirm('下書きを公開します。学習者に表示される教材が置き換わります。')) return;
+        await this._run(() => this._request('POST', '/publish'));
+    },
+
+    async discard() {
+        if (!confirm('下書きを破棄して公開中の教材に戻します。')) return;
+        this.lessonId = null;
+        await this._run(() => this._request('DELETE', '/draft'));
+        this._main('<p class="admin-hint">下書きを破棄しました。</p>');
+    },
+
+    _main(html) {
+        document.getElementById('adminMain').innerHTML = html;
+    },
+
+    // Courses
+
+    selectCourse(id) {
+        this.course = id;
+        this.chapters = this.courses.find(c => c.id === id).chapters;
+        this.lessonId = null;
+        this._renderNav();
+        this._main('<p class="admin-hint">左の一覧からレッスンを選ぶか、新しいレッスン・チャプターを作成してください。</p>');
+    },
+
+    editCourse() {
+        const c = this.courses.find(c => c.id === this.course);
+        this.lessonId = null;
+        this._renderNav();
+        this._main(`
+            <h2>コース ${this._esc(c.id)} の編集</h2>
+            ${this._courseFields(c)}
+            <div class="admin-actions">
+                <button class="btn btn-primary" onclick="Admin.saveCourse()">保存</button>
+                <button class="btn btn-secondary" onclick="Admin.exportCourse()">パッケージを書き出す</button>
+                <button class="btn btn-secondary" onclick="Admin.exportBook('epub')">EPUB を書き出す</button>
+                <button class="btn btn-secondary" onclick="Admin.exportBook('html')">印刷用 HTML を書き出す</button>
+                <label><input type="checkbox" id="exportDraft"> 未公開の下書きを書き出す</label>
+                <button class="btn btn-secondary" onclick="Admin.deleteCourse()">コースを削除</button>
+            </div>
+        `);
+    },
+
+    newCourse() {
+        const next = Math.max(0, ...this.courses.map(c => c.order)) + 1;
+        this.lessonId = null;
+        this._renderNav();
+        this._main(`
+            <h2>新しいコース</h2>
+            <label>ID（英小文字・数字・ハイフン） <input id="courseId"></label>
+            ${this._courseFields({ title: '', description: '', order: next })}
+            <h3>最初のチャプター</h3>
+            <label>タイトル <input id="firstChapterTitle"></label>
+            <h3>最初のレッスン</h3>
+            <label>ID <input id="firstLessonId" value="1-1"></label>
+            <label>タイトル <input id="firstLessonTitle"></label>
+            <label>本文（Markdown）<textarea id="firstLessonContent" rows="8"></textarea></label>
+            <div class="admin-actions">
+                <button class="btn btn-primary" onclick="Admin.createCourse()">作成</button>
+            </div>
+        `);
+    },
+
+    _courseFields(c) {
+        return `
+            <label>タイトル <input id="courseTitle" value="${this._esc(c.title)}"></label>
+            <label>説明 <textarea id="courseDescription" rows="3">${this._esc(c.description)}</textarea></label>
+            <label>一覧での順序 <input type="number" id="courseOrder" value="${c.order}"></label>
+        `;
+    },
+
+    _courseForm() {
+        return {
+            title: document.getElementById('courseTitle').value,
+            description: document.getElementById('courseDescription').value,
+            order: Number(document.getElementById('courseOrder').value),
+        };
+    },
+
+    async saveCourse() {
+        await this._run(() => this._courseRequest('PUT', '', this._courseForm()));
+    },
+
+    async createCourse() {
+        const id = document.getElementById('courseId').value.trim();
+        const body = {
+            id,
+            ...this._courseForm(),
+            chapters: [{
+                id: 1,
+                title: document.getElementById('firstChapterTitle').value,
+                description: '',
+                lessons: [{
+                    id: document.getElementById('firstLessonId').value.trim(),
+                    title: document.getElementById('firstLessonTitle').value,
+                    content: document.getElementById('firstLessonContent').value,
+                    examples: [],
+                }],
+            }],
+        };
+        if (await this._run(() => this._request('POST', '/courses', body))) {
+            this.selectCourse(id);
+            this.editCourse();
+        }
+    },
+
+    async deleteCourse() {
+        if (!confirm(`コース ${this.course} をすべての教材ごと削除します。`)) return;
+        if (await this._run(() => this._courseRequest('DELETE', ''))) {
+            this._main('<p class="admin-hint">コースを削除しました。</p>');
+        }
+    },
+
+    // Course packages and books
+
+    // exportCourse downloads the course as a package.
+    exportCourse() {
+        return this._download('package', `${this.course}.zip`);
+    },
+
+    // exportBook downloads the course as an EPUB book or as a handbook
+    // page to print.
+    exportBook(format) {
+        return this._download(`book?format=${format}`, `${this.course}.${format}`);
+    },
+
+    // _download saves a file of the published course, or of the draft if
+    // the export draft box is checked; the download needs the token, so it
+    // goes through fetch rather than a link.
+    async _download(path, filename) {
+        this._showProblems(null);
+        if (document.getElementById('exportDraft')?.checked) {
+            path += `${path.includes('?') ? '&' : '?'}draft=true`;
+        }
+        const res = await fetch(`/api/admin/courses/${encodeURIComponent(this.course)}/${path}`, {
+            headers: { 'Authorization': `Bearer ${this.token}` },
+        });
+        if (!res.ok) {
+            this._showProblems(new Error((await res.json()).error || 'export failed'));
+            return;
+        }
+        const a = document.createElement('a');
+        a.href = URL.createObjectURL(await res.blob());
+        a.download = filename;
+        a.click();
+        URL.revokeObjectURL(a.href);
+    },
+
+    showImport() {
+        this.lessonId = null;
+        this._renderNav();
+        this._main(`
+            <h2>パッケージから取り込む</h2>
+            <p class="admin-hint">別の環境で書き出したコースのパッケージ（.zip）を下書きに取り込みます。公開するまで学習者には表示されません。</p>
+            <label>パッケージ <input type="file" id="packageFile" accept=".zip,application/zip"></label>
+            <label><input type="checkbox" id="packageReplace"> 同じ ID のコースがあれば置き換える</label>
+            <div class="admin-actions">
+                <button class="btn btn-secondary" onclick="Admin.importCourse(true)">変更内容を確認</button>
+                <button class="btn btn-primary" onclick="Admin.importCourse(false)">取り込む</button>
+            </div>
+            <div id="importPlan"></div>
+        `);
+    },
+
+    // importCourse uploads the chosen package; a dry run only shows what
+    // the import would change.
+    async importCourse(dryRun) {
+        const file = document.getElementById('packageFile').files[0];
+        if (!file) return;
+        const replace = document.getElementById('packageReplace').checked;
+        this._showProblems(null);
+        const res = await fetch(`/api/admin/courses/import?dryRun=${dryRun}&replace=${replace}`, {
+            method: 'POST',
+            headers: { 'Authorization': `Bearer ${this.token}`, 'Content-Type': 'application/zip' },
+            body: file,
+        });
+        const data = await res.json();
+        if (!res.ok) {
+            const err = new Error(data.error || 'import failed');
+            err.problems = data.problems || [];
+            this._showProblems(err);
+            if (data.plan) this._renderPlan(data.plan);
+            return;
+        }
+        this._renderPlan(data);
+        if (!dryRun) {
+            this.course = data.course.id;
+            await this.load();
+            this._showProblems(null);
+        }
+    },
+
+    _renderPlan(plan) {
+        const list = (title, set) => `
+            <h3>${title}: 追加 ${set.added.length}・変更 ${set.changed.length}・削除 ${set.removed.length}</h3>
+            <ul class="admin-plan">
+                ${set.added.map(n => `<li class="added">+ ${this._esc(n)}</li>`).join('')}
+                ${set.changed.map(n => `<li class="changed">~ ${this._esc(n)}</li>`).join('')}
+                ${set.removed.map(n => `<li class="removed">- ${this._esc(n)}</li>`).join('')}
+            </ul>`;
+        document.getElementById('importPlan').innerHTML = `
+            <h3>${this._esc(plan.course.id)}「${this._esc(plan.course.title)}」${plan.exists ? '（既存のコースを置き換え）' : '（新しいコース）'}</h3>
+            ${list('レッスン', plan.lessons)}
+            ${list('ファイル', plan.files)}
+        `;
+    },
+
+    // Chapters
+
+    editChapter(id) {
+        const ch = this.chapters.find(c => c.id === id);
+        this.lessonId = null;
+        this._renderNav();
+        this._main(`
+            <h2>第${ch.id}章の編集</h2>
+            ${this._chapterFields(ch)}
+            <div class="admin-actions">
+                <button class="btn btn-primary" onclick="Admin.saveChapter(${id})">保存</button>
+                <button class="btn btn-secondary" onclick="Admin.deleteChapter(${id})">チャプターを削除</button>
+            </div>
+        `);
+    },
+
+    newChapter() {
+        const next = Math.max(0, ...this.chapters.map(c => c.id)) + 1;
+        this.lessonId = null;
+        this._renderNav();
+        this._main(`
+            <h2>新しいチャプター</h2>
+            <label>番号 <input type="number" id="chapterId" value="${next}"></label>
+            ${this._chapterFields({ title: '', description: '', gating: '' })}
+            <h3>最初のレッスン</h3>
+            <label>ID <input id="firstLessonId" value="${next}-1"></label>
+            <label>タイトル <input id="firstLessonTitle"></label>
+            <label>本文（Markdown）<textarea id="firstLessonContent" rows="8"></textarea></label>
+            <div class="admin-actions">
+                <button class="btn btn-primary" onclick="Admin.createChapter()">作成</button>
+            </div>
+        `);
+    },
+
+    _chapterFields(ch) {
+        return `
+            <label>タイトル <input id="chapterTitle" value="${this._esc(ch.title)}"></label>
+            <label>説明 <textarea id="chapterDescription" rows="3">${this._esc(ch.description)}</textarea></label>
+            <label>前提の扱い
+                <select id="chapterGating">
+                    <option value="" ${ch.gating !== 'strict' ? 'selected' : ''}>advisory（推奨のみ）</option>
+                    <option value="strict" ${ch.gating === 'strict' ? 'selected' : ''}>strict（ロック）</option>
+                </select>
+            </label>
+        `;
+    },
+
+    _chapterForm() {
+        return {
+            title: document.getElementById('chapterTitle').value,
+            description: document.getElementById('chapterDescription').value,
+            gating: document.getElementById('chapterGating').value,
+        };
+    },
+
+    async saveChapter(id) {
+        const ch = this.chapters.find(c => c.id === id);
+        await this._run(() => this._courseRequest('PUT', `/chapters/${id}`, { ...ch, ...this._chapterForm(), lessons: undefined }));
+    },
+
+    async createChapter() {
+        const id = Number(document.getElementById('chapterId').value);
+        const body = {
+            id,
+            ...this._chapterForm(),
+            lessons: [{
+                id: document.getElementById('firstLessonId').value.trim(),
+                title: document.getElementById('firstLessonTitle').value,
+                content: document.getElementById('firstLessonContent').value,
+                examples: [],
+            }],
+        };
+        if (await this._run(() => this._courseRequest('POST', '/chapters', body))) this.editChapter(id);
+    },
+
+    async deleteChapter(id) {
+        if (!confirm(`第${id}章をレッスン・クイズごと削除します。`)) return;
+        if (await this._run(() => this._courseRequest('DELETE', `/chapters/${id}`))) {
+            this._main('<p class="admin-hint">チャプターを削除しました。</p>');
+        }
+    },
+
+    // Lessons
+
+    async editLesson(id) {
+        let data;
+        try {
+            data = await this._courseRequest('GET', `/lessons/${encodeURIComponent(id)}`);
+        } catch (e) {
+            this._showProblems(e);
+            return;
+        }
+        this.lessonId = id;
+        this._renderNav();
+        this._renderLesson(data.lesson, data.quiz, false);
+    },
+
+    newLesson(chapterId) {
+        const ch = this.chapters.find(c => c.id === chapterId);
+        this.lessonId = null;
+        this._renderNav();
+        this._renderLesson({
+            id: `${chapterId}-${ch.lessons.length + 1}`,
+            chapterId,
+            title: '',
+            content: '',
+            examples: [],
+        }, null, true);
+    },
+
+    _renderLesson(l, quiz, isNew) {
+        const ex = l.exercise || { title: '', description: '', starterCode: '', expectedOutput: '' };
+        this._main(`
+            <h2>${isNew ? '新しいレッスン' : `レッスン ${l.id} の編集`}</h2>
+            ${l.version ? `<p class="admin-hint">バージョン ${l.version}</p>` : ''}
+            <div class="admin-row">
+                <label>ID <input id="lessonId" value="${this._esc(l.id)}" ${isNew ? '' : 'disabled'}></label>
+                <label>チャプター
+                    <select id="lessonChapter">
+                        ${this.chapters.map(c => `<option value="${c.id}" ${c.id === l.chapterId ? 'selected' : ''}>第${c.id}章</option>`).join('')}
+                    </select>
+                </label>
+            </div>
+            <label>タイトル <input id="lessonTitle" value="${this._esc(l.title)}"></label>
+            <label>前提（カンマ区切り）<input id="lessonRequires" value="${this._esc((l.requires || []).join(', '))}"></label>
+            <label>本文（Markdown）<textarea id="lessonContent" rows="14">${this._esc(l.content)}</textarea></label>
+
+            <h3>コード例</h3>
+            <div id="lessonExamples">${l.examples.map(e => this._exampleFields(e)).join('')}</div>
+            <button class="admin-add" onclick="Admin.addExample()">+ コード例を追加</button>
+
+            <h3>演習</h3>
+            <label>タイトル <input id="exerciseTitle" value="${this._esc(ex.title)}"></label>
+            <label>説明 <textarea id="exerciseDescription" rows="3">${this._esc(ex.description)}</textarea></label>
+            <label>初期コード <textarea class="admin-code" id="exerciseStarter" rows="10">${this._esc(ex.starterCode)}</textarea></label>
+            <label>期待する出力 <textarea class="admin-code" id="exerciseOutput" rows="3">${this._esc(ex.expectedOutput || '')}</textarea></label>
+
+            <h3>ポイント（1行に1つ）</h3>
+            <textarea id="lessonNotes" rows="5">${this._esc((l.notes || []).join('\n'))}</textarea>
+
+            <h3>更新履歴（1行に「YYYY-MM-DD 内容」）</h3>
+            <textarea id="lessonChangelog" rows="3">${this._esc((l.changelog || []).map(c => `${c.date} ${c.note}`).join('\n'))}</textarea>
+
+            <div class="admin-actions">
+                <button class="btn btn-primary" onclick="Admin.saveLesson(${isNew})">保存</button>
+                ${isNew ? '' : '<button class="btn btn-secondary" onclick="Admin.deleteLesson()">レッスンを削除</button>'}
+            </div>
+
+            ${isNew ? '' : `
+                <h3>クイズ（JSON）</h3>
+                <p class="admin-hint">問題は questions に並べます。空にして保存するとクイズを削除します。</p>
+                <textarea class="admin-code" id="lessonQuiz" rows="16">${quiz ? this._esc(JSON.stringify(quiz, null, 2)) : ''}</textarea>
+                <div class="admin-actions">
+                    <button class="btn btn-primary" onclick="Admin.saveQuiz()">クイズを保存</button>
+                </div>
+            `}
+        `);
+    },
+
+    _exampleFields(e) {
+        return `
+            <div class="admin-example">
+                <div class="admin-row">
+                    <input class="example-title" placeholder="タイトル" value="${this._esc(e.title)}">
+                    <select class="example-check">
+                        <option value="" ${!e.check ? 'selected' : ''}>出力を検証</option>
+                        <option value="compile" ${e.check === 'compile' ? 'selected' : ''}>コンパイルのみ</option>
+                        <option value="none" ${e.check === 'none' ? 'selected' : ''}>検証しない</option>
+                    </select>
+                    <label title="実行するたびに出力が変わる例は、出力を比較せず実行できることだけを検証します">
+                        <input type="checkbox" class="example-nondeterministic" ${e.nondeterministic ? 'checked' : ''}> 出力が毎回変わる
+                    </label>
+                    <button onclick="this.closest('.admin-example').remove()" title="削除">✕</button>
+                </div>
+                <textarea class="admin-code example-code" rows="10">${this._esc(e.code)}</textarea>
+                ${e.output ? `<pre class="admin-example-output" title="記録済みの出力">${this._esc(e.output)}</pre>` : ''}
+            </div>
+        `;
+    },
+
+    addExample() {
+        document.getElementById('lessonExamples')
+            .insertAdjacentHTML('beforeend', this._exampleFields({ title: '', code: '', check: '' }));
+    },
+
+    _lessonForm() {
+        const lines = (id) => document.getElementById(id).value.split('\n').map(s => s.trim()).filter(Boolean);
+        const examples = [...document.querySelectorAll('.admin-example')].map(el => ({
+            title: el.querySelector('.example-title').value,
+            code: el.querySelector('.example-code').value,
+            check: el.querySelector('.example-check').value,
+            nondeterministic: el.querySelector('.example-nondeterministic').checked,
+        }));
+        const exercise = {
+            title: document.getElementById('exerciseTitle').value,
+            description: document.getElementById('exerciseDescription').value,
+            starterCode: document.getElementById('exerciseStarter').value,
+            expectedOutput: document.getElementById('exerciseOutput').value,
+        };
+        const hasExercise = Object.values(exercise).some(v => v.trim() !== '');
+        return {
+            id: document.getElementById('lessonId').value.trim(),
+            chapterId: Number(document.getElementById('lessonChapter').value),
+            title: document.getElementById('lessonTitle').value,
+            requires: document.getElementById('lessonRequires').value.split(',').map(s => s.trim()).filter(Boolean),
+            content: document.getElementById('lessonContent').value,
+            examples,
+            exercise: hasExercise ? exercise : undefined,
+            notes: lines('lessonNotes'),
+            changelog: lines('lessonChangelog').map(line => {
+                const i = line.indexOf(' ');
+                return i < 0 ? { date: line, note: '' } : { date: line.slice(0, i), note: line.slice(i + 1).trim() };
+            }),
+        };
+    },
+
+    async saveLesson(isNew) {
+        const lesson = this._lessonForm();
+        // Keep the completion rule, which the form does not edit.
+        if (!isNew) {
+            const current = await this._courseRequest('GET', `/lessons/${encodeURIComponent(lesson.id)}`);
+            lesson.completion = current.lesson.completion;
+        }
+        const saved = await this._run(() => isNew
+            ? this._courseRequest('POST', '/lessons', lesson)
+            : this._courseRequest('PUT', `/lessons/${encodeURIComponent(lesson.id)}`, lesson));
+        if (saved) this.editLesson(lesson.id);
+    },
+
+    async deleteLesson() {
+        const id = this.lessonId;
+        if (!confirm(`レッスン ${id} をクイズごと削除します。`)) return;
+        if (await this._run(() => this._courseRequest('DELETE', `/lessons/${encodeURIComponent(id)}`))) {
+            this.lessonId = null;
+            this._renderNav();
+            this._main('<p class="admin-hint">レッスンを削除しました。</p>');
+        }
+    },
+
+    async saveQuiz() {
+        const id = this.lessonId;
+        const text = document.getElementById('lessonQuiz').value.trim();
+        let quiz;
+        try {
+            quiz = text ? JSON.parse(text) : null;
+        } catch (e) {
+            this._showProblems(new Error(`クイズの JSON が正しくありません: ${e.message}`));
+            return;
+        }
+        const path = `/lessons/${encodeURIComponent(id)}/quiz`;
+        const saved = await this._run(() => quiz
+            ? this._courseRequest('PUT', path, quiz)
+            : this._courseRequest('DELETE', path));
+        if (saved) this.editLesson(id);
+    },
+
+    _esc(text) {
+        const div = document.createElement('div');
+        div.textContent = text == null ? '' : String(text);
+        return div.innerHTML.replace(/"/g, '&quot;');
+    },
+};
+
+document.addEventListener('DOMContentLoaded', () => Admin.init());
@@ -1,25 +1,202 @@
 // API client for communicating with the Go backend
 const API = {
-    async getChapters() {
-        const res = await fetch('/api/chapters');
+    // Content language sent as Accept-Language; empty lets the browser's
+    // languages decide.
+    lang: '',
+    // Preview token of the draft content, taken from a preview link
+    // (/?preview=TOKEN) and sent with every GET request. Previews are
+    // read-only; see _fetch.
+    preview: new URLSearchParams(location.search).get('preview') || '',
+    COURSE_KEY: 'go-learning-course',
+    // ID of the course the course routes are for; see setCourse.
+    course: localStorage.getItem('go-learning-course') || '',
+
+    setCourse(id) {
+        this.course = id;
+        localStorage.setItem(this.COURSE_KEY, id);
+    },
+
+    // URL of a route of the current course, e.g. _course('/chapters').
+    _course(path) {
+        return `/api/courses/${encodeURIComponent(this.course)}${path}`;
+    },
+
+    _fetch(url, options = {}) {
+        if (this.preview) {
+            if (options.method && options.method !== 'GET') {
+                // Nothing is recorded for a course while previewing its
+                // draft; other writes, such as logging in, go through
+                // without the token.
+                if (url.startsWith('/api/courses/')) {
+                    return Promise.reject(new Error('Progress is not saved while previewing'));
+                }
+            } else {
+                url += `${url.includes('?') ? '&' : '?'}preview=${encodeURIComponent(this.preview)}`;
+            }
+        }
+        if (!this.lang) return fetch(url, options);
+        const headers = { ...options.headers, 'Accept-Language': this.lang };
+        return fetch(url, { ...options, headers });
+    },
+
+    // The course catalog; with a username, each course counts the lessons
+    // the user completed.
+    async getCourses(username) {
+        const query = username ? `?username=${encodeURIComponent(username)}` : '';
+        const res = await this._fetch(`/api/courses${query}`);
+        if (!res.ok) throw new Error('Failed to fetch courses');
+        return res.json();
+    },
+
+    // With a username, lessons carry the user's lock state.
+    async getChapters(username) {
+        const query = username ? `?username=${encodeURIComponent(username)}` : '';
+        const res = await this._fetch(this._course(`/chapters${query}`));
         if (!res.ok) throw new Error('Failed to fetch chapters');
         return res.json();
     },
 
-    async getLesson(id) {
-        const res = await fetch(`/api/lessons/${id}`);
-        if (!res.ok) throw new Error(`Failed to fetch lesson ${id}`);
+    // A lesson locked by its prerequisites fails with the error's missing
+    // set to the lessons to complete first.
+    async getLesson(id, username) {
+        const query = username ? `?username=${encodeURIComponent(username)}` : '';
+        const res = await this._fetch(this._course(`/lessons/${id}${query}`));
+        const data = await res.json();
+        if (!res.ok) {
+            const err = new Error(data.error || `Failed to fetch lesson ${id}`);
+            err.missing = data.missing;
+            throw err;
+        }
+        return data;
+    },
+
+    async getChangelog(lessonId) {
+        const res = await this._fetch(this._course(`/lessons/${lessonId}/changelog`));
+        if (!res.ok) throw new Error(`Failed to fetch changelog for ${lessonId}`);
+        return res.json();
+    },
+
+    // Completed lessons rewritten since the learner completed them.
+    async getUpdatedLessons(username) {
+        const res = await this._fetch(this._course(`/progress/${encodeURIComponent(username)}/updated`));
+        if (!res.ok) throw new Error('Failed to fetch updated lessons');
+        return res.json();
+    },
+
+    async search(query) {
+        const res = await this._fetch(this._course(`/search?q=${encodeURIComponent(query)}`));
+        const data = await res.json();
+        if (!res.ok) throw new Error(data.error || 'Search failed');
+        return data;
+    },
+
+    async getGlossary() {
+        const res = await this._fetch(this._course('/glossary'));
+        if (!res.ok) throw new Error('Failed to fetch glossary');
+        return res.json();
+    },
+
+    async getGlossaryTerm(id) {
+        const res = await this._fetch(this._course(`/glossary/${encodeURIComponent(id)}`));
+        if (!res.ok) throw new Error(`Failed to fetch glossary term ${id}`);
+        return res.json();
+    },
+
+    // Standard library documentation of a package, such as "net/http", or
+    // of one of its declarations, such as "strings.Builder.WriteString".
+    async getDoc(ref) {
+        const res = await this._fetch(`/api/doc/${ref.split('/').map(encodeURIComponent).join('/')}`);
+        const data = await res.json();
+        if (!res.ok) throw new Error(data.error || `Failed to fetch documentation of ${ref}`);
+        return data;
+    },
+
+    async getQuiz(lessonId, username) {
+        const res = await this._fetch(this._course(`/quiz/${lessonId}?username=${encodeURIComponent(username)}`));
+        const data = await res.json();
+        if (!res.ok) throw new Error(data.error || `Failed to fetch quiz for ${lessonId}`);
+        return data;
+    },
+
+    async submitQuiz(lessonId, username, sessionId, answers) {
+        const res = await this._fetch(this._course(`/quiz/${lessonId}/submit`), {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ username, sessionId, answers }),
+        });
+        if (!res.ok) throw new Error(`Failed to submit quiz for ${lessonId}`);
+        return res.json();
+    },
+
+    async getAttempts(username, lessonId) {
+        const res = await this._fetch(
+            this._course(`/attempts/${encodeURIComponent(username)}/${encodeURIComponent(lessonId)}`),
+        );
+        if (!res.ok) throw new Error(`Failed to fetch attempts for ${lessonId}`);
         return res.json();
     },
 
-    async getQuiz(lessonId) {
-        const res = await fetch(`/api/quiz/${lessonId}`);
-        if (!res.ok) throw new Error(`Failed to fetch quiz for ${lessonId}`);
+    async getDueReviews(username, limit = 20) {
+        const res = await this._fetch(this._course(`/review/due?username=${encodeURIComponent(username)}&limit=${limit}`));
+        if (!res.ok) throw new Error('Failed to fetch review queue');
+        return res.json();
+    },
+
+    async gradeReview(username, itemId, body) {
+        const res = await this._fetch(this._course('/review/grade'), {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ username, itemId, ...body }),
+        });
+        if (!res.ok) throw new Error('Failed to grade review');
+        return res.json();
+    },
+
+    async getExams(username) {
+        const res = await this._fetch(this._course(`/exams?username=${encodeURIComponent(username)}`));
+        if (!res.ok) throw new Error('Failed to fetch exams');
+        return res.json();
+    },
+
+    async startExam(examId, username) {
+        const res = await this._fetch(this._course(`/exams/${encodeURIComponent(examId)}/start`), {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ username }),
+        });
+        const data = await res.json();
+        if (!res.ok) throw new Error(data.error || `Failed to start exam ${examId}`);
+        return data;
+    },
+
+    async getExamSession(sessionId, username) {
+        const res = await this._fetch(this._course(`/exams/sessions/${sessionId}?username=${encodeURIComponent(username)}`));
+        if (!res.ok) throw new Error(`Failed to fetch exam session ${sessionId}`);
+        return res.json();
+    },
+
+    async saveExamAnswers(sessionId, username, answers) {
+        const res = await this._fetch(this._course(`/exams/sessions/${sessionId}/answers`), {
+            method: 'PUT',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ username, answers }),
+        });
+        if (!res.ok) throw new Error(`Failed to save exam session ${sessionId}`);
+        return res.json();
+    },
+
+    async submitExam(sessionId, username, answers) {
+        const res = await this._fetch(this._course(`/exams/sessions/${sessionId}/submit`), {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ username, answers }),
+        });
+        if (!res.ok) throw new Error(`Failed to submit exam session ${sessionId}`);
         return res.json();
     },
 
     async login(username) {
-        const res = await fetch('/api/login', {
+        const res = await this._fetch('/api/login', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ username }),
@@ -31,23 +208,50 @@ const API = {
         return res.json();
     },
 
+    async setLocale(username, locale) {
+        const res = await this._fetch(`/api/users/${encodeURIComponent(username)}/locale`, {
+            method: 'PUT',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ locale }),
+        });
+        const data = await res.json();
+        if (!res.ok) throw new Error(data.error || 'Failed to save language');
+        return data;
+    },
+
     async getProgress(username) {
-        const res = await fetch(`/api/progress/${encodeURIComponent(username)}`);
+        const res = await this._fetch(this._course(`/progress/${encodeURIComponent(username)}`));
         if (!res.ok) throw new Error('Failed to fetch progress');
         return res.json();
     },
 
-    async markCompleted(username, lessonId) {
-        const res = await fetch(
-            `/api/progress/${encodeURIComponent(username)}/${encodeURIComponent(lessonId)}`,
-            { method: 'POST' },
+    async markRead(username, lessonId) {
+        return this._postProgress(username, lessonId, 'read');
+    },
+
+    async submitExercise(username, lessonId, code) {
+        return this._postProgress(username, lessonId, 'exercise', { code });
+    },
+
+    async markExampleRun(username, lessonId, index) {
+        return this._postProgress(username, lessonId, `examples/${index}`);
+    },
+
+    async _postProgress(username, lessonId, component, body) {
+        const res = await this._fetch(
+            this._course(`/progress/${encodeURIComponent(username)}/${encodeURIComponent(lessonId)}/${component}`),
+            {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: body ? JSON.stringify(body) : undefined,
+            },
         );
         if (!res.ok) throw new Error('Failed to save progress');
         return res.json();
     },
 
     async resetProgress(username) {
-        const res = await fetch(`/api/progress/${encodeURIComponent(username)}`, {
+        const res = await this._fetch(this._course(`/progress/${encodeURIComponent(username)}`), {
             method: 'DELETE',
         });
         if (!res.ok) throw new Error('Failed to reset progress');
@@ -6,6 +6,8 @@ const App = {
     async init() {
         Theme.init();
         this._setupMobileMenu();
+        Dev.init();
+        if (API.preview) document.getElementById('previewBanner').style.display = '';
 
         // Check login state
         if (Progress.isLoggedIn()) {
@@ -24,8 +26,9 @@ const App = {
         try {
             const result = await API.login(username);
             Progress.setUsername(result.username);
-            Progress.loadFromArray(result.progress);
             this._updateUsernameDisplay(result.username);
+            API.lang = result.locale || '';
+            Components.renderLanguageSelect(result.locales, API.lang);
         } catch (e) {
             console.error('Login failed:', e);
             Progress.clearUsername();
@@ -33,14 +36,48 @@ const App = {
             return;
         }
 
+        await this._loadCourse();
+        this._hideLoginModal();
+    },
+
+    // Load the catalog, keeping the chosen course if it still exists, and
+    // the current course's progress, exams, sidebar and review count.
+    async _loadCourse() {
+        try {
+            const courses = await API.getCourses(Progress.getUsername());
+            if (!courses.some(c => c.id === API.course) && courses.length) {
+                API.setCourse(courses[0].id);
+            }
+            Components.renderCourses(courses, API.course);
+        } catch (e) {
+            console.error('Failed to load courses:', e);
+        }
+
+        await Promise.all([Progress.refresh(), Exam.loadStatuses()]);
+
         try {
-            const chapters = await API.getChapters();
+            const chapters = await API.getChapters(Progress.getUsername());
             Components.renderSidebar(chapters);
         } catch (e) {
             console.error('Failed to load chapters:', e);
         }
 
-        this._hideLoginModal();
+        Components.updateReviewCount(await Review.refreshCount());
+    },
+
+    // Switch to another course; lessons of the previous course no longer
+    // apply, so the welcome screen of the new course is shown.
+    async changeCourse(id) {
+        if (id === API.course) return;
+        API.setCourse(id);
+        this.currentLessonId = null;
+        this.currentLesson = null;
+        await this._loadCourse();
+        if (window.location.hash) {
+            window.location.hash = '';
+        } else {
+            Components.showView('welcome');
+        }
     },
 
     _showLoginModal() {
@@ -84,6 +121,17 @@ const App = {
         window.location.reload();
     },
 
+    async changeLanguage(locale) {
+        try {
+            await API.setLocale(Progress.getUsername(), locale);
+        } catch (e) {
+            console.error('Failed to save language:', e);
+            return;
+        }
+        API.lang = locale;
+        await this.reloadContent();
+    },
+
     _updateUsernameDisplay(username) {
         const el = document.getElementById('usernameDisplay');
         if (el) {
@@ -94,16 +142,30 @@ const App = {
 
     _handleRoute() {
         const hash = window.location.hash.slice(1); // remove #
+        this._stopExamTimer();
         if (hash.startsWith('lesson/')) {
-            const id = hash.replace('lesson/', '');
-            this.navigateTo(id, false);
+            // lesson/1-2 or lesson/1-2/example-1 to open at a section
+            const [id, section] = hash.replace('lesson/', '').split('/');
+            this.navigateTo(id, false, section && decodeURIComponent(section));
+        } else if (hash.startsWith('search/')) {
+            this.showSearch(decodeURIComponent(hash.replace('search/', '')));
+        } else if (hash === 'glossary') {
+            this.showGlossary();
+        } else if (hash.startsWith('glossary/')) {
+            this.showGlossary(decodeURIComponent(hash.replace('glossary/', '')));
+        } else if (hash.startsWith('doc/')) {
+            this.showDoc(decodeURIComponent(hash.replace('doc/', '')));
+        } else if (hash === 'review') {
+            this.startReview();
+        } else if (hash.startsWith('exam/')) {
+            this.resumeExam(Number(hash.replace('exam/', '')));
         } else if (hash === '' || hash === '/') {
             Components.showView('welcome');
             this.currentLessonId = null;
         }
     },
 
-    async navigateTo(lessonId, updateHash = true) {
+    async navigateTo(lessonId, updateHash = true, section = null) {
         if (updateHash) {
             window.location.hash = `lesson/${lessonId}`;
             // hashchange handler will call navigateTo again with false
@@ -112,23 +174,41 @@ const App = {
 
         try {
             this.currentLessonId = lessonId;
-            this.currentLesson = await API.getLesson(lessonId);
+            this.currentLesson = await API.getLesson(lessonId, Progress.getUsername());
             Components.renderLesson(this.currentLesson);
             Components.showView('lesson');
             Components.updateSidebarActive(lessonId);
+            this._watchLessonRead(lessonId);
+            this._loadLessonUpdates(lessonId);
             // Close mobile sidebar
             this._closeMobileSidebar();
-            // Scroll to top
-            window.scrollTo(0, 0);
+            // Scroll to the section, or to the top
+            const target = section && document.getElementById(`section-${section}`);
+            if (target) {
+                target.scrollIntoView();
+            } else {
+                window.scrollTo(0, 0);
+            }
         } catch (e) {
+            if (e.missing) {
+                this.currentLesson = null;
+                Components.renderLockedLesson(lessonId, e.message, e.missing);
+                Components.showView('lesson');
+                Components.updateSidebarActive(lessonId);
+                this._closeMobileSidebar();
+                return;
+            }
             console.error('Failed to load lesson:', e);
         }
     },
 
     async startQuiz(lessonId) {
         const quiz = await Quiz.load(lessonId);
-        if (!quiz) return;
-        Components.renderQuiz(quiz);
+        if (quiz) {
+            Components.renderQuiz(quiz);
+        } else {
+            Components.renderQuizUnavailable(Quiz.error);
+        }
         Components.showView('quiz');
         window.scrollTo(0, 0);
     },
@@ -139,24 +219,371 @@ const App = {
         Components.renderQuiz(Quiz.currentQuiz);
     },
 
-    submitQuiz() {
-        const result = Quiz.submit();
+    toggleQuizOption(questionId, optionIndex) {
+        if (Quiz.submitted) return;
+        Quiz.toggleOption(questionId, optionIndex);
+        Components.renderQuiz(Quiz.currentQuiz);
+    },
+
+    // Text answers update state without re-rendering so the input keeps focus.
+    setQuizText(questionId, value) {
+        Quiz.setText(questionId, value);
+        Components.updateQuizSubmit();
+    },
+
+    moveQuizLine(questionId, index, delta) {
+        if (Quiz.submitted) return;
+        Quiz.moveLine(questionId, index, delta);
+        Components.renderQuiz(Quiz.currentQuiz);
+    },
+
+    async submitQuiz() {
+        const result = await Quiz.submit();
         if (!result) return;
 
         Components.renderQuizResult(result, this.currentLessonId);
+        this._refreshProgress(this.currentLessonId);
+        Components.updateReviewCount(await Review.refreshCount());
+        window.scrollTo(0, 0);
+    },
+
+    async startReview() {
+        if (window.location.hash !== '#review') {
+            window.location.hash = 'review';
+            return;
+        }
+        try {
+            await Review.load();
+        } catch (e) {
+            console.error('Failed to load review queue:', e);
+            return;
+        }
+        this.currentLessonId = null;
+        Components.updateSidebarActive(null);
+        Components.renderReview();
+        Components.showView('review');
+        Components.updateReviewCount(Review.due);
+        this._closeMobileSidebar();
+        window.scrollTo(0, 0);
+    },
+
+    selectReviewOption(questionId, optionIndex) {
+        Review.selectAnswer(questionId, optionIndex);
+        Components.renderReview();
+    },
+
+    toggleReviewOption(questionId, optionIndex) {
+        Review.toggleOption(questionId, optionIndex);
+        Components.renderReview();
+    },
+
+    setReviewText(questionId, value) {
+        Review.setText(questionId, value);
+        Components.updateReviewSubmit();
+    },
+
+    moveReviewLine(questionId, index, delta) {
+        Review.moveLine(questionId, index, delta);
+        Components.renderReview();
+    },
+
+    async answerReview() {
+        try {
+            await Review.answer();
+        } catch (e) {
+            console.error('Failed to grade review:', e);
+        }
+        Components.renderReview();
+        Components.updateReviewCount(Review.due);
+    },
+
+    revealReview() {
+        Review.revealed = true;
+        Components.renderReview();
+    },
+
+    async rateReview(quality) {
+        try {
+            await Review.rate(quality);
+        } catch (e) {
+            console.error('Failed to grade review:', e);
+        }
+        Components.renderReview();
+        Components.updateReviewCount(Review.due);
+    },
+
+    nextReview() {
+        Review.next();
+        Components.renderReview();
+    },
+
+    closeReview() {
+        window.location.hash = '';
+    },
+
+    // Submit the sidebar search box. The query goes into the hash so the
+    // back button returns to the results.
+    search(event) {
+        event.preventDefault();
+        const query = document.getElementById('searchInput').value.trim();
+        if (!query) return;
+        const hash = `search/${encodeURIComponent(query)}`;
+        if (window.location.hash === `#${hash}`) {
+            this.showSearch(query);
+        } else {
+            window.location.hash = hash;
+        }
+    },
+
+    async showSearch(query) {
+        document.getElementById('searchInput').value = query;
+        this.currentLessonId = null;
+        Components.updateSidebarActive(null);
+        Components.showView('search');
+        try {
+            const data = await API.search(query);
+            Components.renderSearchResults(query, data.results);
+        } catch (e) {
+            Components.renderSearchResults(query, [], e.message);
+        }
+        this._closeMobileSidebar();
+    },
+
+    // Show the glossary, or one of its terms with the lessons mentioning it.
+    async showGlossary(termId = null) {
+        this.currentLessonId = null;
+        Components.updateSidebarActive(null);
+        Components.showView('glossary');
+        try {
+            if (termId) {
+                Components.renderGlossaryTerm(await API.getGlossaryTerm(termId));
+            } else {
+                Components.renderGlossary((await API.getGlossary()).terms);
+            }
+        } catch (e) {
+            console.error('Failed to load glossary:', e);
+            Components.renderGlossary([], '用語が見つかりませんでした。');
+        }
+        window.scrollTo(0, 0);
+        this._closeMobileSidebar();
+    },
+
+    // Show the standard library documentation of a package or of one of
+    // its declarations, linked from lesson code.
+    async showDoc(ref) {
+        this.currentLessonId = null;
+        Components.updateSidebarActive(null);
+        Components.showView('doc');
+        try {
+            Components.renderDoc(ref, await API.getDoc(ref));
+        } catch (e) {
+            console.error('Failed to load documentation:', e);
+            Components.renderDoc(ref, null, 'ドキュメントが見つかりませんでした。');
+        }
+        window.scrollTo(0, 0);
+        this._closeMobileSidebar();
+    },
+
+    // Start or resume an exam. The session ID goes into the hash so a
+    // reload comes back to the same session.
+    async startExam(examId) {
+        try {
+            const session = await Exam.start(examId);
+            if (window.location.hash === `#exam/${session.id}`) {
+                this._handleRoute();
+            } else {
+                window.location.hash = `exam/${session.id}`;
+            }
+        } catch (e) {
+            this._showExamView();
+            Components.renderExamUnavailable(e.message);
+        }
+    },
+
+    async resumeExam(sessionId) {
+        try {
+            await Exam.resume(sessionId);
+        } catch (e) {
+            console.error('Failed to load exam:', e);
+            window.location.hash = '';
+            return;
+        }
+        this._showExamView();
+        Components.renderExam();
+        if (Exam.submitted) {
+            await this._examFinished();
+        } else {
+            this._examTimer = setInterval(() => this._tickExam(), 1000);
+        }
+    },
+
+    _showExamView() {
+        this.currentLessonId = null;
+        Components.updateSidebarActive(null);
+        Components.showView('exam');
+        this._closeMobileSidebar();
+        window.scrollTo(0, 0);
+    },
+
+    _tickExam() {
+        Components.updateExamTimer();
+        if (Exam.remainingMs() === 0) this.submitExam();
+    },
+
+    _stopExamTimer() {
+        clearInterval(this._examTimer);
+        this._examTimer = null;
+    },
+
+    selectExamOption(questionId, optionIndex) {
+        Exam.selectAnswer(questionId, optionIndex);
+        this._examAnswerChanged(true);
+    },
+
+    toggleExamOption(questionId, optionIndex) {
+        Exam.toggleOption(questionId, optionIndex);
+        this._examAnswerChanged(true);
+    },
+
+    setExamText(questionId, value) {
+        Exam.setText(questionId, value);
+        this._examAnswerChanged(false);
+    },
+
+    moveExamLine(questionId, index, delta) {
+        Exam.moveLine(questionId, index, delta);
+        this._examAnswerChanged(true);
+    },
+
+    _examAnswerChanged(rerender) {
+        if (Exam.submitted) return;
+        Exam.scheduleSave();
+        if (rerender) Components.renderExam();
+        else Components.updateExamAnswered();
+    },
+
+    async submitExam() {
+        this._stopExamTimer();
+        const result = await Exam.submit();
+        if (!result) return;
+        Components.renderExam();
+        await this._examFinished();
+        window.scrollTo(0, 0);
+    },
+
+    // Passing an exam can complete a chapter, so reload everything it affects.
+    async _examFinished() {
+        await Promise.all([Exam.loadStatuses(), Progress.refresh()]);
+        this._refreshProgress(null);
+        Components.updateReviewCount(await Review.refreshCount());
+    },
+
+    closeExam() {
+        window.location.hash = '';
+    },
+
+    // Show the lesson's changelog and, if it was rewritten since the learner
+    // completed it, what changed since.
+    async _loadLessonUpdates(lessonId) {
+        try {
+            const changelog = await API.getChangelog(lessonId);
+            let update = null;
+            if (Progress.getLessonProgress(lessonId)?.updated) {
+                const updated = await API.getUpdatedLessons(Progress.getUsername());
+                update = updated.lessons.find(u => u.lessonId === lessonId) || null;
+            }
+            if (this.currentLessonId !== lessonId) return;
+            Components.renderLessonUpdates(changelog, update);
+        } catch (e) {
+            console.error('Failed to load changelog:', e);
+        }
+    },
+
+    // Mark the lesson read once the learner scrolls to the bottom of it.
+    _watchLessonRead(lessonId) {
+        if (this._readObserver) this._readObserver.disconnect();
+        const end = document.querySelector('#lessonView .lesson-nav');
+        if (!end || !('IntersectionObserver' in window)) return;
+
+        this._readObserver = new IntersectionObserver(async (entries) => {
+            if (!entries.some(e => e.isIntersecting)) return;
+            this._readObserver.disconnect();
+            await Progress.markRead(lessonId);
+            this._refreshProgress(lessonId);
+        });
+        this._readObserver.observe(end);
+    },
+
+    async exampleRun(lessonId, index) {
+        await Progress.markExampleRun(lessonId, index);
+        this._refreshProgress(lessonId);
+    },
+
+    async submitExercise() {
+        if (!this.currentLesson || !Editor.editor) return;
+        const lessonId = this.currentLesson.id;
+        try {
+            const result = await Progress.submitExercise(lessonId, Editor.editor.getValue());
+            Components.renderExerciseResult(result);
+        } catch (e) {
+            Components.renderExerciseResult({ passed: false, reason: '提出に失敗しました' });
+        }
+        this._refreshProgress(lessonId);
+    },
+
+    // Re-render progress indicators after a component was recorded.
+    _refreshProgress(lessonId) {
         Components.updateProgress();
-        // Re-render sidebar to update checkmarks
         if (Components.chaptersData.length > 0) {
             Components.renderSidebar(Components.chaptersData);
             Components.updateSidebarActive(this.currentLessonId);
         }
-        window.scrollTo(0, 0);
+        if (this.currentLessonId === lessonId) {
+            Components.renderLessonChecklist(lessonId);
+        }
+        // Completing a lesson can unlock its chapter exam and the lessons
+        // that require it.
+        if (lessonId && Progress.isCompleted(lessonId)) {
+            Promise.all([Exam.loadStatuses(), API.getChapters(Progress.getUsername())]).then(([, chapters]) => {
+                Components.renderSidebar(chapters);
+                Components.updateSidebarActive(this.currentLessonId);
+            }).catch(e => console.error('Failed to refresh chapters:', e));
+        }
+    },
+
+    // Content changed on the server (development mode). Refresh the
+    // sidebar and progress, and the lesson if one is open; quizzes and
+    // exams in progress are left alone.
+    async reloadContent() {
+        if (!Progress.isLoggedIn()) return;
+        try {
+            const chapters = await API.getChapters(Progress.getUsername());
+            await Promise.all([Progress.refresh(), Exam.loadStatuses()]);
+            Components.renderSidebar(chapters);
+            Components.updateSidebarActive(this.currentLessonId);
+            Components.updateProgress();
+        } catch (e) {
+            console.error('Failed to reload chapters:', e);
+            return;
+        }
+        if (document.getElementById('searchView').style.display !== 'none') {
+            await this.showSearch(document.getElementById('searchInput').value);
+            return;
+        }
+        const lessonView = document.getElementById('lessonView');
+        if (this.currentLessonId && lessonView.style.display !== 'none') {
+            const scroll = window.scrollY;
+            await this.navigateTo(this.currentLessonId, false);
+            window.scrollTo(0, scroll);
+        }
     },
 
     showLesson() {
         if (this.currentLesson) {
             Components.renderLesson(this.currentLesson);
             Components.showView('lesson');
+            this._watchLessonRead(this.currentLesson.id);
+            this._loadLessonUpdates(this.currentLesson.id);
         }
     },
 
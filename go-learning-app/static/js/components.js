@@ -3,6 +3,26 @@ const Components = {
     chaptersData: [],
     totalLessons: 0,
 
+    // Handler names used by question inputs in the quiz, review and exam views.
+    quizActions: {
+        select: 'App.selectQuizOption',
+        toggle: 'App.toggleQuizOption',
+        text: 'App.setQuizText',
+        move: 'App.moveQuizLine',
+    },
+    reviewActions: {
+        select: 'App.selectReviewOption',
+        toggle: 'App.toggleReviewOption',
+        text: 'App.setReviewText',
+        move: 'App.moveReviewLine',
+    },
+    examActions: {
+        select: 'App.selectExamOption',
+        toggle: 'App.toggleExamOption',
+        text: 'App.setExamText',
+        move: 'App.moveExamLine',
+    },
+
     renderSidebar(chapters) {
         this.chaptersData = chapters;
         this.totalLessons = chapters.reduce((sum, ch) => sum + ch.lessons.length, 0);
@@ -12,10 +32,11 @@ const Components = {
         let html = '';
         for (const ch of chapters) {
             const isOpen = this._isChapterOpen(ch.id);
+            const chapterDone = Progress.isChapterCompleted(ch.id);
             html += `<div class="chapter-group" data-chapter="${ch.id}">
                 <div class="chapter-header" onclick="App.toggleChapter(${ch.id})">
-                    <span class="chapter-num">${ch.id}</span>
-                    <span class="chapter-title">${ch.title}</span>
+                    <span class="chapter-num ${chapterDone ? 'completed' : ''}">${ch.id}</span>
+                    <span class="chapter-title">${this._escapeHtml(ch.title)}</span>
                     <span class="chapter-toggle ${isOpen ? 'open' : ''}">\u25B6</span>
                 </div>
                 <div class="lesson-list ${isOpen ? 'open' : ''}">`;
@@ -23,20 +44,42 @@ const Components = {
             for (const lesson of ch.lessons) {
                 const isComplete = completed.has(lesson.id);
                 const isActive = App.currentLessonId === lesson.id;
-                html += `<div class="lesson-item ${isActive ? 'active' : ''}"
-                              onclick="App.navigateTo('${lesson.id}')">
-                    <span class="lesson-check ${isComplete ? 'completed' : ''}"></span>
-                    <span>${lesson.title}</span>
+                const steps = Progress.getSteps(lesson.id);
+                const isPartial = !isComplete && steps.done > 0;
+                const checkClass = isComplete ? 'completed' : (isPartial ? 'partial' : '');
+                const missing = lesson.missing || [];
+                const hint = missing.length > 0 ? `先に修了: ${missing.map(id => this._lessonLabel(id)).join('、')}` : '';
+                html += `<div class="lesson-item ${isActive ? 'active' : ''} ${lesson.locked ? 'locked' : ''}"
+                              onclick="App.navigateTo('${lesson.id}')" title="${this._escapeHtml(hint)}">
+                    <span class="lesson-check ${checkClass}" title="${steps.done}/${steps.total}"></span>
+                    <span>${lesson.locked ? '\u{1F512} ' : ''}${this._escapeHtml(lesson.title)}</span>
+                    ${Progress.getLessonProgress(lesson.id)?.updated ? '<span class="lesson-updated" title="修了後に更新されました">更新</span>' : ''}
                 </div>`;
             }
 
+            html += this._renderExamItem(Exam.chapterStatus(ch.id), '章末試験');
             html += `</div></div>`;
         }
 
+        const final = Exam.statuses.find(s => !s.chapterId);
+        if (final) {
+            html += `<div class="chapter-group exam-group">${this._renderExamItem(final, final.title)}</div>`;
+        }
+
         nav.innerHTML = html;
         this.updateProgress();
     },
 
+    _renderExamItem(status, label) {
+        if (!status) return '';
+        const score = status.bestScore != null ? ` ${status.bestScore}%` : '';
+        return `<div class="lesson-item exam-item ${status.unlocked ? '' : 'locked'}"
+                      onclick="App.startExam('${status.id}')" title="${this._escapeHtml(status.reason || '')}">
+                <span class="lesson-check ${status.passed ? 'completed' : ''}"></span>
+                <span>${status.unlocked ? '' : '\u{1F512} '}${this._escapeHtml(label)}${score}</span>
+            </div>`;
+    },
+
     _isChapterOpen(chapterId) {
         const key = `go-learning-ch-${chapterId}`;
         return sessionStorage.getItem(key) !== 'closed';
@@ -80,29 +123,102 @@ const Components = {
         });
     },
 
+    // "3-4 クロージャ" for a lesson ID, from the loaded chapters.
+    _lessonLabel(lessonId) {
+        for (const ch of this.chaptersData) {
+            const l = ch.lessons.find(l => l.id === lessonId);
+            if (l) return `${l.id} ${l.title}`;
+        }
+        return lessonId;
+    },
+
+    _lessonSummary(lessonId) {
+        for (const ch of this.chaptersData) {
+            const l = ch.lessons.find(l => l.id === lessonId);
+            if (l) return l;
+        }
+        return null;
+    },
+
+    _prerequisiteLinks(missing) {
+        return missing
+            .map(id => `<a href="#lesson/${id}">${this._escapeHtml(this._lessonLabel(id))}</a>`)
+            .join('、');
+    },
+
+    // Shown instead of a lesson that strict gating keeps locked.
+    // Advisory gating: recommend the prerequisites not yet completed.
+    _renderPrerequisiteNotice(lessonId) {
+        const missing = this._lessonSummary(lessonId)?.missing || [];
+        if (missing.length === 0) return '';
+        return `<div class="prerequisite-notice">先に学習することをおすすめします: ${this._prerequisiteLinks(missing)}</div>`;
+    },
+
+    // What changed in a lesson rewritten since the learner completed it,
+    // and the lesson's full changelog.
+    renderLessonUpdates(changelog, update) {
+        const el = document.getElementById('lessonUpdates');
+        if (!el) return;
+        const entries = list => `<ul class="changelog-list">${list.map(c => `
+            <li><span class="changelog-date">${c.date}</span> ${this._escapeHtml(c.note)}</li>`).join('')}
+        </ul>`;
+
+        let html = '';
+        if (update) {
+            const parts = [update.lessonChanged && '本文', update.quizChanged && 'クイズ'].filter(Boolean).join('と');
+            html += `<div class="update-notice">
+                <p>修了後にこのレッスンの${parts}が更新されました。</p>
+                ${update.changes.length > 0 ? entries(update.changes) : ''}
+            </div>`;
+        }
+        if (changelog.changelog.length > 0) {
+            html += `<details class="changelog">
+                <summary>更新履歴</summary>
+                ${entries(changelog.changelog)}
+            </details>`;
+        }
+        el.innerHTML = html;
+    },
+
+    renderLockedLesson(lessonId, message, missing) {
+        const view = document.getElementById('lessonView');
+        view.innerHTML = `
+            <h1 class="lesson-title">\u{1F512} ${this._escapeHtml(this._lessonLabel(lessonId))}</h1>
+            <div class="prerequisite-notice locked">
+                <p>${this._escapeHtml(message)}</p>
+                <p>${this._prerequisiteLinks(missing)}</p>
+            </div>`;
+    },
+
     renderLesson(lesson) {
         const view = document.getElementById('lessonView');
         const chapter = this.chaptersData.find(ch => ch.id === lesson.chapterId);
         const chapterTitle = chapter ? chapter.title : '';
 
-        // Parse content - convert line breaks to paragraphs
-        const contentHtml = lesson.content
-            .split('\n\n')
-            .map(p => `<p>${p.trim()}</p>`)
-            .join('');
+        // The server renders and sanitizes the lesson's Markdown
+        const contentHtml = lesson.contentHtml;
 
         let html = `
-            <div class="lesson-breadcrumb">第${lesson.chapterId}章: ${chapterTitle}</div>
-            <h1 class="lesson-title">${lesson.title}</h1>
-            <div class="lesson-content">${contentHtml}</div>`;
+            <div class="lesson-breadcrumb">第${lesson.chapterId}章: ${this._escapeHtml(chapterTitle)}</div>
+            <h1 class="lesson-title">${this._escapeHtml(lesson.title)}</h1>
+            <div class="lesson-checklist" id="lessonChecklist"></div>
+            ${this._renderPrerequisiteNotice(lesson.id)}
+            <div id="lessonUpdates"></div>
+            ${this._renderToc(lesson)}
+            <div class="lesson-content" id="section-content">${contentHtml}</div>`;
 
         // Code examples with "Try it" button
         for (let i = 0; i < lesson.codeExamples.length; i++) {
             const ex = lesson.codeExamples[i];
             html += `
-            <div class="code-example">
-                <div class="code-example-title">${ex.title}</div>
-                <pre class="language-go"><code class="language-go">${this._escapeHtml(ex.code)}</code></pre>
+            <div class="code-example" id="section-example-${i + 1}">
+                <div class="code-example-title">${this._escapeHtml(ex.title)}</div>
+                <pre class="language-go"><code class="language-go">${ex.codeHtml}</code></pre>
+                ${ex.output ? `
+                <div class="code-example-output">
+                    <div class="code-example-output-label">出力${ex.nondeterministic ? '例（実行するたびに変わります）' : ''}</div>
+                    <pre>${this._escapeHtml(ex.output)}</pre>
+                </div>` : ''}
                 <div class="code-example-actions">
                     <button class="try-btn" onclick="Components.loadCodeToEditor('${lesson.id}', ${i})">
                         ▶ 試してみる
@@ -114,10 +230,10 @@ const Components = {
         // Notes
         if (lesson.notes && lesson.notes.length > 0) {
             html += `
-            <div class="lesson-notes">
+            <div class="lesson-notes" id="section-notes">
                 <h3>ポイント</h3>
                 <ul>
-                    ${lesson.notes.map(n => `<li>${n}</li>`).join('')}
+                    ${lesson.notesHtml.map(n => `<li>${n}</li>`).join('')}
                 </ul>
             </div>`;
         }
@@ -132,17 +248,22 @@ func main() {
 }`;
 
         html += `
-        <div class="exercise-section">
+        <div class="exercise-section" id="section-exercise">
             <div class="exercise-header">
-                <span class="exercise-title">💻 ${lesson.exercise?.title || 'コードを書いてみよう'}</span>
+                <span class="exercise-title">💻 ${this._escapeHtml(lesson.exercise?.title || 'コードを書いてみよう')}</span>
             </div>
-            <p class="exercise-description">${lesson.exercise?.description || '上記のコードを参考に、自分でコードを書いて実行してみましょう。'}</p>
+            <p class="exercise-description">${lesson.exercise?.descriptionHtml || '上記のコードを参考に、自分でコードを書いて実行してみましょう。'}</p>
             <div id="editorMount"></div>
+            ${lesson.exercise ? `
+            <div class="exercise-actions">
+                <button class="btn btn-primary" onclick="App.submitExercise()">課題を提出</button>
+                <span class="exercise-result" id="exerciseResult"></span>
+            </div>` : ''}
         </div>`;
 
         // Quiz button
         html += `
-            <button class="quiz-start-btn" onclick="App.startQuiz('${lesson.id}')">
+            <button class="quiz-start-btn" id="section-quiz" onclick="App.startQuiz('${lesson.id}')">
                 クイズに挑戦する
             </button>`;
 
@@ -159,11 +280,25 @@ func main() {
 
         // Store lesson for later use
         this._currentLesson = lesson;
+        this.renderLessonChecklist(lesson.id);
+        // The server highlights the lesson's code; Prism would drop its
+        // links to the standard library documentation.
+    },
 
-        // Highlight code
-        if (window.Prism) {
-            Prism.highlightAllUnder(view);
-        }
+    // Table of contents of the lesson's headings, linking to each
+    _renderToc(lesson) {
+        if (!lesson.toc || lesson.toc.length === 0) return '';
+        const top = Math.min(...lesson.toc.map(h => h.level));
+        return `
+            <nav class="lesson-toc">
+                <div class="lesson-toc-title">目次</div>
+                <ul>
+                    ${lesson.toc.map(h => `
+                    <li class="lesson-toc-level-${h.level - top + 1}">
+                        <a href="#lesson/${lesson.id}/heading-${encodeURIComponent(h.id)}">${this._escapeHtml(h.title)}</a>
+                    </li>`).join('')}
+                </ul>
+            </nav>`;
     },
 
     // Load code example into editor
@@ -173,12 +308,43 @@ func main() {
             if (code && Editor.editor) {
                 Editor.currentStarterCode = code;
                 Editor.editor.setValue(code);
+                Editor.onSuccessfulRun = (ranCode) => {
+                    if (ranCode === code) App.exampleRun(lessonId, exampleIndex);
+                };
                 // Scroll to editor
                 document.getElementById('editorMount')?.scrollIntoView({ behavior: 'smooth' });
             }
         }
     },
 
+    // Show which completion components of the lesson are done.
+    renderLessonChecklist(lessonId) {
+        const el = document.getElementById('lessonChecklist');
+        const p = Progress.getLessonProgress(lessonId);
+        if (!el || !p) return;
+
+        const items = [];
+        if (p.rule.read) items.push(['読了', p.read]);
+        if (p.rule.quiz) {
+            const score = p.quizScore != null ? ` (${p.quizScore}%)` : '';
+            items.push([`クイズ合格${score}`, p.quizPassed]);
+        }
+        if (p.rule.exercise) items.push(['課題クリア', p.exercisePassed]);
+        if (p.rule.examples) items.push([`サンプル実行 ${p.examplesRun}/${p.examplesTotal}`, p.examplesRun >= p.examplesTotal]);
+
+        el.innerHTML = items.map(([label, done]) =>
+            `<span class="checklist-item ${done ? 'done' : ''}">${done ? '\u2714' : '\u25CB'} ${label}</span>`
+        ).join('');
+    },
+
+    renderExerciseResult(result) {
+        const el = document.getElementById('exerciseResult');
+        if (!el) return;
+        el.textContent = result.passed ? '\u2705 課題クリア！' : `\u274C ${result.reason || '不合格'}`;
+        el.classList.toggle('pass', !!result.passed);
+        el.classList.toggle('fail', !result.passed);
+    },
+
     _renderLessonNav(currentId) {
         const allLessons = [];
         for (const ch of this.chaptersData) {
@@ -195,7 +361,7 @@ func main() {
 
         if (prev) {
             html += `<button class="lesson-nav-btn" onclick="App.navigateTo('${prev.id}')">
-                \u2190 ${prev.title}
+                \u2190 ${this._escapeHtml(prev.title)}
             </button>`;
         } else {
             html += '<div></div>';
@@ -203,7 +369,7 @@ func main() {
 
         if (next) {
             html += `<button class="lesson-nav-btn" onclick="App.navigateTo('${next.id}')">
-                ${next.title} \u2192
+                ${this._escapeHtml(next.title)} \u2192
             </button>`;
         } else {
             html += '<div></div>';
@@ -221,38 +387,19 @@ func main() {
             <div class="quiz-header">
                 <h2>クイズ</h2>
                 <span class="quiz-progress-text">${questions.length}問</span>
-            </div>`;
+            </div>
+            ${this._renderPolicy(quiz)}`;
 
         for (let i = 0; i < questions.length; i++) {
             const q = questions[i];
-            const selected = Quiz.getSelectedAnswer(q.id);
 
             html += `
             <div class="quiz-question" data-question="${q.id}">
-                <div class="quiz-question-text">Q${i + 1}. ${q.text}</div>
-                <div class="quiz-options">`;
-
-            const labels = ['A', 'B', 'C', 'D'];
-            for (let j = 0; j < q.options.length; j++) {
-                let classes = 'quiz-option';
-                if (Quiz.submitted) {
-                    classes += ' disabled';
-                    if (j === q.answer) classes += ' correct';
-                    else if (j === selected && j !== q.answer) classes += ' wrong';
-                } else if (j === selected) {
-                    classes += ' selected';
-                }
-
-                html += `
-                    <div class="${classes}" onclick="App.selectQuizOption('${q.id}', ${j})">
-                        <span class="quiz-option-marker">${labels[j]}</span>
-                        <span>${q.options[j]}</span>
-                    </div>`;
-            }
-
-            html += `</div>
+                <div class="quiz-question-text">Q${i + 1}. ${this._escapeHtml(q.text)}${q.points > 1 ? ` <span class="quiz-points">(${q.points}点)</span>` : ''}</div>
+                ${q.code && q.type !== 'order' ? `<pre class="language-go quiz-code"><code class="language-go">${this._escapeHtml(q.code)}</code></pre>` : ''}
+                ${this._renderQuestionInput(q)}
                 <div class="quiz-explanation ${Quiz.submitted ? 'show' : ''}" id="explanation-${q.id}">
-                    ${Quiz.submitted ? (Quiz.isCorrect(q.id) ? '\u2705 正解! ' : '\u274C 不正解. ') + q.explanation : q.explanation}
+                    ${Quiz.submitted ? (Quiz.isCorrect(q.id) ? '\u2705 正解! ' : '\u274C 不正解. ') + this._escapeHtml(Quiz.getExplanation(q.id)) : ''}
                 </div>
             </div>`;
         }
@@ -271,11 +418,117 @@ func main() {
         }
 
         view.innerHTML = html;
+
+        if (window.Prism) {
+            Prism.highlightAllUnder(view);
+        }
+    },
+
+    // Render the answer input for a question according to its type. state
+    // holds the answers (Quiz or Review) and actions names the handlers.
+    // Explain how the quiz is scored.
+    _renderPolicy(quiz) {
+        const policy = quiz.policy;
+        if (!policy) return '';
+        const rules = [`合格ライン: ${policy.passPercent}%`];
+        if (quiz.questions.some(q => q.points > 1)) rules.push('問題ごとに配点が異なります');
+        if (policy.negativeMarking) rules.push('複数選択は正しい選択肢ごとに部分点、誤った選択肢ごとに減点');
+        if (policy.maxAttempts) rules.push(`受験回数: ${quiz.attemptsUsed + 1}/${policy.maxAttempts}回目`);
+        return `<div class="quiz-policy">${rules.map(r => `<span>${r}</span>`).join('')}</div>`;
+    },
+
+    renderQuizUnavailable(message) {
+        document.getElementById('quizView').innerHTML = `
+            <div class="quiz-result">
+                <div class="quiz-result-message">${this._escapeHtml(message || 'クイズを読み込めませんでした')}</div>
+                <div class="quiz-actions" style="justify-content:center;">
+                    <button class="btn btn-primary" onclick="App.showLesson()">レッスンに戻る</button>
+                </div>
+            </div>`;
+    },
+
+    _renderQuestionInput(q, state = Quiz, actions = this.quizActions) {
+        const selected = state.getSelectedAnswer(q.id);
+        const answer = state.submitted ? state.getCorrectAnswer(q.id) : null;
+
+        switch (q.type) {
+            case 'multi': {
+                const chosen = selected || [];
+                return this._renderOptions(q, j => chosen.includes(j),
+                    j => answer.includes(j), state.submitted, actions.toggle, '複数選択');
+            }
+            case 'output':
+            case 'fill':
+            case 'short': {
+                const value = this._escapeHtml(selected || '');
+                const placeholder = { output: '出力を入力', fill: '空欄に入るコード', short: '答えを入力' }[q.type];
+                const input = q.type === 'output'
+                    ? `<textarea class="quiz-text-input" rows="3" placeholder="${placeholder}"
+                          oninput="${actions.text}('${q.id}', this.value)" ${state.submitted ? 'disabled' : ''}>${value}</textarea>`
+                    : `<input type="text" class="quiz-text-input" placeholder="${placeholder}" value="${value}"
+                          oninput="${actions.text}('${q.id}', this.value)" ${state.submitted ? 'disabled' : ''}>`;
+                const key = state.submitted && !state.isCorrect(q.id)
+                    ? `<pre class="quiz-key">正解: ${this._escapeHtml(String(answer))}</pre>` : '';
+                return input + key;
+            }
+            case 'order': {
+                const lines = selected || [];
+                let html = `<pre class="quiz-code">${this._escapeHtml(q.code || '')}</pre><ol class="quiz-order">`;
+                lines.forEach((line, j) => {
+                    html += `<li class="quiz-order-line">
+                        <code>${this._escapeHtml(line)}</code>
+                        ${state.submitted ? '' : `<span class="quiz-order-actions">
+                            <button onclick="${actions.move}('${q.id}', ${j}, -1)" ${j === 0 ? 'disabled' : ''}>\u25B2</button>
+                            <button onclick="${actions.move}('${q.id}', ${j}, 1)" ${j === lines.length - 1 ? 'disabled' : ''}>\u25BC</button>
+                        </span>`}
+                    </li>`;
+                });
+                html += '</ol>';
+                if (state.submitted && !state.isCorrect(q.id)) {
+                    html += `<pre class="quiz-key">正解:\n${this._escapeHtml(answer.join('\n'))}</pre>`;
+                }
+                return html;
+            }
+            default:
+                return this._renderOptions(q, j => j === selected,
+                    j => j === answer, state.submitted, actions.select, '');
+        }
+    },
+
+    _renderOptions(q, isSelected, isAnswer, submitted, onSelect, hint) {
+        const labels = ['A', 'B', 'C', 'D', 'E', 'F'];
+        let html = `${hint ? `<div class="quiz-hint">${hint}</div>` : ''}<div class="quiz-options">`;
+        for (let j = 0; j < q.options.length; j++) {
+            let classes = 'quiz-option';
+            if (submitted) {
+                classes += ' disabled';
+                if (isAnswer(j)) classes += ' correct';
+                else if (isSelected(j)) classes += ' wrong';
+            } else if (isSelected(j)) {
+                classes += ' selected';
+            }
+
+            html += `
+                <div class="${classes}" onclick="${onSelect}('${q.id}', ${j})">
+                    <span class="quiz-option-marker">${labels[j]}</span>
+                    <span>${this._escapeHtml(q.options[j])}</span>
+                </div>`;
+        }
+        return html + '</div>';
+    },
+
+    // Enable the submit button once every question has an answer.
+    updateQuizSubmit() {
+        const btn = document.querySelector('#quizView .quiz-actions .btn-primary');
+        if (btn) btn.disabled = !Quiz.allAnswered();
     },
 
     renderQuizResult(result, lessonId) {
         const view = document.getElementById('quizView');
 
+        const policy = result.policy;
+        const canRetry = !policy.maxAttempts || Quiz.attempts.length < policy.maxAttempts;
+
         // Keep existing questions, add result at top
         const resultHtml = `
             <div class="quiz-result">
@@ -286,10 +539,11 @@ func main() {
                     ${result.passed ? 'おめでとうございます！合格です！' : '惜しい！もう一度挑戦してみましょう。'}
                 </div>
                 <div class="quiz-result-detail">
-                    ${result.total}問中${result.correct}問正解 (合格ライン: 70%)
+                    ${result.total}問中${result.correct}問正解 / ${Math.round(result.points * 10) / 10}点 (満点${result.maxPoints}点, 合格ライン: ${result.passPercent}%)
                 </div>
+                ${this._renderAttemptHistory(Quiz.attempts)}
                 <div class="quiz-actions" style="justify-content:center;">
-                    ${result.passed
+                    ${result.passed || !canRetry
                 ? `<button class="btn btn-primary" onclick="App.showLesson()">レッスンに戻る</button>`
                 : `<button class="btn btn-primary" onclick="App.startQuiz('${lessonId}')">もう一度挑戦</button>
                            <button class="btn btn-secondary" onclick="App.showLesson()">レッスンに戻る</button>`
@@ -302,15 +556,395 @@ func main() {
         view.insertAdjacentHTML('afterbegin', resultHtml);
     },
 
+    _renderAttemptHistory(attempts) {
+        if (!attempts || attempts.length === 0) return '';
+        const best = Math.max(...attempts.map(a => a.score));
+        const recent = attempts.slice(-5);
+        const trail = recent.map(a =>
+            `<span class="attempt-score ${a.passed ? 'pass' : 'fail'}">${a.score}%</span>`
+        ).join(' \u2192 ');
+        return `
+            <div class="quiz-history">
+                <div>受験回数: ${attempts.length}回 / 最高スコア: ${best}%</div>
+                <div class="quiz-history-trail">${attempts.length > recent.length ? '\u2026 ' : ''}${trail}</div>
+            </div>`;
+    },
+
+    // Escape text for HTML, including quotes so it is also safe in
+    // attribute values.
     _escapeHtml(str) {
-        const div = document.createElement('div');
-        div.textContent = str;
-        return div.innerHTML;
+        const entities = { '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;' };
+        return String(str ?? '').replace(/[&<>"']/g, c => entities[c]);
+    },
+
+    updateReviewCount(count) {
+        const el = document.getElementById('reviewCount');
+        if (!el) return;
+        el.textContent = count > 0 ? count : '';
+        el.style.display = count > 0 ? '' : 'none';
+    },
+
+    renderReview() {
+        const view = document.getElementById('reviewView');
+        const card = Review.current();
+
+        let html = `
+            <div class="quiz-header">
+                <h2>復習</h2>
+                <span class="quiz-progress-text">残り ${Review.remaining()}枚</span>
+            </div>`;
+
+        if (!card) {
+            html += `
+            <div class="quiz-result">
+                <div class="quiz-result-message">今日の復習はすべて完了しました！</div>
+                <div class="quiz-actions" style="justify-content:center;">
+                    <button class="btn btn-primary" onclick="App.closeReview()">戻る</button>
+                </div>
+            </div>`;
+            view.innerHTML = html;
+            return;
+        }
+
+        html += `<div class="quiz-question review-card">
+            <div class="lesson-breadcrumb">${this._escapeHtml(card.lessonTitle)}</div>`;
+
+        if (card.question) {
+            const q = card.question;
+            html += `
+                <div class="quiz-question-text">${this._escapeHtml(q.text)}</div>
+                ${q.code && q.type !== 'order' ? `<pre class="language-go quiz-code"><code class="language-go">${this._escapeHtml(q.code)}</code></pre>` : ''}
+                ${this._renderQuestionInput(q, Review, this.reviewActions)}
+                <div class="quiz-explanation ${Review.submitted ? 'show' : ''}">
+                    ${Review.submitted ? (Review.isCorrect(q.id) ? '\u2705 正解! ' : '\u274C 不正解. ') + this._escapeHtml(Review.getExplanation(q.id)) : ''}
+                </div>
+                <div class="quiz-actions">
+                    ${Review.submitted
+                        ? `<button class="btn btn-primary" onclick="App.nextReview()">次へ</button>`
+                        : `<button class="btn btn-primary" onclick="App.answerReview()" ${Review.answered() ? '' : 'disabled'}>回答する</button>`}
+                </div>`;
+        } else {
+            html += `<div class="quiz-question-text">${this._escapeHtml(card.front)}</div>`;
+            if (Review.revealed) {
+                html += `
+                <div class="review-back">${this._escapeHtml(card.back)}</div>
+                <div class="quiz-actions review-ratings">
+                    <button class="btn btn-secondary" onclick="App.rateReview(1)">もう一度</button>
+                    <button class="btn btn-secondary" onclick="App.rateReview(3)">難しい</button>
+                    <button class="btn btn-primary" onclick="App.rateReview(4)">普通</button>
+                    <button class="btn btn-secondary" onclick="App.rateReview(5)">簡単</button>
+                </div>`;
+            } else {
+                html += `
+                <div class="quiz-actions">
+                    <button class="btn btn-primary" onclick="App.revealReview()">答えを見る</button>
+                </div>`;
+            }
+        }
+
+        html += '</div>';
+        view.innerHTML = html;
+
+        if (window.Prism) {
+            Prism.highlightAllUnder(view);
+        }
+    },
+
+    // Enable the review answer button once the card has an answer.
+    updateReviewSubmit() {
+        const btn = document.querySelector('#reviewView .quiz-actions .btn-primary');
+        if (btn && !Review.submitted) btn.disabled = !Review.answered();
+    },
+
+    renderExam() {
+        const view = document.getElementById('examView');
+        const exam = Exam.exam;
+
+        let html = `
+            <div class="quiz-header">
+                <h2>${this._escapeHtml(exam.title)}</h2>
+                ${Exam.submitted ? '' : `<span class="exam-timer" id="examTimer"></span>`}
+            </div>`;
+
+        if (Exam.submitted) {
+            const result = Exam.result;
+            const score = result ? result.percent : Exam.session.score;
+            html += `
+            <div class="quiz-result">
+                <div class="quiz-result-score ${Exam.session.passed ? 'pass' : 'fail'}">${score}%</div>
+                <div class="quiz-result-message">
+                    ${Exam.session.passed ? '合格です！' : '不合格です。復習してから再挑戦しましょう。'}
+                </div>
+                ${result ? `<div class="quiz-result-detail">
+                    ${result.total}問中${result.correct}問正解 (合格ライン: ${result.passPercent}%)
+                </div>` : ''}
+                <div class="quiz-actions" style="justify-content:center;">
+                    <button class="btn btn-primary" onclick="App.closeExam()">戻る</button>
+                </div>
+            </div>`;
+        } else {
+            html += `<div class="exam-info">
+                制限時間: ${Math.round(exam.timeLimitSec / 60)}分 /
+                回答済み: <span id="examAnswered">${Exam.answeredCount()}</span>/${Exam.questions.length}問
+                (回答は自動保存されます)
+            </div>`;
+        }
+
+        // Questions are shown with results once graded on this page.
+        if (!Exam.submitted || Exam.result) {
+            Exam.questions.forEach((q, i) => {
+                html += `
+                <div class="quiz-question" data-question="${q.id}">
+                    <div class="quiz-question-text">Q${i + 1}. ${this._escapeHtml(q.text)}</div>
+                    ${q.code && q.type !== 'order' ? `<pre class="language-go quiz-code"><code class="language-go">${this._escapeHtml(q.code)}</code></pre>` : ''}
+                    ${this._renderQuestionInput(q, Exam, this.examActions)}
+                    <div class="quiz-explanation ${Exam.submitted ? 'show' : ''}">
+                        ${Exam.submitted ? (Exam.isCorrect(q.id) ? '\u2705 正解! ' : '\u274C 不正解. ') + this._escapeHtml(Exam.getExplanation(q.id)) : ''}
+                    </div>
+                </div>`;
+            });
+        }
+
+        if (!Exam.submitted) {
+            html += `
+            <div class="quiz-actions">
+                <button class="btn btn-primary" onclick="App.submitExam()">提出する</button>
+            </div>`;
+        }
+
+        view.innerHTML = html;
+        this.updateExamTimer();
+
+        if (window.Prism) {
+            Prism.highlightAllUnder(view);
+        }
+    },
+
+    renderExamUnavailable(message) {
+        document.getElementById('examView').innerHTML = `
+            <div class="quiz-result">
+                <div class="quiz-result-message">${message}</div>
+                <div class="quiz-actions" style="justify-content:center;">
+                    <button class="btn btn-primary" onclick="App.closeExam()">戻る</button>
+                </div>
+            </div>`;
+    },
+
+    updateExamTimer() {
+        const el = document.getElementById('examTimer');
+        if (!el) return;
+        const total = Math.ceil(Exam.remainingMs() / 1000);
+        const min = Math.floor(total / 60);
+        const sec = String(total % 60).padStart(2, '0');
+        el.textContent = `残り ${min}:${sec}`;
+        el.classList.toggle('urgent', total <= 60);
+    },
+
+    updateExamAnswered() {
+        const el = document.getElementById('examAnswered');
+        if (el) el.textContent = Exam.answeredCount();
+    },
+
+    // Show the error from the latest content reload, or hide the banner.
+    renderDevError(message) {
+        const el = document.getElementById('devError');
+        if (!el) return;
+        el.style.display = message ? '' : 'none';
+        el.innerHTML = message
+            ? `<div class="dev-error-title">コンテンツの読み込みに失敗しました（前回の内容を表示中）</div><pre>${this._escapeHtml(message)}</pre>`
+            : '';
+    },
+
+    // Labels of the lesson sections search results point to.
+    SECTION_LABELS: {
+        content: '本文',
+        exercise: '演習',
+        notes: 'ポイント',
+        quiz: 'クイズ',
+    },
+
+    _sectionLabel(section, title) {
+        if (section.startsWith('example-')) return `コード例: ${title}`;
+        return this.SECTION_LABELS[section] || section;
+    },
+
+    // Render search results. Snippets come from the server already escaped,
+    // with matches wrapped in <mark>.
+    renderSearchResults(query, results, error) {
+        const view = document.getElementById('searchView');
+        let html = `<h1 class="search-title">「${this._escapeHtml(query)}」の検索結果</h1>`;
+        if (error) {
+            html += `<p class="search-empty">${this._escapeHtml(error)}</p>`;
+        } else if (results.length === 0) {
+            html += '<p class="search-empty">一致する教材は見つかりませんでした。</p>';
+        } else {
+            html += '<ul class="search-results">';
+            for (const r of results) {
+                html += `
+                <li class="search-result">
+                    <a href="${r.url}">
+                        <span class="search-result-lesson">${r.lessonId} ${this._escapeHtml(r.lessonTitle)}</span>
+                        <span class="search-result-section">${this._escapeHtml(this._sectionLabel(r.section, r.title))}</span>
+                    </a>
+                    <div class="search-result-snippet">${r.snippet}</div>
+                </li>`;
+            }
+            html += '</ul>';
+        }
+        view.innerHTML = html;
+    },
+
+    // Glossary: every term with its reading and definition.
+    renderGlossary(terms, error) {
+        const view = document.getElementById('glossaryView');
+        let html = '<h1 class="search-title">用語集</h1>';
+        if (error || terms.length === 0) {
+            html += `<p class="search-empty">${this._escapeHtml(error || 'このコースには用語集がありません。')}</p>`;
+        } else {
+            html += '<dl class="glossary-list">';
+            for (const t of terms) {
+                html += `
+                <dt><a href="#glossary/${encodeURIComponent(t.id)}">${this._escapeHtml(t.term)}</a>
+                    ${t.reading ? `<span class="glossary-reading">${this._escapeHtml(t.reading)}</span>` : ''}</dt>
+                <dd>${t.definitionHtml}</dd>`;
+            }
+            html += '</dl>';
+        }
+        view.innerHTML = html;
+    },
+
+    // A glossary term: its definition, the lesson introducing it, related
+    // terms and every lesson that mentions it.
+    renderGlossaryTerm({ term, lessons, related }) {
+        const view = document.getElementById('glossaryView');
+        const lessonLink = id => `<a href="#lesson/${id}">${this._escapeHtml(this._lessonLabel(id))}</a>`;
+        let html = `
+            <div class="lesson-breadcrumb"><a href="#glossary">用語集</a></div>
+            <h1 class="search-title">${this._escapeHtml(term.term)}
+                ${term.reading ? `<span class="glossary-reading">${this._escapeHtml(term.reading)}</span>` : ''}</h1>
+            <div class="lesson-content">${term.definitionHtml}</div>`;
+        if (term.aliases && term.aliases.length > 0) {
+            html += `<p class="glossary-meta">別名: ${term.aliases.map(a => this._escapeHtml(a)).join('、')}</p>`;
+        }
+        if (term.lesson) {
+            html += `<p class="glossary-meta">初出: ${lessonLink(term.lesson)}</p>`;
+        }
+        if (related.length > 0) {
+            html += `<p class="glossary-meta">関連用語: ${related
+                .map(t => `<a href="#glossary/${encodeURIComponent(t.id)}">${this._escapeHtml(t.term)}</a>`)
+                .join('、')}</p>`;
+        }
+        html += '<h3 class="glossary-heading">この用語が出てくるレッスン</h3>';
+        html += lessons.length === 0
+            ? '<p class="search-empty">この用語が出てくるレッスンはありません。</p>'
+            : `<ul class="search-results">${lessons.map(l => `<li class="search-result">${lessonLink(l.id)}</li>`).join('')}</ul>`;
+        view.innerHTML = html;
+    },
+
+    // Standard library documentation: a package with its index, or a
+    // declaration with its members and examples.
+    renderDoc(ref, doc, error) {
+        const view = document.getElementById('docView');
+        const link = (path, name) => `#doc/${path}${name ? '.' + name : ''}`;
+        const docText = html => `<div class="lesson-content doc-text">${html}</div>`;
+        const entries = (path, list) => `<ul class="doc-index">${list.map(e => `
+            <li><span class="doc-kind">${e.kind}</span>
+                <a href="${this._escapeHtml(link(path, e.name))}"><code>${this._escapeHtml(e.name)}</code></a>
+                <span class="doc-synopsis">${this._escapeHtml(e.synopsis)}</span></li>`).join('')}
+        </ul>`;
+        const examples = list => list.map(ex => `
+            <div class="code-example">
+                <div class="code-example-title">例${ex.name ? ` (${this._escapeHtml(ex.name)})` : ''}</div>
+                ${ex.doc ? `<p class="doc-example-doc">${this._escapeHtml(ex.doc)}</p>` : ''}
+                <pre class="language-go"><code class="language-go">${this._escapeHtml(ex.code)}</code></pre>
+                ${ex.output ? `
+                <div class="code-example-output">
+                    <div class="code-example-output-label">出力</div>
+                    <pre>${this._escapeHtml(ex.output)}</pre>
+                </div>` : ''}
+            </div>`).join('');
+
+        let html;
+        if (error) {
+            html = `<h1 class="search-title">${this._escapeHtml(ref)}</h1>
+                <p class="search-empty">${this._escapeHtml(error)}</p>`;
+        } else if (doc.symbol) {
+            const { package: pkg, symbol } = doc;
+            html = `
+                <div class="lesson-breadcrumb"><a href="${this._escapeHtml(link(pkg.importPath))}">package ${this._escapeHtml(pkg.importPath)}</a></div>
+                <h1 class="search-title"><code>${this._escapeHtml(pkg.name)}.${this._escapeHtml(symbol.name)}</code></h1>
+                <pre class="language-go"><code class="language-go">${this._escapeHtml(symbol.decl)}</code></pre>
+                ${docText(symbol.docHtml)}
+                ${symbol.members ? entries(pkg.importPath, symbol.members) : ''}
+                ${examples(symbol.examples)}`;
+        } else {
+            html = `
+                <div class="lesson-breadcrumb">標準ライブラリ</div>
+                <h1 class="search-title">package ${this._escapeHtml(doc.name)}</h1>
+                <pre class="language-go"><code class="language-go">import "${this._escapeHtml(doc.importPath)}"</code></pre>
+                ${docText(doc.docHtml)}
+                ${examples(doc.examples)}
+                <h3 class="glossary-heading">索引</h3>
+                ${entries(doc.importPath, doc.index)}`;
+        }
+        view.innerHTML = html;
+        if (window.Prism) {
+            Prism.highlightAllUnder(view);
+        }
+    },
+
+    // Language names for the content language selector.
+    LANGUAGE_NAMES: { ja: '日本語', en: 'English' },
+
+    // Fill the content language selector. The empty value follows the
+    // browser's languages. Hidden when there is only Japanese.
+    // Course catalog: the header's course selector, and on the welcome
+    // screen the current course and cards to switch to the others.
+    renderCourses(courses, current) {
+        const select = document.getElementById('courseSelect');
+        select.style.display = courses.length > 1 ? '' : 'none';
+        select.innerHTML = courses
+            .map(c => `<option value="${this._escapeHtml(c.id)}"${c.id === current ? ' selected' : ''}>${this._escapeHtml(c.title)}</option>`)
+            .join('');
+
+        const course = courses.find(c => c.id === current);
+        if (course) {
+            document.getElementById('welcomeTitle').textContent = course.title;
+            document.getElementById('welcomeDescription').textContent = course.description;
+            document.getElementById('welcomeChapters').textContent = course.chapters;
+            document.getElementById('welcomeLessons').textContent = course.lessons;
+            document.getElementById('welcomeCompleted').textContent = course.completed || 0;
+        }
+
+        const list = document.getElementById('courseList');
+        const others = courses.filter(c => c.id !== current);
+        list.style.display = others.length ? '' : 'none';
+        list.innerHTML = `<h3>ほかのコース</h3>` + others.map(c => `
+            <button class="course-card" onclick="App.changeCourse('${this._escapeHtml(c.id)}')">
+                <span class="course-card-title">${this._escapeHtml(c.title)}</span>
+                <span class="course-card-description">${this._escapeHtml(c.description)}</span>
+                <span class="course-card-meta">${c.chapters}チャプター・${c.lessons}レッスン・${c.completed || 0}修了</span>
+            </button>`).join('');
+    },
+
+    renderLanguageSelect(locales, current) {
+        const el = document.getElementById('langSelect');
+        if (!el) return;
+        el.style.display = locales && locales.length > 1 ? '' : 'none';
+        const options = [['', '自動'], ...(locales || []).map(l => [l, this.LANGUAGE_NAMES[l] || l])];
+        el.innerHTML = options
+            .map(([value, label]) => `<option value="${value}"${value === current ? ' selected' : ''}>${this._escapeHtml(label)}</option>`)
+            .join('');
     },
 
     showView(viewName) {
         document.getElementById('welcomeScreen').style.display = viewName === 'welcome' ? '' : 'none';
         document.getElementById('lessonView').style.display = viewName === 'lesson' ? '' : 'none';
         document.getElementById('quizView').style.display = viewName === 'quiz' ? '' : 'none';
+        document.getElementById('reviewView').style.display = viewName === 'review' ? '' : 'none';
+        document.getElementById('examView').style.display = viewName === 'exam' ? '' : 'none';
+        document.getElementById('searchView').style.display = viewName === 'search' ? '' : 'none';
+        document.getElementById('glossaryView').style.display = viewName === 'glossary' ? '' : 'none';
+        document.getElementById('docView').style.display = viewName === 'doc' ? '' : 'none';
     }
 };
@@ -0,0 +1,20 @@
+// Development mode: reload content when the server reports that content
+// files changed, and show content errors. Outside development mode the
+// server answers the event stream with 204 and nothing happens.
+const Dev = {
+    version: null,
+
+    init() {
+        if (!('EventSource' in window)) return;
+        const source = new EventSource('/api/dev/events');
+        source.addEventListener('reload', (e) => this._onState(JSON.parse(e.data)));
+    },
+
+    async _onState(state) {
+        Components.renderDevError(state.error || '');
+        // The first state only tells which content is being served.
+        const changed = this.version !== null && state.version !== this.version;
+        this.version = state.version;
+        if (changed) await App.reloadContent();
+    }
+};
@@ -3,10 +3,13 @@ const Editor = {
     editor: null,
     currentStarterCode: '',
     isRunning: false,
+    // Called with the code after it runs without error.
+    onSuccessfulRun: null,
 
     // Initialize CodeMirror editor
     init(container, starterCode = '') {
         this.currentStarterCode = starterCode;
+        this.onSuccessfulRun = null;
         
         const editorContainer = document.createElement('div');
         editorContainer.className = 'editor-container';
@@ -80,7 +83,7 @@ const Editor = {
         this._setRunning(true);
         
         try {
-            const response = await fetch('/api/run', {
+            const response = await API._fetch('/api/run', {
                 method: 'POST',
                 headers: { 'Content-Type': 'application/json' },
                 body: JSON.stringify({ code })
@@ -92,6 +95,7 @@ const Editor = {
                 this._showOutput(result.output || result.error, true);
             } else {
                 this._showOutput(result.output || '(出力なし)', false);
+                if (this.onSuccessfulRun) this.onSuccessfulRun(code);
             }
         } catch (err) {
             this._showOutput('実行エラー: ' + err.message, true);
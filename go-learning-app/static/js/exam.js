@@ -0,0 +1,109 @@
+// Timed exam state; the deadline is enforced by the server
+const Exam = {
+    SAVE_DELAY_MS: 1000,
+    statuses: [],
+    exam: null,
+    session: null,
+    questions: [],
+    answers: {},
+    submitted: false,
+    result: null,
+    // Server clock minus client clock, so the countdown follows the server.
+    clockOffset: 0,
+    _saveTimer: null,
+
+    // Answer editing and result lookups are shared with quizzes.
+    selectAnswer: Quiz.selectAnswer,
+    toggleOption: Quiz.toggleOption,
+    setText: Quiz.setText,
+    moveLine: Quiz.moveLine,
+    getSelectedAnswer: Quiz.getSelectedAnswer,
+    hasAnswer: Quiz.hasAnswer,
+    _questionResult: Quiz._questionResult,
+    isCorrect: Quiz.isCorrect,
+    getCorrectAnswer: Quiz.getCorrectAnswer,
+    getExplanation: Quiz.getExplanation,
+
+    async loadStatuses() {
+        if (!Progress.getUsername()) return this.statuses;
+        try {
+            this.statuses = (await API.getExams(Progress.getUsername())).exams;
+        } catch (e) {
+            console.error('Failed to load exams:', e);
+        }
+        return this.statuses;
+    },
+
+    getStatus(examId) {
+        return this.statuses.find(s => s.id === examId) || null;
+    },
+
+    chapterStatus(chapterId) {
+        return this.statuses.find(s => s.chapterId === chapterId) || null;
+    },
+
+    // Start a new session or resume the open one. Throws with the server's
+    // reason when the exam cannot be taken now.
+    async start(examId) {
+        this._apply(await API.startExam(examId, Progress.getUsername()));
+        return this.session;
+    },
+
+    async resume(sessionId) {
+        this._apply(await API.getExamSession(sessionId, Progress.getUsername()));
+        return this.session;
+    },
+
+    _apply(data) {
+        clearTimeout(this._saveTimer);
+        this.exam = data.exam;
+        this.session = data.session;
+        this.questions = data.questions;
+        this.answers = { ...data.session.answers };
+        this.submitted = !!data.session.submittedAt;
+        this.result = data.result || null;
+        this.clockOffset = new Date(data.serverTime).getTime() - Date.now();
+        for (const q of this.questions) {
+            if (q.type === 'order' && !this.answers[q.id]) this.answers[q.id] = [...q.lines];
+        }
+    },
+
+    remainingMs() {
+        if (!this.session) return 0;
+        const now = Date.now() + this.clockOffset;
+        return Math.max(new Date(this.session.deadline).getTime() - now, 0);
+    },
+
+    answeredCount() {
+        return this.questions.filter(q => this.hasAnswer(this.answers[q.id])).length;
+    },
+
+    // Save answers shortly after the last edit so a reload loses nothing.
+    scheduleSave() {
+        if (this.submitted) return;
+        clearTimeout(this._saveTimer);
+        this._saveTimer = setTimeout(() => this.save(), this.SAVE_DELAY_MS);
+    },
+
+    async save() {
+        clearTimeout(this._saveTimer);
+        if (!this.session || this.submitted || this.remainingMs() === 0) return;
+        try {
+            await API.saveExamAnswers(this.session.id, Progress.getUsername(), this.answers);
+        } catch (e) {
+            console.error('Failed to save exam answers:', e);
+        }
+    },
+
+    async submit() {
+        if (!this.session || this.submitted) return null;
+        clearTimeout(this._saveTimer);
+        try {
+            this._apply(await API.submitExam(this.session.id, Progress.getUsername(), this.answers));
+            return this.result;
+        } catch (e) {
+            console.error('Failed to submit exam:', e);
+            return null;
+        }
+    },
+};
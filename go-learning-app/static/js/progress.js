@@ -3,6 +3,9 @@ const Progress = {
     USERNAME_KEY: 'go-learning-username',
     username: null,
     _completed: new Set(),
+    _lessons: {},
+    _chapters: {},
+    certified: false,
 
     getUsername() {
         if (this.username) return this.username;
@@ -24,23 +27,93 @@ const Progress = {
         return !!this.getUsername();
     },
 
-    loadFromArray(lessons) {
-        this._completed = new Set(lessons || []);
+    // Load the server's progress response: completed IDs, the
+    // per-component breakdown for every lesson and chapter completion.
+    load(result) {
+        this._completed = new Set(result.progress || []);
+        this._lessons = {};
+        for (const p of result.lessons || []) {
+            this._lessons[p.lessonId] = p;
+        }
+        this._chapters = {};
+        for (const c of result.chapters || []) {
+            this._chapters[c.chapterId] = c;
+        }
+        this.certified = !!result.certified;
+    },
+
+    // Re-fetch everything; used after an exam changes chapter completion.
+    async refresh() {
+        if (!this.getUsername()) return;
+        try {
+            this.load(await API.getProgress(this.getUsername()));
+        } catch (e) {
+            console.error('Failed to load progress:', e);
+        }
+    },
+
+    isChapterCompleted(chapterId) {
+        const c = this._chapters[chapterId];
+        return !!c && c.completed;
     },
 
     isCompleted(lessonId) {
         return this._completed.has(lessonId);
     },
 
-    async markCompleted(lessonId) {
-        this._completed.add(lessonId);
-        const username = this.getUsername();
-        if (username) {
-            try {
-                await API.markCompleted(username, lessonId);
-            } catch (e) {
-                console.error('Failed to save progress:', e);
-            }
+    getLessonProgress(lessonId) {
+        return this._lessons[lessonId] || null;
+    },
+
+    // Number of required components done and required in total.
+    getSteps(lessonId) {
+        const p = this._lessons[lessonId];
+        if (!p) return { done: 0, total: 0 };
+        const steps = [
+            [p.rule.read, p.read],
+            [p.rule.quiz, p.quizPassed],
+            [p.rule.exercise, p.exercisePassed],
+            [p.rule.examples, p.examplesRun >= p.examplesTotal],
+        ].filter(([required]) => required);
+        return { done: steps.filter(([, done]) => done).length, total: steps.length };
+    },
+
+    // Apply an updated per-lesson breakdown returned by the server.
+    update(lesson) {
+        if (!lesson) return;
+        this._lessons[lesson.lessonId] = lesson;
+        if (lesson.completed) {
+            this._completed.add(lesson.lessonId);
+        } else {
+            this._completed.delete(lesson.lessonId);
+        }
+    },
+
+    async markRead(lessonId) {
+        const p = this._lessons[lessonId];
+        // Reading a lesson rewritten since it was completed again records
+        // that the learner has seen the new version.
+        if (p && p.read && !p.updated) return;
+        await this._save(() => API.markRead(this.getUsername(), lessonId));
+    },
+
+    async markExampleRun(lessonId, index) {
+        await this._save(() => API.markExampleRun(this.getUsername(), lessonId, index));
+    },
+
+    async submitExercise(lessonId, code) {
+        const result = await API.submitExercise(this.getUsername(), lessonId, code);
+        this.update(result.lesson);
+        return result;
+    },
+
+    async _save(call) {
+        if (!this.getUsername()) return;
+        try {
+            const result = await call();
+            this.update(result.lesson);
+        } catch (e) {
+            console.error('Failed to save progress:', e);
         }
     },
 
@@ -54,6 +127,9 @@ const Progress = {
 
     async reset() {
         this._completed.clear();
+        this._lessons = {};
+        this._chapters = {};
+        this.certified = false;
         const username = this.getUsername();
         if (username) {
             try {
@@ -66,6 +142,9 @@ const Progress = {
 
     async logout() {
         this._completed.clear();
+        this._lessons = {};
+        this._chapters = {};
+        this.certified = false;
         this.clearUsername();
     },
 };
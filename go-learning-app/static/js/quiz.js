@@ -1,17 +1,28 @@
-// Quiz logic and scoring
+// Quiz state; grading happens on the server
 const Quiz = {
     currentQuiz: null,
     answers: {},
     submitted: false,
+    result: null,
+    attempts: [],
+    error: '',
 
     async load(lessonId) {
         this.answers = {};
         this.submitted = false;
+        this.result = null;
+        this.attempts = [];
+        this.error = '';
         try {
-            this.currentQuiz = await API.getQuiz(lessonId);
+            this.currentQuiz = await API.getQuiz(lessonId, Progress.getUsername());
+            // Ordering questions start in the order the server sent.
+            for (const q of this.currentQuiz.questions) {
+                if (q.type === 'order') this.answers[q.id] = [...q.lines];
+            }
             return this.currentQuiz;
-        } catch {
+        } catch (e) {
             this.currentQuiz = null;
+            this.error = e.message;
             return null;
         }
     },
@@ -21,48 +32,84 @@ const Quiz = {
         this.answers[questionId] = optionIndex;
     },
 
+    toggleOption(questionId, optionIndex) {
+        if (this.submitted) return;
+        const chosen = this.answers[questionId] || [];
+        this.answers[questionId] = chosen.includes(optionIndex)
+            ? chosen.filter(j => j !== optionIndex)
+            : [...chosen, optionIndex];
+    },
+
+    setText(questionId, value) {
+        if (this.submitted) return;
+        this.answers[questionId] = value;
+    },
+
+    moveLine(questionId, index, delta) {
+        if (this.submitted) return;
+        const lines = this.answers[questionId];
+        const target = index + delta;
+        if (!lines || target < 0 || target >= lines.length) return;
+        [lines[index], lines[target]] = [lines[target], lines[index]];
+    },
+
     getSelectedAnswer(questionId) {
         return this.answers[questionId] !== undefined ? this.answers[questionId] : -1;
     },
 
     allAnswered() {
         if (!this.currentQuiz) return false;
-        return this.currentQuiz.questions.every(q => this.answers[q.id] !== undefined);
+        return this.currentQuiz.questions.every(q => this.hasAnswer(this.answers[q.id]));
     },
 
-    submit() {
-        if (!this.currentQuiz || !this.allAnswered()) return null;
-        this.submitted = true;
+    hasAnswer(a) {
+        if (a === undefined) return false;
+        if (typeof a === 'string') return a.trim() !== '';
+        if (Array.isArray(a)) return a.length > 0;
+        return true;
+    },
 
-        const questions = this.currentQuiz.questions;
-        let correct = 0;
+    async submit() {
+        if (!this.currentQuiz || !this.allAnswered()) return null;
 
-        for (const q of questions) {
-            if (this.answers[q.id] === q.answer) {
-                correct++;
+        try {
+            const username = Progress.getUsername();
+            const lessonId = this.currentQuiz.lessonId;
+            const data = await API.submitQuiz(
+                lessonId, username, this.currentQuiz.sessionId, this.answers,
+            );
+            this.result = data.result;
+            this.submitted = true;
+            Progress.update(data.lesson);
+            try {
+                this.attempts = (await API.getAttempts(username, lessonId)).attempts;
+            } catch (e) {
+                console.error('Failed to load attempt history:', e);
             }
+            return this.result;
+        } catch (e) {
+            console.error('Failed to submit quiz:', e);
+            return null;
         }
+    },
 
-        const total = questions.length;
-        const percent = Math.round((correct / total) * 100);
-        const passed = percent >= 70;
-
-        if (passed) {
-            Progress.markCompleted(this.currentQuiz.lessonId);
-        }
-
-        return { correct, total, percent, passed };
+    _questionResult(questionId) {
+        if (!this.result) return null;
+        return this.result.questions.find(q => q.questionId === questionId) || null;
     },
 
     isCorrect(questionId) {
-        if (!this.currentQuiz) return false;
-        const q = this.currentQuiz.questions.find(q => q.id === questionId);
-        return q && this.answers[questionId] === q.answer;
+        const q = this._questionResult(questionId);
+        return !!q && q.correct;
     },
 
     getCorrectAnswer(questionId) {
-        if (!this.currentQuiz) return -1;
-        const q = this.currentQuiz.questions.find(q => q.id === questionId);
+        const q = this._questionResult(questionId);
         return q ? q.answer : -1;
+    },
+
+    getExplanation(questionId) {
+        const q = this._questionResult(questionId);
+        return q ? q.explanation : '';
     }
 };
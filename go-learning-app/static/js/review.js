@@ -0,0 +1,95 @@
+// Spaced-repetition review session
+const Review = {
+    cards: [],
+    index: 0,
+    due: 0,
+    answers: {},
+    submitted: false,
+    revealed: false,
+    result: null,
+
+    // Answer editing is shared with quizzes.
+    selectAnswer: Quiz.selectAnswer,
+    toggleOption: Quiz.toggleOption,
+    setText: Quiz.setText,
+    moveLine: Quiz.moveLine,
+    getSelectedAnswer: Quiz.getSelectedAnswer,
+
+    async load() {
+        const data = await API.getDueReviews(Progress.getUsername());
+        this.cards = data.cards;
+        this.due = data.due;
+        this.index = 0;
+        this._resetCard();
+        return this.cards;
+    },
+
+    async refreshCount() {
+        if (!Progress.getUsername()) return 0;
+        try {
+            const data = await API.getDueReviews(Progress.getUsername(), 1);
+            this.due = data.due;
+        } catch (e) {
+            console.error('Failed to load review count:', e);
+        }
+        return this.due;
+    },
+
+    current() {
+        return this.cards[this.index] || null;
+    },
+
+    remaining() {
+        return Math.max(this.cards.length - this.index, 0);
+    },
+
+    answered() {
+        const card = this.current();
+        return !!card && !!card.question && Quiz.hasAnswer(this.answers[card.question.id]);
+    },
+
+    async answer() {
+        const card = this.current();
+        if (!card || !card.question || this.submitted) return;
+        const qid = card.question.id;
+        this.result = await API.gradeReview(Progress.getUsername(), card.id, { answer: this.answers[qid] });
+        this.submitted = true;
+        this.due = Math.max(this.due - 1, 0);
+    },
+
+    async rate(quality) {
+        const card = this.current();
+        if (!card) return;
+        await API.gradeReview(Progress.getUsername(), card.id, { quality });
+        this.due = Math.max(this.due - 1, 0);
+        this.next();
+    },
+
+    next() {
+        this.index++;
+        this._resetCard();
+    },
+
+    isCorrect() {
+        return !!this.result && this.result.correct;
+    },
+
+    getCorrectAnswer() {
+        return this.result ? this.result.answer : -1;
+    },
+
+    getExplanation() {
+        return this.result ? this.result.explanation : '';
+    },
+
+    _resetCard() {
+        this.submitted = false;
+        this.revealed = false;
+        this.result = null;
+        this.answers = {};
+        const card = this.current();
+        if (card && card.question && card.question.type === 'order') {
+            this.answers[card.question.id] = [...card.question.lines];
+        }
+    },
+};
@@ -0,0 +1,351 @@
+// Package stddoc serves the documentation of the standard library that
+// comes with the installed Go toolchain, the same one programs are run
+// with, so learners can look up a function without leaving the app or
+// going online. Packages are parsed with go/doc on first use and kept.
+package stddoc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/doc/comment"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned for paths that are not documented packages of
+// the standard library and for symbols a package does not declare.
+var ErrNotFound = errors.New("not found")
+
+// Package is the documentation of a package.
+type Package struct {
+	ImportPath string `json:"importPath"`
+	Name       string `json:"name"`
+	Synopsis   string `json:"synopsis"`
+	Doc        string `json:"doc"`
+	DocHTML    string `json:"docHtml"`
+	// Index lists the exported declarations in the order of the package's
+	// documentation: constants, variables, functions, then each type
+	// followed by its constants, variables, constructors and methods.
+	Index    []Entry   `json:"index"`
+	Examples []Example `json:"examples"`
+
+	symbols map[string]*Symbol
+}
+
+// Entry is a declaration in a package's index.
+type Entry struct {
+	// Name looks the declaration up with Package.Symbol; methods are
+	// named Type.Method.
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Synopsis string `json:"synopsis"`
+}
+
+// Kinds of declarations.
+const (
+	KindConst  = "const"
+	KindVar    = "var"
+	KindFunc   = "func"
+	KindType   = "type"
+	KindMethod = "method"
+)
+
+// Symbol is the documentation of a declaration.
+type Symbol struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// Names are all the names a grouped const or var declaration
+	// declares; a lookup of any of them finds the group.
+	Names []string `json:"names,omitempty"`
+	// Decl is the declaration's source, without function bodies and
+	// unexported fields.
+	Decl     string    `json:"decl"`
+	Doc      string    `json:"doc"`
+	DocHTML  string    `json:"docHtml"`
+	Examples []Example `json:"examples"`
+	// Members are a type's constants, variables, constructors and
+	// methods.
+	Members []Entry `json:"members,omitempty"`
+}
+
+// Example is an example from a package's tests.
+type Example struct {
+	// Name is the example's suffix, such as "multiple" in
+	// ExampleSplit_multiple; it is empty for the main example.
+	Name string `json:"name"`
+	Doc  string `json:"doc"`
+	// Code is a complete program where the example can be turned into
+	// one, and the body of the example function otherwise.
+	Code   string `json:"code"`
+	Output string `json:"output"`
+}
+
+// goroot returns the GOROOT of the go command programs are run with,
+// falling back to the one this binary was built with. It is found on
+// first use, so a missing toolchain only fails documentation requests.
+var goroot = sync.OnceValue(func() string {
+	out, err := exec.Command("go", "env", "GOROOT").Output()
+	if root := strings.TrimSpace(string(out)); err == nil && root != "" {
+		return root
+	}
+	return runtime.GOROOT()
+})
+
+// IsStd reports whether an import path is a package of the installed
+// standard library that programs can import, so not a command or an
+// internal or vendored package.
+func IsStd(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	if path == "" || strings.Contains(first, ".") || first == "cmd" {
+		return false
+	}
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "" || elem == "." || elem == ".." || elem == "internal" || elem == "vendor" || elem == "testdata" {
+			return false
+		}
+	}
+	info, err := os.Stat(filepath.Join(goroot(), "src", filepath.FromSlash(path)))
+	return err == nil && info.IsDir()
+}
+
+// Docs looks up documentation in the toolchain's GOROOT.
+type Docs struct {
+	mu   sync.Mutex
+	pkgs map[string]*Package
+}
+
+// New returns Docs for the Go toolchain on the PATH.
+func New() *Docs {
+	return &Docs{pkgs: make(map[string]*Package)}
+}
+
+// Package returns the documentation of a standard library package, or
+// ErrNotFound if IsStd is false for the path.
+func (d *Docs) Package(path string) (*Package, error) {
+	if !IsStd(path) {
+		return nil, ErrNotFound
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if p, ok := d.pkgs[path]; ok {
+		return p, nil
+	}
+	p, err := d.load(path)
+	if err != nil {
+		return nil, err
+	}
+	d.pkgs[path] = p
+	return p, nil
+}
+
+// Symbol returns the documentation of a declaration of a standard
+// library package, named as in Entry.
+func (d *Docs) Symbol(path, name string) (*Package, *Symbol, error) {
+	p, err := d.Package(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, ok := p.symbols[name]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	return p, s, nil
+}
+
+// load parses a package and its tests, for the examples, with the build
+// constraints of the machine the app runs on.
+func (d *Docs) load(path string) (*Package, error) {
+	ctx := build.Default
+	ctx.GOROOT = goroot()
+	bp, err := ctx.Import(path, "", 0)
+	var noGo *build.NoGoError
+	if (err != nil && !errors.As(err, &noGo)) || !bp.Goroot || bp.Name == "main" {
+		return nil, ErrNotFound
+	}
+
+	sources := [][]string{bp.GoFiles, bp.CgoFiles, bp.TestGoFiles, bp.XTestGoFiles}
+	var mode doc.Mode
+	if path == "builtin" {
+		// The predeclared identifiers are documented in a file that is
+		// never built, as lowercase declarations.
+		sources = append(sources, bp.IgnoredGoFiles)
+		mode = doc.AllDecls
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, names := range sources {
+		for _, name := range names {
+			f, err := parser.ParseFile(fset, filepath.Join(bp.Dir, name), nil, parser.ParseComments)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s: %w", name, err)
+			}
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return nil, ErrNotFound
+	}
+	dp, err := doc.NewFromFiles(fset, files, path, mode)
+	if err != nil {
+		return nil, fmt.Errorf("read documentation of %s: %w", path, err)
+	}
+	return newPackage(fset, dp), nil
+}
+
+// newPackage converts go/doc's documentation, printing declarations and
+// rendering doc comments.
+func newPackage(fset *token.FileSet, dp *doc.Package) *Package {
+	pr := dp.Printer()
+	pr.DocLinkURL = func(link *comment.DocLink) string {
+		path := link.ImportPath
+		if path == "" {
+			path = dp.ImportPath
+		}
+		name := link.Name
+		if link.Recv != "" {
+			name = link.Recv + "." + name
+		}
+		return Link(path, name)
+	}
+	html := func(text string) string {
+		return string(pr.HTML(dp.Parser().Parse(text)))
+	}
+
+	p := &Package{
+		ImportPath: dp.ImportPath,
+		Name:       dp.Name,
+		Synopsis:   dp.Synopsis(dp.Doc),
+		Doc:        dp.Doc,
+		DocHTML:    html(dp.Doc),
+		Examples:   examples(fset, dp.Examples),
+		Index:      []Entry{},
+		symbols:    make(map[string]*Symbol),
+	}
+	add := func(s *Symbol, names ...string) Entry {
+		s.DocHTML = html(s.Doc)
+		if s.Examples == nil {
+			s.Examples = []Example{}
+		}
+		for _, name := range names {
+			p.symbols[name] = s
+		}
+		e := Entry{Name: s.Name, Kind: s.Kind, Synopsis: dp.Synopsis(s.Doc)}
+		p.Index = append(p.Index, e)
+		return e
+	}
+	values := func(kind string, vs []*doc.Value) []Entry {
+		var entries []Entry
+		for _, v := range vs {
+			s := &Symbol{Name: v.Names[0], Kind: kind, Decl: source(fset, v.Decl), Doc: v.Doc}
+			if len(v.Names) > 1 {
+				s.Names = v.Names
+			}
+			entries = append(entries, add(s, v.Names...))
+		}
+		return entries
+	}
+	funcs := func(fs []*doc.Func) []Entry {
+		var entries []Entry
+		for _, f := range fs {
+			s := &Symbol{Name: f.Name, Kind: KindFunc, Decl: source(fset, f.Decl), Doc: f.Doc, Examples: examples(fset, f.Examples)}
+			if f.Recv != "" {
+				s.Name = strings.TrimLeft(f.Recv, "*") + "." + f.Name
+				s.Kind = KindMethod
+			}
+			entries = append(entries, add(s, s.Name))
+		}
+		return entries
+	}
+
+	values(KindConst, dp.Consts)
+	values(KindVar, dp.Vars)
+	funcs(dp.Funcs)
+	for _, t := range dp.Types {
+		s := &Symbol{Name: t.Name, Kind: KindType, Decl: source(fset, t.Decl), Doc: t.Doc, Examples: examples(fset, t.Examples)}
+		add(s, t.Name)
+		s.Members = append(s.Members, values(KindConst, t.Consts)...)
+		s.Members = append(s.Members, values(KindVar, t.Vars)...)
+		s.Members = append(s.Members, funcs(t.Funcs)...)
+		s.Members = append(s.Members, funcs(t.Methods)...)
+	}
+	return p
+}
+
+// examples converts go/doc's examples, preferring the complete program.
+func examples(fset *token.FileSet, exs []*doc.Example) []Example {
+	out := []Example{}
+	for _, ex := range exs {
+		var code string
+		if ex.Play != nil {
+			code = source(fset, ex.Play)
+		} else {
+			code = source(fset, &printer.CommentedNode{Node: ex.Code, Comments: ex.Comments})
+			code = unindent(code)
+		}
+		out = append(out, Example{Name: ex.Suffix, Doc: ex.Doc, Code: code, Output: ex.Output})
+	}
+	return out
+}
+
+// source prints a node as gofmt would.
+func source(fset *token.FileSet, node any) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// unindent turns a printed block into its statements.
+func unindent(block string) string {
+	block = strings.TrimSpace(block)
+	block = strings.TrimPrefix(block, "{")
+	block = strings.TrimSuffix(block, "}")
+	lines := strings.Split(strings.Trim(block, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Link returns the app route showing a package's documentation, or one
+// of its declarations if name is not empty. It does not check that they
+// exist; see LinkStd.
+func Link(path, name string) string {
+	if name == "" {
+		return "#doc/" + path
+	}
+	return "#doc/" + path + "." + name
+}
+
+// LinkStd is Link for packages of the standard library, and returns ""
+// for other import paths, so code links only what can be looked up.
+func LinkStd(path, name string) string {
+	if !IsStd(path) {
+		return ""
+	}
+	return Link(path, name)
+}
+
+// SplitRef splits a reference such as net/http.Client.Do into the
+// package path and the declaration's name, which is empty for a package.
+func SplitRef(ref string) (path, name string) {
+	i := strings.LastIndex(ref, "/") + 1
+	if dot := strings.Index(ref[i:], "."); dot >= 0 {
+		return ref[:i+dot], ref[i+dot+1:]
+	}
+	return ref, ""
+}
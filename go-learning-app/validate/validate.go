@@ -0,0 +1,411 @@
+// Package validate checks course content before it ships. It checks that
+// the content loads, that chapters, lessons and quizzes agree with each
+// other, that starter code and examples compile, and that examples still
+// print their recorded golden output. The golden output is served with
+// the example, so learners see it without running anything; recording it
+// with Options.Update is the step that generates it.
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-learning-app/data"
+	"go-learning-app/models"
+	"go-learning-app/runner"
+)
+
+// Problem is one thing wrong with the content.
+type Problem struct {
+	// File is the content file at fault, if known, and Line the line in it.
+	File string
+	Line int
+	// Subject names what is wrong when there is no file, e.g. "lesson 4-2".
+	Subject string
+	Msg     string
+	// Detail is extra context such as an output diff, shown indented.
+	Detail string
+}
+
+func (p Problem) String() string {
+	var b strings.Builder
+	switch {
+	case p.File != "" && p.Line > 0:
+		fmt.Fprintf(&b, "%s:%d: ", p.File, p.Line)
+	case p.File != "":
+		fmt.Fprintf(&b, "%s: ", p.File)
+	}
+	if p.Subject != "" {
+		fmt.Fprintf(&b, "%s: ", p.Subject)
+	}
+	b.WriteString(p.Msg)
+	if p.Detail != "" {
+		for _, line := range strings.Split(strings.TrimRight(p.Detail, "\n"), "\n") {
+			b.WriteString("\n    " + line)
+		}
+	}
+	return b.String()
+}
+
+// Options configures Run.
+type Options struct {
+	// Dir is the content directory, with one directory per course.
+	Dir string
+	// Update records the output of every run example as its golden output
+	// instead of comparing against it.
+	Update bool
+}
+
+// Report is the outcome of Run.
+type Report struct {
+	Problems []Problem
+	// Counts of what was checked.
+	Lessons, Examples, Starters, OutputQuestions int
+	// Updated lists the golden files written in update mode.
+	Updated []string
+	// Coverage is the translation coverage of each translated locale,
+	// summed over the courses.
+	Coverage []data.Coverage
+}
+
+// Run checks the content in opts.Dir. Problems with the content are
+// returned in the report; the error is for failures of the check itself.
+func Run(ctx context.Context, opts Options) (Report, error) {
+	var rep Report
+	catalog, err := data.LoadCatalog(os.DirFS(opts.Dir))
+	if err != nil {
+		// Nothing else can be checked until the content loads.
+		for _, err := range unjoin(err) {
+			var ce *data.ContentError
+			if !errors.As(err, &ce) {
+				return rep, err
+			}
+			rep.Problems = append(rep.Problems, Problem{File: ce.Path, Line: ce.Line, Msg: ce.Msg})
+		}
+		return rep, nil
+	}
+
+	c := newCompiler()
+	var runs []runJob
+	for _, store := range catalog.Courses() {
+		runs = append(runs, rep.checkCourse(c, store)...)
+	}
+
+	for _, res := range runAll(ctx, runs) {
+		p, updated, err := res.evaluate(opts)
+		if err != nil {
+			return rep, err
+		}
+		rep.Problems = append(rep.Problems, p...)
+		if updated != "" {
+			rep.Updated = append(rep.Updated, updated)
+		}
+	}
+	return rep, nil
+}
+
+// checkCourse adds the problems of a course that need no running to the
+// report and returns the code to run. File names in problems are made
+// relative to the content directory.
+func (rep *Report) checkCourse(c *compiler, store *data.Store) []runJob {
+	dir := store.Course.ID
+	for _, p := range CheckStore(store) {
+		p.Subject = "course " + dir + " " + p.Subject
+		rep.Problems = append(rep.Problems, p)
+	}
+	rep.addCoverage(store.Coverage())
+
+	var runs []runJob
+	for _, ch := range store.GetChapters() {
+		for _, summary := range ch.Lessons {
+			lesson, ok := store.GetLesson(summary.ID)
+			if !ok {
+				continue
+			}
+			rep.Lessons++
+			for i, ex := range lesson.CodeExamples {
+				subject := fmt.Sprintf("lesson %s example %d %q", lesson.ID, i+1, ex.Title)
+				file := path.Join(dir, ex.File)
+				switch ex.Check {
+				case models.CheckNone:
+					continue
+				case models.CheckCompile:
+					rep.Problems = append(rep.Problems, c.check(file, subject, ex.Code, false)...)
+				default:
+					runs = append(runs, runJob{
+						file: file, subject: subject, code: ex.Code,
+						golden: true, nondeterministic: ex.Nondeterministic,
+					})
+				}
+				rep.Examples++
+			}
+			if ex := lesson.Exercise; ex != nil {
+				// Starter code is meant to be finished by the learner, so
+				// unused imports and variables, and types the exercise asks
+				// for, are expected to be missing.
+				subject := fmt.Sprintf("lesson %s starter code", lesson.ID)
+				rep.Problems = append(rep.Problems, c.check(path.Join(dir, ex.StarterFile), subject, ex.StarterCode, true)...)
+				rep.Starters++
+			}
+			if quiz, ok := store.GetQuiz(lesson.ID); ok {
+				for _, q := range quiz.Questions {
+					if q.Kind() == models.QuestionOutput {
+						subject := fmt.Sprintf("course %s lesson %s question %s", dir, lesson.ID, q.ID)
+						runs = append(runs, runJob{subject: subject, code: q.Code})
+						rep.OutputQuestions++
+					}
+				}
+			}
+		}
+	}
+	return runs
+}
+
+// addCoverage adds a course's translation coverage to the report's.
+func (rep *Report) addCoverage(coverage []data.Coverage) {
+	for _, cov := range coverage {
+		i := slices.IndexFunc(rep.Coverage, func(c data.Coverage) bool { return c.Locale == cov.Locale })
+		if i < 0 {
+			rep.Coverage = append(rep.Coverage, data.Coverage{Locale: cov.Locale})
+			i = len(rep.Coverage) - 1
+		}
+		c := &rep.Coverage[i]
+		c.Translated += cov.Translated
+		c.Total += cov.Total
+		c.Lessons = append(c.Lessons, cov.Lessons...)
+	}
+}
+
+// CheckStore checks that the store's chapters, lessons and quizzes agree:
+// every lesson a chapter lists exists under the same title and has a quiz
+// with valid questions.
+func CheckStore(store *data.Store) []Problem {
+	var problems []Problem
+	add := func(subject, format string, args ...any) {
+		problems = append(problems, Problem{Subject: subject, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	seen := make(map[string]int)
+	for _, ch := range store.GetChapters() {
+		for _, summary := range ch.Lessons {
+			subject := "lesson " + summary.ID
+			if prev, dup := seen[summary.ID]; dup {
+				add(subject, "listed in chapter %d and chapter %d", prev, ch.ID)
+				continue
+			}
+			seen[summary.ID] = ch.ID
+
+			lesson, ok := store.GetLesson(summary.ID)
+			if !ok {
+				add(subject, "listed in chapter %d but does not exist", ch.ID)
+				continue
+			}
+			if lesson.ChapterID != ch.ID {
+				add(subject, "listed in chapter %d but belongs to chapter %d", ch.ID, lesson.ChapterID)
+			}
+			if lesson.Title != summary.Title {
+				add(subject, "title %q does not match %q in chapter %d", lesson.Title, summary.Title, ch.ID)
+			}
+
+			quiz, ok := store.GetQuiz(lesson.ID)
+			if !ok {
+				add(subject, "has no quiz")
+				continue
+			}
+			if len(quiz.Questions) == 0 {
+				add(subject, "quiz has no questions")
+			}
+			if quiz.Draw > len(quiz.Questions) {
+				add(subject, "quiz draws %d of %d questions", quiz.Draw, len(quiz.Questions))
+			}
+			for _, q := range quiz.Questions {
+				if lessonID, _ := store.QuestionLesson(q.ID); lessonID != lesson.ID {
+					add(subject, "question %s is also in lesson %s", q.ID, lessonID)
+				}
+				if _, msg := data.CheckQuestion(q); msg != "" {
+					add(subject, "question %s: %s", q.ID, msg)
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// compiler type-checks Go files. It is not safe for concurrent use.
+type compiler struct {
+	fset *token.FileSet
+	imp  types.Importer
+}
+
+func newCompiler() *compiler {
+	fset := token.NewFileSet()
+	// The source importer works without export data, which recent
+	// toolchains no longer ship for the standard library.
+	return &compiler{fset: fset, imp: importer.ForCompiler(fset, "source", nil)}
+}
+
+// check parses and type-checks code as a single-file package. With
+// partial, unused imports and variables and undefined names are not
+// reported.
+func (c *compiler) check(file, subject, code string, partial bool) []Problem {
+	var problems []Problem
+	report := func(pos token.Position, msg string) {
+		problems = append(problems, Problem{File: file, Line: pos.Line, Subject: subject, Msg: msg})
+	}
+
+	f, err := parser.ParseFile(c.fset, file, code, parser.AllErrors)
+	if err != nil {
+		var list scanner.ErrorList
+		if errors.As(err, &list) {
+			for _, e := range list {
+				report(e.Pos, e.Msg)
+			}
+		} else {
+			report(token.Position{}, err.Error())
+		}
+		return problems
+	}
+
+	// Only the standard library is available to learners, and resolving
+	// other imports would consult the app's own module.
+	for _, spec := range f.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		if first, _, _ := strings.Cut(p, "/"); strings.Contains(first, ".") {
+			report(c.fset.Position(spec.Pos()), fmt.Sprintf("imports %s, which is not in the standard library", p))
+		}
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	conf := types.Config{
+		Importer: c.imp,
+		Error: func(err error) {
+			var te types.Error
+			if !errors.As(err, &te) {
+				report(token.Position{}, err.Error())
+				return
+			}
+			if partial && (te.Soft || strings.HasPrefix(te.Msg, "undefined: ")) {
+				return
+			}
+			report(te.Fset.Position(te.Pos), te.Msg)
+		},
+	}
+	conf.Check(f.Name.Name, c.fset, []*ast.File{f}, nil)
+	return problems
+}
+
+// runJob is a program to run: an example, which is compared against its
+// golden output, or an output question, which only has to succeed. A
+// nondeterministic example only needs a golden output to exist.
+type runJob struct {
+	file, subject, code      string
+	golden, nondeterministic bool
+}
+
+type runResult struct {
+	runJob
+	res runner.Result
+	err error
+}
+
+// runAll runs the jobs in parallel and returns their results in order.
+func runAll(ctx context.Context, jobs []runJob) []runResult {
+	results := make([]runResult, len(jobs))
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			res, err := runner.Run(ctx, job.code)
+			results[i] = runResult{runJob: job, res: res, err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// evaluate turns a run into problems, comparing or, in update mode,
+// recording the golden output. It returns the golden file it wrote.
+func (r runResult) evaluate(opts Options) ([]Problem, string, error) {
+	fail := func(msg, detail string) ([]Problem, string, error) {
+		return []Problem{{File: r.file, Subject: r.subject, Msg: msg, Detail: detail}}, "", nil
+	}
+	if r.err != nil {
+		return fail(r.err.Error(), "")
+	}
+	if r.res.Failed {
+		return fail("does not run", r.res.Output)
+	}
+	if !r.golden {
+		return nil, "", nil
+	}
+
+	golden := data.GoldenFile(r.file)
+	goldenPath := filepath.Join(opts.Dir, filepath.FromSlash(golden))
+	if opts.Update {
+		if err := os.WriteFile(goldenPath, []byte(r.res.Output), 0o644); err != nil {
+			return nil, "", err
+		}
+		return nil, golden, nil
+	}
+	want, err := os.ReadFile(goldenPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fail(fmt.Sprintf("no golden output %s; record it with validate -update", golden), "")
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if string(want) != r.res.Output && !r.nondeterministic {
+		// Learners are shown the golden output, so it must follow both
+		// edits to the example and Go versions that print differently.
+		return fail(fmt.Sprintf("output differs from %s under %s; check it and record it with validate -update", golden, runtime.Version()),
+			diffLine(string(want), r.res.Output))
+	}
+	return nil, "", nil
+}
+
+// diffLine describes the first line where got differs from want.
+func diffLine(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < max(len(w), len(g)); i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl || i >= len(w) || i >= len(g) {
+			return fmt.Sprintf("line %d:\nwant: %q\ngot:  %q", i+1, wl, gl)
+		}
+	}
+	return ""
+}
+
+// unjoin splits an error made by errors.Join.
+func unjoin(err error) []error {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		return j.Unwrap()
+	}
+	return []error{err}
+}
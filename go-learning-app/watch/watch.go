@@ -0,0 +1,57 @@
+// Package watch detects changes to the files under a directory by polling,
+// which needs no platform-specific notification API and is cheap for a
+// directory the size of the course content.
+package watch
+
+import (
+	"context"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Poll calls changed each time the tree under dir differs from the last
+// poll: a file was added, removed, resized or modified. It blocks until ctx
+// is done. A burst of saves within one interval triggers a single call.
+func Poll(ctx context.Context, dir string, interval time.Duration, changed func()) {
+	last := fingerprint(dir)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		if fp := fingerprint(dir); fp != last {
+			last = fp
+			changed()
+		}
+	}
+}
+
+// fingerprint hashes the path, size and modification time of every file
+// under dir. Unreadable entries are skipped; a missing directory hashes
+// like an empty one.
+func fingerprint(dir string) uint64 {
+	h := fnv.New64a()
+	fs.WalkDir(os.DirFS(dir), ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		h.Write([]byte(path))
+		h.Write([]byte{0})
+		h.Write(strconv.AppendInt(nil, info.Size(), 10))
+		h.Write([]byte{0})
+		h.Write(strconv.AppendInt(nil, info.ModTime().UnixNano(), 10))
+		h.Write([]byte{0})
+		return nil
+	})
+	return h.Sum64()
+}